@@ -6,8 +6,12 @@ import (
 	"financial-api/internal/config"
 	"financial-api/internal/database"
 	"financial-api/internal/handlers"
+	"financial-api/internal/jobs"
+	"financial-api/internal/jwtkeys"
 	"financial-api/internal/logger"
+	"financial-api/internal/mailer"
 	"financial-api/internal/middleware"
+	"financial-api/internal/oidc"
 	"financial-api/internal/repositories"
 	"financial-api/internal/services"
 
@@ -40,6 +44,11 @@ func main() {
 	defer database.Disconnect(db)
 	logger.Logger.Info("Connected to MongoDB successfully")
 
+	// Move records to workspaces before the indexes on workspaceId are created
+	if err := database.MigrateWorkspaces(db); err != nil {
+		logger.Logger.Fatal("Failed to migrate records to workspaces", zap.Error(err))
+	}
+
 	// Create database indexes
 	if err := database.CreateIndexes(db); err != nil {
 		logger.Logger.Warn("Failed to create indexes", zap.Error(err))
@@ -48,9 +57,29 @@ func main() {
 	// Initialize repositories
 	transactionRepo := repositories.NewTransactionRepository(db)
 	investmentRepo := repositories.NewInvestmentRepository(db)
+	movementRepo := repositories.NewMovementRepository(db)
+	indexRepo := repositories.NewIndexRepository(db)
+	tradeRepo := repositories.NewTradeRepository(db)
+	quoteRepo := repositories.NewQuoteRepository(db)
+	dividendRepo := repositories.NewDividendRepository(db)
+	allocationRepo := repositories.NewAllocationRepository(db)
+	netWorthRepo := repositories.NewNetWorthRepository(db)
+	loanRepo := repositories.NewLoanRepository(db)
+	recurringRepo := repositories.NewRecurringRepository(db)
+	insightRepo := repositories.NewInsightRepository(db)
+	merchantRepo := repositories.NewMerchantRepository(db)
 	categoryRepo := repositories.NewCategoryRepository(db)
 	aggregationRepo := repositories.NewAggregationRepository(db)
 	userRepo := repositories.NewUserRepository(db)
+	sessionRepo := repositories.NewSessionRepository(db)
+	accountTokenRepo := repositories.NewAccountTokenRepository(db)
+	securityRepo := repositories.NewSecurityRepository(db)
+	userDataRepo := repositories.NewUserDataRepository(db)
+	exportRepo := repositories.NewExportRepository(db)
+	apiKeyRepo := repositories.NewAPIKeyRepository(db)
+	oidcStateRepo := repositories.NewOIDCStateRepository(db)
+	workspaceRepo := repositories.NewWorkspaceRepository(db)
+	invitationRepo := repositories.NewInvitationRepository(db)
 
 	// Seed default categories
 	if err := categoryRepo.SeedDefaultCategories(); err != nil {
@@ -59,16 +88,107 @@ func main() {
 		logger.Logger.Info("Categories seeded successfully")
 	}
 
+	// Without key files, access tokens are signed with HS256 and JWT_SECRET
+	signingKeys := jwtkeys.NewHMAC(cfg.JWTSecret)
+	if cfg.JWTKeysDir != "" {
+		signingKeys, err = jwtkeys.LoadDir(cfg.JWTKeysDir, cfg.JWTSigningKeyID)
+		if err != nil {
+			logger.Logger.Fatal("Failed to load JWT keys", zap.Error(err))
+		}
+	}
+
+	mail := mailer.New(cfg.MailDriver, mailer.SMTPConfig{
+		Host:     cfg.SMTPHost,
+		Port:     cfg.SMTPPort,
+		Username: cfg.SMTPUsername,
+		Password: cfg.SMTPPassword,
+		From:     cfg.MailFrom,
+	}, cfg.MailOutboxDir)
+
 	// Initialize services
-	transactionService := services.NewTransactionService(transactionRepo)
-	investmentService := services.NewInvestmentService(investmentRepo)
-	dashboardService := services.NewDashboardService(transactionRepo, investmentRepo, categoryRepo, aggregationRepo)
-	authService := services.NewAuthService(userRepo)
+	insightService := services.NewInsightService(insightRepo, transactionRepo, categoryRepo, workspaceRepo)
+	merchantService := services.NewMerchantService(merchantRepo, transactionRepo, aggregationRepo)
+	// Tag the transactions saved before merchants were tracked
+	if tagged, err := merchantService.BackfillMerchants(); err != nil {
+		logger.Logger.Warn("Failed to backfill transaction merchants", zap.Error(err))
+	} else if tagged > 0 {
+		logger.Logger.Info("Backfilled transaction merchants", zap.Int("transactions", tagged))
+	}
+	transactionService := services.NewTransactionService(transactionRepo, insightService, merchantService)
+	investmentService := services.NewInvestmentService(investmentRepo, movementRepo, indexRepo)
+	dashboardService := services.NewDashboardService(transactionRepo, investmentRepo, categoryRepo, aggregationRepo, investmentService)
+	securityService := services.NewSecurityService(securityRepo, userRepo, accountTokenRepo, mail, cfg.AppURL, services.LoginPolicy{
+		MaxFailures:   cfg.LoginMaxFailures,
+		IPMaxFailures: cfg.LoginIPMaxFailures,
+		Lockout:       cfg.LoginLockout,
+	})
+	twoFactorService := services.NewTwoFactorService(userRepo, sessionRepo, securityService, cfg.TOTPIssuer)
+	authService := services.NewAuthService(userRepo, sessionRepo, accountTokenRepo, apiKeyRepo, twoFactorService, securityService, services.TokenPolicy{
+		Keys:       signingKeys,
+		Issuer:     cfg.JWTIssuer,
+		Audience:   cfg.JWTAudience,
+		AccessTTL:  cfg.JWTExpiration,
+		RefreshTTL: cfg.RefreshTokenExpiration,
+	}, cfg.RequireEmailVerification)
+	var providers []*oidc.Provider
+	for _, provider := range cfg.OIDCProviders {
+		providers = append(providers, oidc.NewProvider(oidc.Config{
+			Name:         provider.Name,
+			Issuer:       provider.Issuer,
+			ClientID:     provider.ClientID,
+			ClientSecret: provider.ClientSecret,
+			RedirectURL:  cfg.OIDCRedirectURL,
+			Scopes:       provider.Scopes,
+		}))
+	}
+	oidcService := services.NewOIDCService(providers, oidcStateRepo, userRepo, authService, securityService)
+	apiKeyService := services.NewAPIKeyService(apiKeyRepo, userRepo)
+	workspaceService := services.NewWorkspaceService(workspaceRepo, invitationRepo, userRepo, userDataRepo, mail, cfg.AppURL, cfg.InvitationExpiration)
+	exportService := services.NewExportService(exportRepo, userRepo, categoryRepo, workspaceService, userDataRepo, cfg.ExportDir, cfg.ExportSigningKey, cfg.ExportRetention, cfg.ExportLinkTTL)
+	accountService := services.NewAccountService(userRepo, userDataRepo, exportService, workspaceService, accountTokenRepo, sessionRepo, apiKeyRepo, securityService, mail, cfg.AppURL, cfg.PasswordResetExpiration, cfg.EmailVerificationExpiration)
+	indexService := services.NewIndexService(indexRepo)
+	positionService := services.NewPositionService(tradeRepo, quoteRepo)
+	quoteService := services.NewQuoteService(quoteRepo)
+	dividendService := services.NewDividendService(dividendRepo, positionService)
+	performanceService := services.NewPerformanceService(investmentService, positionService, dividendService, indexRepo, quoteRepo)
+	allocationService := services.NewAllocationService(allocationRepo, aggregationRepo)
+	netWorthService := services.NewNetWorthService(netWorthRepo, workspaceRepo, transactionRepo, investmentService, positionService)
+	loanService := services.NewLoanService(loanRepo, transactionRepo)
+	recurringService := services.NewRecurringService(recurringRepo)
+	forecastService := services.NewForecastService(transactionRepo, aggregationRepo, recurringService, loanService, investmentService)
+	comparisonService := services.NewComparisonService(aggregationRepo)
+	subscriptionService := services.NewSubscriptionService(transactionRepo, recurringService)
+
+	// Import local index tables
+	if result, err := indexService.ImportDir(cfg.IndexDataDir); err != nil {
+		logger.Logger.Warn("Failed to import index tables", zap.Error(err))
+	} else if result.Imported > 0 {
+		logger.Logger.Info("Index tables imported", zap.Int("values", result.Imported))
+	}
+
+	if result, err := quoteService.ImportDir(cfg.QuoteDataDir); err != nil {
+		logger.Logger.Warn("Failed to import quote history", zap.Error(err))
+	} else if result.Imported > 0 {
+		logger.Logger.Info("Quote history imported", zap.Int("quotes", result.Imported))
+	}
+
+	// Start background jobs
+	if cfg.NetWorthSnapshotInterval > 0 {
+		go jobs.RunNetWorthSnapshots(netWorthService, cfg.NetWorthSnapshotInterval)
+	}
+	if cfg.InsightSweepInterval > 0 {
+		go jobs.RunInsightSweep(insightService, cfg.InsightSweepInterval)
+	}
+	if cfg.ExportCleanupInterval > 0 {
+		go jobs.RunExportCleanup(exportService, cfg.ExportCleanupInterval)
+	}
 
 	// Initialize handlers
-	h := handlers.NewHandlers(transactionService, investmentService, dashboardService)
-	authHandlers := handlers.NewAuthHandlers(authService)
-	authMiddleware := middleware.AuthMiddleware(authService)
+	h := handlers.NewHandlers(transactionService, investmentService, dashboardService, indexService, positionService, quoteService, dividendService, performanceService, allocationService, netWorthService, loanService, recurringService, forecastService, comparisonService, insightService, subscriptionService, merchantService, exportService, workspaceService)
+	authHandlers := handlers.NewAuthHandlers(authService, accountService, twoFactorService, securityService, apiKeyService, oidcService)
+	authMiddleware := middleware.AuthMiddleware(authService, apiKeyService)
+	workspaceMiddleware := middleware.Workspace(workspaceService)
+	operatorMiddleware := middleware.RequireOperator(cfg.OperatorToken)
 
 	// Setup router
 	r := gin.New()
@@ -87,7 +207,7 @@ func main() {
 	})
 
 	// Setup routes
-	h.SetupRoutes(r, authHandlers, authMiddleware)
+	h.SetupRoutes(r, authHandlers, authMiddleware, workspaceMiddleware, operatorMiddleware)
 
 	// Start server
 	logger.Logger.Info("Server starting", zap.String("port", cfg.Port))
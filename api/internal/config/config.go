@@ -18,8 +18,45 @@ type Config struct {
 	MongoURI string
 	
 	// Security
-	JWTSecret     string
-	JWTExpiration time.Duration
+	JWTSecret              string
+	JWTExpiration          time.Duration
+	RefreshTokenExpiration time.Duration
+	JWTKeysDir             string
+	JWTSigningKeyID        string
+	JWTIssuer              string
+	JWTAudience            string
+	
+	// Login throttling
+	LoginMaxFailures   int
+	LoginIPMaxFailures int
+	LoginLockout       time.Duration
+	
+	// Accounts
+	RequireEmailVerification    bool
+	PasswordResetExpiration     time.Duration
+	EmailVerificationExpiration time.Duration
+	InvitationExpiration        time.Duration
+	AppURL                      string
+	TOTPIssuer                  string
+	
+	// Mail ("smtp" or "file")
+	MailDriver    string
+	MailFrom      string
+	MailOutboxDir string
+	SMTPHost      string
+	SMTPPort      string
+	SMTPUsername  string
+	SMTPPassword  string
+	
+	// Identity providers (OpenID Connect)
+	OIDCProviders   []OIDCProvider
+	OIDCRedirectURL string
+	
+	// Data exports
+	ExportDir        string
+	ExportRetention  time.Duration
+	ExportLinkTTL    time.Duration
+	ExportSigningKey string
 	
 	// Rate Limiting
 	RateLimitEnabled bool
@@ -29,13 +66,35 @@ type Config struct {
 	// CORS
 	AllowedOrigins []string
 	
+	// Market data, shared by every user. Index and quote tables are loaded
+	// from these directories at startup, or uploaded with OperatorToken.
+	IndexDataDir  string
+	QuoteDataDir  string
+	OperatorToken string
+	
+	// Jobs
+	NetWorthSnapshotInterval time.Duration
+	InsightSweepInterval     time.Duration
+	ExportCleanupInterval    time.Duration
+	
 	// Features
 	EnableSwagger bool
 	EnableMetrics bool
 }
 
+// OIDCProvider is one provider of OIDC_PROVIDERS, configured by the
+// OIDC_<NAME>_* variables.
+type OIDCProvider struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+}
+
 func Load() *Config {
 	env := getEnv("GIN_MODE", "debug")
+	appURL := getEnv("APP_URL", "http://localhost:5173")
 	
 	return &Config{
 		// Server
@@ -47,8 +106,45 @@ func Load() *Config {
 		MongoURI: getEnv("MONGO_URI", getDefaultMongoURI(env)),
 		
 		// Security
-		JWTSecret:     getEnv("JWT_SECRET", getDefaultJWTSecret(env)),
-		JWTExpiration: getEnvDuration("JWT_EXPIRATION", 24*time.Hour),
+		JWTSecret:              getEnv("JWT_SECRET", getDefaultJWTSecret(env)),
+		JWTExpiration:          getEnvDuration("JWT_EXPIRATION", 15*time.Minute),
+		RefreshTokenExpiration: getEnvDuration("REFRESH_TOKEN_EXPIRATION", 30*24*time.Hour),
+		JWTKeysDir:             getEnv("JWT_KEYS_DIR", ""),
+		JWTSigningKeyID:        getEnv("JWT_SIGNING_KEY_ID", ""),
+		JWTIssuer:              getEnv("JWT_ISSUER", "financial-api"),
+		JWTAudience:            getEnv("JWT_AUDIENCE", "financial-api"),
+		
+		// Login throttling
+		LoginMaxFailures:   getEnvInt("LOGIN_MAX_FAILURES", 5),
+		LoginIPMaxFailures: getEnvInt("LOGIN_IP_MAX_FAILURES", getLoginIPMaxFailures(env)),
+		LoginLockout:       getEnvDuration("LOGIN_LOCKOUT", 15*time.Minute),
+		
+		// Accounts
+		RequireEmailVerification:    getEnvBool("REQUIRE_EMAIL_VERIFICATION", false),
+		PasswordResetExpiration:     getEnvDuration("PASSWORD_RESET_EXPIRATION", time.Hour),
+		EmailVerificationExpiration: getEnvDuration("EMAIL_VERIFICATION_EXPIRATION", 48*time.Hour),
+		InvitationExpiration:        getEnvDuration("INVITATION_EXPIRATION", 7*24*time.Hour),
+		AppURL:                      appURL,
+		TOTPIssuer:                  getEnv("TOTP_ISSUER", "Financeiro"),
+		
+		// Mail
+		MailDriver:    getEnv("MAIL_DRIVER", getDefaultMailDriver(env)),
+		MailFrom:      getEnv("MAIL_FROM", "no-reply@localhost"),
+		MailOutboxDir: getEnv("MAIL_OUTBOX_DIR", "data/outbox"),
+		SMTPHost:      getEnv("SMTP_HOST", ""),
+		SMTPPort:      getEnv("SMTP_PORT", "587"),
+		SMTPUsername:  getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:  getEnv("SMTP_PASSWORD", ""),
+		
+		// Identity providers
+		OIDCProviders:   getOIDCProviders(),
+		OIDCRedirectURL: getEnv("OIDC_REDIRECT_URL", appURL+"/auth/oidc/callback"),
+		
+		// Data exports
+		ExportDir:        getEnv("EXPORT_DIR", "data/exports"),
+		ExportRetention:  getEnvDuration("EXPORT_RETENTION", 24*time.Hour),
+		ExportLinkTTL:    getEnvDuration("EXPORT_LINK_TTL", time.Hour),
+		ExportSigningKey: getEnv("EXPORT_SIGNING_KEY", getDefaultExportSigningKey(env)),
 		
 		// Rate Limiting
 		RateLimitEnabled: getEnvBool("RATE_LIMIT_ENABLED", getRateLimitDefault(env)),
@@ -58,6 +154,16 @@ func Load() *Config {
 		// CORS
 		AllowedOrigins: getEnvSlice("ALLOWED_ORIGINS", getAllowedOrigins(env)),
 		
+		// Market data
+		IndexDataDir:  getEnv("INDEX_DATA_DIR", "data/indexes"),
+		QuoteDataDir:  getEnv("QUOTE_DATA_DIR", "data/quotes"),
+		OperatorToken: getEnv("OPERATOR_TOKEN", ""),
+		
+		// Jobs (0 disables a job)
+		NetWorthSnapshotInterval: getEnvDuration("NET_WORTH_SNAPSHOT_INTERVAL", 24*time.Hour),
+		InsightSweepInterval:     getEnvDuration("INSIGHT_SWEEP_INTERVAL", 24*time.Hour),
+		ExportCleanupInterval:    getEnvDuration("EXPORT_CLEANUP_INTERVAL", time.Hour),
+		
 		// Features
 		EnableSwagger: getEnvBool("ENABLE_SWAGGER", env != "release"),
 		EnableMetrics: getEnvBool("ENABLE_METRICS", true),
@@ -88,8 +194,9 @@ func getDefaultMongoURI(env string) string {
 func getDefaultJWTSecret(env string) string {
 	switch env {
 	case "release":
-		// In production, this MUST be set via environment variable
-		panic("JWT_SECRET must be set in production environment")
+		// In production, this MUST be set via environment variable unless
+		// JWT_KEYS_DIR is; Validate checks it
+		return ""
 	case "test":
 		return "test-jwt-secret-key-not-for-production"
 	default:
@@ -97,6 +204,60 @@ func getDefaultJWTSecret(env string) string {
 	}
 }
 
+func getDefaultExportSigningKey(env string) string {
+	switch env {
+	case "release":
+		// In production, this MUST be set via environment variable
+		return ""
+	case "test":
+		return "test-export-signing-key-not-for-production"
+	default:
+		return "dev-export-signing-key-change-in-production"
+	}
+}
+
+// getOIDCProviders reads the providers named in OIDC_PROVIDERS. The issuer of
+// "google" defaults to Google's.
+func getOIDCProviders() []OIDCProvider {
+	var providers []OIDCProvider
+	for _, name := range getEnvSlice("OIDC_PROVIDERS", nil) {
+		name = strings.ToLower(strings.TrimSpace(name))
+		prefix := "OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+	
+		defaultIssuer := ""
+		if name == "google" {
+			defaultIssuer = "https://accounts.google.com"
+		}
+	
+		providers = append(providers, OIDCProvider{
+			Name:         name,
+			Issuer:       getEnv(prefix+"ISSUER", defaultIssuer),
+			ClientID:     getEnv(prefix+"CLIENT_ID", ""),
+			ClientSecret: getEnv(prefix+"CLIENT_SECRET", ""),
+			Scopes:       strings.Fields(getEnv(prefix+"SCOPES", "openid email profile")),
+		})
+	}
+	return providers
+}
+
+func getDefaultMailDriver(env string) string {
+	switch env {
+	case "release":
+		return "smtp"
+	default:
+		return "file" // Written to MAIL_OUTBOX_DIR
+	}
+}
+
+func getLoginIPMaxFailures(env string) int {
+	switch env {
+	case "test":
+		return 10000 // Every test logs in from the same address
+	default:
+		return 50
+	}
+}
+
 func getRateLimitDefault(env string) bool {
 	switch env {
 	case "test":
@@ -185,13 +346,24 @@ func getEnvSlice(key string, defaultValue []string) []string {
 
 // Validation
 func (c *Config) Validate() error {
+	for _, provider := range c.OIDCProviders {
+		if provider.Issuer == "" || provider.ClientID == "" {
+			return fmt.Errorf("OIDC provider %q needs an issuer and a client ID", provider.Name)
+		}
+	}
 	if c.Environment == "release" {
-		if c.JWTSecret == "" {
-			return fmt.Errorf("JWT_SECRET is required in production")
+		if c.JWTSecret == "" && c.JWTKeysDir == "" {
+			return fmt.Errorf("JWT_SECRET or JWT_KEYS_DIR is required in production")
+		}
+		if c.ExportSigningKey == "" {
+			return fmt.Errorf("EXPORT_SIGNING_KEY is required in production")
 		}
 		if len(c.AllowedOrigins) == 0 {
 			return fmt.Errorf("ALLOWED_ORIGINS must be configured in production")
 		}
+		if c.MailDriver == "smtp" && c.SMTPHost == "" {
+			return fmt.Errorf("SMTP_HOST is required in production")
+		}
 	}
 	return nil
 }
\ No newline at end of file
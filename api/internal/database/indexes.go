@@ -41,10 +41,23 @@ func CreateIndexes(db *mongo.Database) error {
 		},
 		{
 			Keys: bson.D{
-				{Key: "userId", Value: 1},
+				{Key: "workspaceId", Value: 1},
 				{Key: "createdAt", Value: -1},
 			},
 		},
+		{
+			Keys: bson.D{
+				{Key: "workspaceId", Value: 1},
+				{Key: "type", Value: 1},
+				{Key: "date", Value: 1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "workspaceId", Value: 1},
+				{Key: "merchantKey", Value: 1},
+			},
+		},
 	}
 
 	if _, err := db.Collection("transactions").Indexes().CreateMany(ctx, transactionIndexes); err != nil {
@@ -71,7 +84,7 @@ func CreateIndexes(db *mongo.Database) error {
 		},
 		{
 			Keys: bson.D{
-				{Key: "userId", Value: 1},
+				{Key: "workspaceId", Value: 1},
 				{Key: "createdAt", Value: -1},
 			},
 		},
@@ -87,6 +100,427 @@ func CreateIndexes(db *mongo.Database) error {
 		return err
 	}
 
+	// Investment movements indexes
+	movementIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "investmentId", Value: 1},
+				{Key: "date", Value: 1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "workspaceId", Value: 1},
+				{Key: "date", Value: -1},
+			},
+		},
+	}
+
+	if _, err := db.Collection("investment_movements").Indexes().CreateMany(ctx, movementIndexes); err != nil {
+		logger.Logger.Error("Failed to create investment movement indexes", zap.Error(err))
+		return err
+	}
+
+	// Index values indexes
+	indexValueIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "index", Value: 1},
+				{Key: "date", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+
+	if _, err := db.Collection("index_values").Indexes().CreateMany(ctx, indexValueIndexes); err != nil {
+		logger.Logger.Error("Failed to create index value indexes", zap.Error(err))
+		return err
+	}
+
+	// Trades indexes
+	tradeIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "workspaceId", Value: 1},
+				{Key: "ticker", Value: 1},
+				{Key: "date", Value: 1},
+			},
+		},
+	}
+
+	if _, err := db.Collection("trades").Indexes().CreateMany(ctx, tradeIndexes); err != nil {
+		logger.Logger.Error("Failed to create trade indexes", zap.Error(err))
+		return err
+	}
+
+	// Quotes indexes
+	quoteIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "ticker", Value: 1},
+				{Key: "date", Value: -1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+
+	if _, err := db.Collection("quotes").Indexes().CreateMany(ctx, quoteIndexes); err != nil {
+		logger.Logger.Error("Failed to create quote indexes", zap.Error(err))
+		return err
+	}
+
+	// Dividends indexes
+	dividendIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "workspaceId", Value: 1},
+				{Key: "payDate", Value: -1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "workspaceId", Value: 1},
+				{Key: "ticker", Value: 1},
+			},
+		},
+	}
+
+	if _, err := db.Collection("dividends").Indexes().CreateMany(ctx, dividendIndexes); err != nil {
+		logger.Logger.Error("Failed to create dividend indexes", zap.Error(err))
+		return err
+	}
+
+	// Allocation targets indexes
+	allocationIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "workspaceId", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+
+	if _, err := db.Collection("allocation_targets").Indexes().CreateMany(ctx, allocationIndexes); err != nil {
+		logger.Logger.Error("Failed to create allocation target indexes", zap.Error(err))
+		return err
+	}
+
+	// Net worth indexes
+	netWorthItemIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "workspaceId", Value: 1},
+				{Key: "kind", Value: 1},
+			},
+		},
+	}
+
+	if _, err := db.Collection("net_worth_items").Indexes().CreateMany(ctx, netWorthItemIndexes); err != nil {
+		logger.Logger.Error("Failed to create net worth item indexes", zap.Error(err))
+		return err
+	}
+
+	netWorthSnapshotIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "workspaceId", Value: 1},
+				{Key: "date", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+
+	if _, err := db.Collection("net_worth_snapshots").Indexes().CreateMany(ctx, netWorthSnapshotIndexes); err != nil {
+		logger.Logger.Error("Failed to create net worth snapshot indexes", zap.Error(err))
+		return err
+	}
+
+	// Loans indexes
+	loanIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "workspaceId", Value: 1},
+				{Key: "startDate", Value: -1},
+			},
+		},
+	}
+
+	if _, err := db.Collection("loans").Indexes().CreateMany(ctx, loanIndexes); err != nil {
+		logger.Logger.Error("Failed to create loan indexes", zap.Error(err))
+		return err
+	}
+
+	// Recurring items indexes
+	recurringIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "workspaceId", Value: 1},
+				{Key: "startDate", Value: 1},
+			},
+		},
+	}
+
+	if _, err := db.Collection("recurring_items").Indexes().CreateMany(ctx, recurringIndexes); err != nil {
+		logger.Logger.Error("Failed to create recurring item indexes", zap.Error(err))
+		return err
+	}
+
+	// Insights indexes
+	insightIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "workspaceId", Value: 1},
+				{Key: "key", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "workspaceId", Value: 1},
+				{Key: "dismissed", Value: 1},
+				{Key: "date", Value: -1},
+			},
+		},
+	}
+
+	if _, err := db.Collection("insights").Indexes().CreateMany(ctx, insightIndexes); err != nil {
+		logger.Logger.Error("Failed to create insight indexes", zap.Error(err))
+		return err
+	}
+
+	// Merchants indexes
+	merchantIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "workspaceId", Value: 1},
+				{Key: "key", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "workspaceId", Value: 1},
+				{Key: "aliases", Value: 1},
+			},
+		},
+	}
+
+	if _, err := db.Collection("merchants").Indexes().CreateMany(ctx, merchantIndexes); err != nil {
+		logger.Logger.Error("Failed to create merchant indexes", zap.Error(err))
+		return err
+	}
+
+	// Sessions indexes
+	sessionIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "userId", Value: 1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "expiresAt", Value: 1},
+			},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	}
+
+	if _, err := db.Collection("sessions").Indexes().CreateMany(ctx, sessionIndexes); err != nil {
+		logger.Logger.Error("Failed to create session indexes", zap.Error(err))
+		return err
+	}
+
+	// Refresh tokens indexes
+	refreshTokenIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "tokenHash", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "expiresAt", Value: 1},
+			},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	}
+
+	if _, err := db.Collection("refresh_tokens").Indexes().CreateMany(ctx, refreshTokenIndexes); err != nil {
+		logger.Logger.Error("Failed to create refresh token indexes", zap.Error(err))
+		return err
+	}
+
+	// Account tokens indexes
+	accountTokenIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "tokenHash", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "userId", Value: 1},
+				{Key: "purpose", Value: 1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "expiresAt", Value: 1},
+			},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	}
+
+	if _, err := db.Collection("account_tokens").Indexes().CreateMany(ctx, accountTokenIndexes); err != nil {
+		logger.Logger.Error("Failed to create account token indexes", zap.Error(err))
+		return err
+	}
+
+	// Login throttles indexes
+	throttleIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "key", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "expiresAt", Value: 1},
+			},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	}
+
+	if _, err := db.Collection("login_throttles").Indexes().CreateMany(ctx, throttleIndexes); err != nil {
+		logger.Logger.Error("Failed to create login throttle indexes", zap.Error(err))
+		return err
+	}
+
+	// Security audit indexes
+	auditIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "userId", Value: 1},
+				{Key: "createdAt", Value: -1},
+			},
+		},
+	}
+
+	if _, err := db.Collection("security_audit").Indexes().CreateMany(ctx, auditIndexes); err != nil {
+		logger.Logger.Error("Failed to create security audit indexes", zap.Error(err))
+		return err
+	}
+
+	// Data export indexes
+	exportIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "userId", Value: 1},
+				{Key: "createdAt", Value: -1},
+			},
+		},
+		{
+			Keys: bson.D{{Key: "expiresAt", Value: 1}},
+		},
+	}
+
+	if _, err := db.Collection("data_exports").Indexes().CreateMany(ctx, exportIndexes); err != nil {
+		logger.Logger.Error("Failed to create data export indexes", zap.Error(err))
+		return err
+	}
+
+	// API key indexes
+	apiKeyIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "keyHash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "userId", Value: 1},
+				{Key: "createdAt", Value: -1},
+			},
+		},
+	}
+
+	if _, err := db.Collection("api_keys").Indexes().CreateMany(ctx, apiKeyIndexes); err != nil {
+		logger.Logger.Error("Failed to create API key indexes", zap.Error(err))
+		return err
+	}
+
+	// User identity indexes: one account per identity at a provider
+	identityIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "identities.provider", Value: 1},
+				{Key: "identities.subject", Value: 1},
+			},
+			Options: options.Index().
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"identities.subject": bson.M{"$exists": true}}),
+		},
+	}
+
+	if _, err := db.Collection("users").Indexes().CreateMany(ctx, identityIndexes); err != nil {
+		logger.Logger.Error("Failed to create user identity indexes", zap.Error(err))
+		return err
+	}
+
+	// OIDC sign in state indexes
+	oidcStateIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "stateHash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	}
+
+	if _, err := db.Collection("oidc_states").Indexes().CreateMany(ctx, oidcStateIndexes); err != nil {
+		logger.Logger.Error("Failed to create OIDC state indexes", zap.Error(err))
+		return err
+	}
+
+	// Workspace indexes: one membership per user and workspace
+	workspaceMemberIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "workspaceId", Value: 1}, {Key: "userId", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "userId", Value: 1}},
+		},
+	}
+
+	if _, err := db.Collection("workspace_members").Indexes().CreateMany(ctx, workspaceMemberIndexes); err != nil {
+		logger.Logger.Error("Failed to create workspace member indexes", zap.Error(err))
+		return err
+	}
+
+	invitationIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "tokenHash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "workspaceId", Value: 1}, {Key: "email", Value: 1}},
+		},
+		{
+			Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	}
+
+	if _, err := db.Collection("workspace_invitations").Indexes().CreateMany(ctx, invitationIndexes); err != nil {
+		logger.Logger.Error("Failed to create workspace invitation indexes", zap.Error(err))
+		return err
+	}
+
 	// Categories indexes
 	categoryIndexes := []mongo.IndexModel{
 		{
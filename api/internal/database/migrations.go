@@ -0,0 +1,103 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"financial-api/internal/logger"
+	"financial-api/internal/models"
+	"financial-api/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// MigrateWorkspaces moves the financial records from their user to the
+// user's personal workspace, which has the user's ID, and makes sure every
+// user has one. It is safe to run on every start and must run before
+// CreateIndexes, which replaces the userId indexes it drops.
+func MigrateWorkspaces(db *mongo.Database) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	for _, name := range repositories.WorkspaceCollections {
+		collection := db.Collection(name)
+		pending := bson.M{"userId": bson.M{"$exists": true}}
+
+		count, err := collection.CountDocuments(ctx, pending)
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			continue
+		}
+
+		if err := dropUserIndexes(ctx, collection); err != nil {
+			return err
+		}
+		update := mongo.Pipeline{
+			{{Key: "$set", Value: bson.M{"workspaceId": "$userId"}}},
+			{{Key: "$unset", Value: "userId"}},
+		}
+		if _, err := collection.UpdateMany(ctx, pending, update); err != nil {
+			return err
+		}
+		logger.Logger.Info("Moved records to workspaces", zap.String("collection", name), zap.Int64("records", count))
+	}
+
+	personal := mongo.Pipeline{
+		{{Key: "$project", Value: bson.M{
+			"name":      "$name",
+			"personal":  bson.M{"$literal": true},
+			"createdAt": "$$NOW",
+			"updatedAt": "$$NOW",
+		}}},
+		{{Key: "$merge", Value: bson.M{"into": "workspaces", "on": "_id", "whenMatched": "keepExisting"}}},
+	}
+	if _, err := db.Collection("users").Aggregate(ctx, personal); err != nil {
+		return err
+	}
+
+	owners := mongo.Pipeline{
+		{{Key: "$project", Value: bson.M{
+			"workspaceId": bson.M{"$toString": "$_id"},
+			"userId":      bson.M{"$toString": "$_id"},
+			"role":        bson.M{"$literal": models.RoleOwner},
+			"joinedAt":    "$$NOW",
+		}}},
+		{{Key: "$merge", Value: bson.M{"into": "workspace_members", "on": "_id", "whenMatched": "keepExisting"}}},
+	}
+	_, err := db.Collection("users").Aggregate(ctx, owners)
+	return err
+}
+
+// dropUserIndexes drops the indexes on userId, which CreateIndexes creates
+// again on workspaceId.
+func dropUserIndexes(ctx context.Context, collection *mongo.Collection) error {
+	cursor, err := collection.Indexes().List(ctx)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	var indexes []struct {
+		Name string `bson:"name"`
+		Key  bson.D `bson:"key"`
+	}
+	if err := cursor.All(ctx, &indexes); err != nil {
+		return err
+	}
+
+	for _, index := range indexes {
+		for _, key := range index.Key {
+			if key.Key == "userId" {
+				if _, err := collection.Indexes().DropOne(ctx, index.Name); err != nil {
+					return err
+				}
+				break
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package finance
+
+import (
+	"math"
+	"time"
+
+	"financial-api/internal/models"
+)
+
+// IndexedRate describes how an indexed investment is remunerated: IndexPercent
+// of the index plus an annual Spread, both in percent.
+type IndexedRate struct {
+	Indexer      string
+	IndexPercent float64
+	Spread       float64
+}
+
+// AccrualFactor returns the growth factor of an indexed rate over [from, to).
+// Values must be sorted by date. Daily indexes only publish on business days,
+// so compounding over the published values follows the business-day
+// convention; the spread accrues exponentially over business days / 252.
+func AccrualFactor(rate IndexedRate, values []models.IndexValue, from, to time.Time) float64 {
+	if !from.Before(to) {
+		return 1
+	}
+
+	percent := rate.IndexPercent
+	if percent == 0 {
+		percent = 100
+	}
+
+	var factor float64
+	if models.IsMonthlyIndex(rate.Indexer) {
+		factor = monthlyIndexFactor(values, percent, from, to)
+	} else {
+		factor = dailyIndexFactor(values, percent, from, to)
+	}
+
+	if rate.Spread != 0 {
+		du := BusinessDaysBetween(from, to)
+		factor *= math.Pow(1+rate.Spread/100, float64(du)/252)
+	}
+
+	return factor
+}
+
+// ProjectedFactor returns the growth factor of an indexed rate over [from, to)
+// assuming the index keeps its last published value, as a forecast of future
+// accrual. Without any published value only the spread accrues.
+func ProjectedFactor(rate IndexedRate, values []models.IndexValue, from, to time.Time) float64 {
+	if !from.Before(to) {
+		return 1
+	}
+
+	percent := rate.IndexPercent
+	if percent == 0 {
+		percent = 100
+	}
+
+	du := float64(BusinessDaysBetween(from, to))
+	factor := 1.0
+	if len(values) > 0 {
+		last := 1 + (values[len(values)-1].Value/100)*(percent/100)
+		if models.IsMonthlyIndex(rate.Indexer) {
+			// A monthly variation compounds twelve times over 252 business days
+			factor = math.Pow(last, 12*du/252)
+		} else {
+			factor = math.Pow(last, du)
+		}
+	}
+
+	if rate.Spread != 0 {
+		factor *= math.Pow(1+rate.Spread/100, du/252)
+	}
+
+	return factor
+}
+
+// FixedRateFactor compounds an annual rate over business days / 252.
+func FixedRateFactor(annualRate float64, from, to time.Time) float64 {
+	if !from.Before(to) {
+		return 1
+	}
+	du := BusinessDaysBetween(from, to)
+	return math.Pow(1+annualRate/100, float64(du)/252)
+}
+
+func dailyIndexFactor(values []models.IndexValue, percent float64, from, to time.Time) float64 {
+	factor := 1.0
+	for _, v := range values {
+		date, err := ParseDate(v.Date)
+		if err != nil || date.Before(truncateDay(from)) || !date.Before(truncateDay(to)) {
+			continue
+		}
+		factor *= 1 + (v.Value/100)*(percent/100)
+	}
+	return factor
+}
+
+// monthlyIndexFactor applies each monthly variation pro rata to the business
+// days held within that month.
+func monthlyIndexFactor(values []models.IndexValue, percent float64, from, to time.Time) float64 {
+	factor := 1.0
+	for _, v := range values {
+		date, err := ParseDate(v.Date)
+		if err != nil {
+			continue
+		}
+
+		monthStart := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+		monthEnd := monthStart.AddDate(0, 1, 0)
+
+		start, end := monthStart, monthEnd
+		if from.After(start) {
+			start = truncateDay(from)
+		}
+		if to.Before(end) {
+			end = truncateDay(to)
+		}
+		if !start.Before(end) {
+			continue
+		}
+
+		held := BusinessDaysBetween(start, end)
+		total := BusinessDaysBetween(monthStart, monthEnd)
+		if total == 0 || held == 0 {
+			continue
+		}
+
+		monthly := 1 + (v.Value/100)*(percent/100)
+		factor *= math.Pow(monthly, float64(held)/float64(total))
+	}
+	return factor
+}
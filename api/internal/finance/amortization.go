@@ -0,0 +1,96 @@
+package finance
+
+import (
+	"math"
+	"time"
+
+	"financial-api/internal/models"
+)
+
+// MonthlyRate converts an effective annual rate in percent to the equivalent
+// monthly rate, as a fraction.
+func MonthlyRate(annualRate float64) float64 {
+	return math.Pow(1+annualRate/100, 1.0/12) - 1
+}
+
+// Amortize builds the schedule of a loan under the SAC (constant amortization)
+// or Price (constant installment) system. Extra payments are applied after the
+// installment they are attached to; depending on their mode the following
+// installments are recomputed over the remaining term, or kept and the loan
+// ends earlier.
+func Amortize(system string, principal, annualRate float64, term int, start time.Time, extras []models.ExtraPayment) []models.LoanInstallment {
+	rate := MonthlyRate(annualRate)
+
+	extrasByInstallment := map[int][]models.ExtraPayment{}
+	for _, extra := range extras {
+		extrasByInstallment[extra.Installment] = append(extrasByInstallment[extra.Installment], extra)
+	}
+
+	balance := principal
+	amortization := principal / float64(term)
+	payment := priceInstallment(principal, rate, term)
+
+	installments := []models.LoanInstallment{}
+	for number := 1; number <= term && balance > 0.005; number++ {
+		interest := balance * rate
+		amortized := amortization
+		if system == models.AmortizationPrice {
+			amortized = payment - interest
+		}
+		if amortized > balance || number == term {
+			amortized = balance
+		}
+		balance -= amortized
+
+		installment := models.LoanInstallment{
+			Number:    number,
+			Date:      AddMonths(start, number).Format(DateLayout),
+			Payment:   roundCents(interest + amortized),
+			Interest:  roundCents(interest),
+			Principal: roundCents(amortized),
+		}
+
+		recompute := false
+		for _, extra := range extrasByInstallment[number] {
+			paid := math.Min(extra.Amount, balance)
+			installment.Extra += paid
+			balance -= paid
+			if extra.Mode == models.ExtraPaymentReduceInstallment {
+				recompute = true
+			}
+		}
+		if recompute && number < term {
+			amortization = balance / float64(term-number)
+			payment = priceInstallment(balance, rate, term-number)
+		}
+
+		installment.Extra = roundCents(installment.Extra)
+		installment.Balance = roundCents(balance)
+		installments = append(installments, installment)
+	}
+
+	return installments
+}
+
+// AddMonths adds months to a date, keeping the day of the month when possible
+// and falling back to the last day of shorter months.
+func AddMonths(date time.Time, months int) time.Time {
+	first := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, months, 0)
+	lastDay := first.AddDate(0, 1, -1).Day()
+	day := date.Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return time.Date(first.Year(), first.Month(), day, 0, 0, 0, 0, time.UTC)
+}
+
+func priceInstallment(principal, rate float64, term int) float64 {
+	if rate == 0 {
+		return principal / float64(term)
+	}
+	return principal * rate / (1 - math.Pow(1+rate, -float64(term)))
+}
+
+func roundCents(value float64) float64 {
+	return math.Round(value*100) / 100
+}
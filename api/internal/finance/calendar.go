@@ -0,0 +1,84 @@
+package finance
+
+import "time"
+
+// DateLayout is the layout used for every date stored by the API.
+const DateLayout = "2006-01-02"
+
+// ParseDate parses a date in the API layout.
+func ParseDate(value string) (time.Time, error) {
+	return time.Parse(DateLayout, value)
+}
+
+// IsBusinessDay reports whether t is a Brazilian national business day.
+func IsBusinessDay(t time.Time) bool {
+	if t.Weekday() == time.Saturday || t.Weekday() == time.Sunday {
+		return false
+	}
+	return !isHoliday(t)
+}
+
+// BusinessDaysBetween counts business days in [from, to), the convention used
+// by the "dias úteis / 252" accrual.
+func BusinessDaysBetween(from, to time.Time) int {
+	from, to = truncateDay(from), truncateDay(to)
+	days := 0
+	for d := from; d.Before(to); d = d.AddDate(0, 0, 1) {
+		if IsBusinessDay(d) {
+			days++
+		}
+	}
+	return days
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func isHoliday(t time.Time) bool {
+	month, day := t.Month(), t.Day()
+
+	// Fixed national holidays
+	switch {
+	case month == time.January && day == 1,
+		month == time.April && day == 21,
+		month == time.May && day == 1,
+		month == time.September && day == 7,
+		month == time.October && day == 12,
+		month == time.November && day == 2,
+		month == time.November && day == 15,
+		month == time.December && day == 25:
+		return true
+	case month == time.November && day == 20 && t.Year() >= 2024:
+		return true
+	}
+
+	// Movable holidays anchored on Easter
+	easter := easterSunday(t.Year())
+	d := truncateDay(t)
+	for _, offset := range []int{-48, -47, -2, 60} { // Carnival Monday/Tuesday, Good Friday, Corpus Christi
+		if d.Equal(easter.AddDate(0, 0, offset)) {
+			return true
+		}
+	}
+	return false
+}
+
+// easterSunday uses the anonymous Gregorian algorithm.
+func easterSunday(year int) time.Time {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,98 @@
+package finance
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+var ErrNoSolution = errors.New("xirr: no solution for the given cash flows")
+
+// CashFlow is an amount received (positive) or paid (negative) on a date.
+type CashFlow struct {
+	Date   time.Time
+	Amount float64
+}
+
+// XIRR returns the annualized internal rate of return of irregular cash flows,
+// as a fraction, using actual/365 day counting.
+func XIRR(flows []CashFlow) (float64, error) {
+	if len(flows) < 2 {
+		return 0, ErrNoSolution
+	}
+
+	var hasPositive, hasNegative bool
+	first := flows[0].Date
+	for _, f := range flows {
+		if f.Amount > 0 {
+			hasPositive = true
+		}
+		if f.Amount < 0 {
+			hasNegative = true
+		}
+		if f.Date.Before(first) {
+			first = f.Date
+		}
+	}
+	if !hasPositive || !hasNegative {
+		return 0, ErrNoSolution
+	}
+
+	npv := func(rate float64) float64 {
+		total := 0.0
+		for _, f := range flows {
+			years := f.Date.Sub(first).Hours() / 24 / 365
+			total += f.Amount / math.Pow(1+rate, years)
+		}
+		return total
+	}
+
+	// Newton-Raphson from 10%, with a numeric derivative
+	rate := 0.1
+	for i := 0; i < 100; i++ {
+		value := npv(rate)
+		if math.Abs(value) < 1e-7 {
+			return rate, nil
+		}
+		derivative := (npv(rate+1e-6) - value) / 1e-6
+		if derivative == 0 {
+			break
+		}
+		next := rate - value/derivative
+		if next <= -1 || math.IsNaN(next) || math.IsInf(next, 0) {
+			break
+		}
+		if math.Abs(next-rate) < 1e-10 {
+			return next, nil
+		}
+		rate = next
+	}
+
+	// Fall back to bisection over a wide bracket
+	low, high := -0.9999, 100.0
+	lowValue := npv(low)
+	if lowValue*npv(high) > 0 {
+		return 0, ErrNoSolution
+	}
+	for i := 0; i < 200; i++ {
+		mid := (low + high) / 2
+		midValue := npv(mid)
+		if math.Abs(midValue) < 1e-7 || (high-low)/2 < 1e-10 {
+			return mid, nil
+		}
+		if midValue*lowValue < 0 {
+			high = mid
+		} else {
+			low, lowValue = mid, midValue
+		}
+	}
+	return (low + high) / 2, nil
+}
+
+// Annualize converts a return over a number of calendar days to a yearly rate.
+func Annualize(periodReturn float64, days int) float64 {
+	if days <= 0 {
+		return 0
+	}
+	return math.Pow(1+periodReturn, 365/float64(days)) - 1
+}
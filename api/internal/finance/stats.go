@@ -0,0 +1,58 @@
+package finance
+
+import (
+	"math"
+	"sort"
+)
+
+// Median returns the middle value of the sample, or 0 when it is empty.
+func Median(sample []float64) float64 {
+	if len(sample) == 0 {
+		return 0
+	}
+
+	sorted := append([]float64(nil), sample...)
+	sort.Float64s(sorted)
+
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
+
+// MedianAbsoluteDeviation returns the median distance of the sample values to
+// the sample median.
+func MedianAbsoluteDeviation(sample []float64) float64 {
+	median := Median(sample)
+	deviations := make([]float64, len(sample))
+	for i, value := range sample {
+		deviations[i] = math.Abs(value - median)
+	}
+	return Median(deviations)
+}
+
+// RobustZScore scores how far a value is from the sample using the median and
+// the MAD (modified z-score, Iglewicz and Hoaglin). When more than half of the
+// sample is identical the MAD is zero and the mean absolute deviation is used
+// instead. It returns false when the sample has no spread at all.
+func RobustZScore(value float64, sample []float64) (float64, bool) {
+	if len(sample) == 0 {
+		return 0, false
+	}
+
+	median := Median(sample)
+	if mad := MedianAbsoluteDeviation(sample); mad > 0 {
+		return 0.6745 * (value - median) / mad, true
+	}
+
+	var meanDeviation float64
+	for _, v := range sample {
+		meanDeviation += math.Abs(v - median)
+	}
+	meanDeviation /= float64(len(sample))
+	if meanDeviation == 0 {
+		return 0, false
+	}
+	return (value - median) / (1.253314 * meanDeviation), true
+}
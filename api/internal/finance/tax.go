@@ -0,0 +1,96 @@
+package finance
+
+import "strings"
+
+// incomeTaxExemptTypes are investment types exempt from IR for individuals.
+var incomeTaxExemptTypes = map[string]bool{
+	"LCI":                   true,
+	"LCA":                   true,
+	"CRI":                   true,
+	"CRA":                   true,
+	"POUPANÇA":              true,
+	"POUPANCA":              true,
+	"DEBÊNTURE INCENTIVADA": true,
+	"DEBENTURE INCENTIVADA": true,
+}
+
+// iofExemptTypes are investment types that never pay IOF on redemption. Being
+// exempt from IR does not exempt a type from IOF: LCI and LCA redeemed within
+// 30 days pay it like any other fixed income.
+var iofExemptTypes = map[string]bool{
+	"POUPANÇA": true,
+	"POUPANCA": true,
+}
+
+// iofTable holds the IOF rate on earnings for redemptions on days 1 to 29.
+var iofTable = []float64{
+	96, 93, 90, 86, 83, 80, 76, 73, 70, 66,
+	63, 60, 56, 53, 50, 46, 43, 40, 36, 33,
+	30, 26, 23, 20, 16, 13, 10, 6, 3,
+}
+
+type TaxBreakdown struct {
+	IOF       float64
+	IncomeTax float64
+}
+
+// Total returns the sum of every tax due.
+func (t TaxBreakdown) Total() float64 {
+	return t.IOF + t.IncomeTax
+}
+
+// IncomeTaxRate returns the regressive IR rate, in percent, for a holding period in calendar days.
+func IncomeTaxRate(days int) float64 {
+	switch {
+	case days <= 180:
+		return 22.5
+	case days <= 360:
+		return 20
+	case days <= 720:
+		return 17.5
+	default:
+		return 15
+	}
+}
+
+// IOFRate returns the IOF rate, in percent of earnings, for a holding period in calendar days.
+func IOFRate(days int) float64 {
+	if days < 1 {
+		return 100
+	}
+	if days > len(iofTable) {
+		return 0
+	}
+	return iofTable[days-1]
+}
+
+// IsIncomeTaxExempt reports whether an investment type is exempt from IR.
+func IsIncomeTaxExempt(investmentType string) bool {
+	return incomeTaxExemptTypes[normalizeType(investmentType)]
+}
+
+// IsIOFExempt reports whether an investment type is exempt from IOF.
+func IsIOFExempt(investmentType string) bool {
+	return iofExemptTypes[normalizeType(investmentType)]
+}
+
+// RedemptionTax computes the taxes withheld when redeeming earnings held for
+// the given number of days. IOF is charged first and IR applies to what is left.
+func RedemptionTax(earnings float64, days int, investmentType string) TaxBreakdown {
+	if earnings <= 0 {
+		return TaxBreakdown{}
+	}
+
+	var taxes TaxBreakdown
+	if !IsIOFExempt(investmentType) {
+		taxes.IOF = earnings * IOFRate(days) / 100
+	}
+	if !IsIncomeTaxExempt(investmentType) {
+		taxes.IncomeTax = (earnings - taxes.IOF) * IncomeTaxRate(days) / 100
+	}
+	return taxes
+}
+
+func normalizeType(investmentType string) string {
+	return strings.ToUpper(strings.TrimSpace(investmentType))
+}
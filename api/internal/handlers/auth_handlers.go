@@ -1,7 +1,10 @@
 package handlers
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
 
 	"financial-api/internal/logger"
 	"financial-api/internal/models"
@@ -13,14 +16,31 @@ import (
 )
 
 type AuthHandlers struct {
-	authService *services.AuthService
-	validator   *validator.Validate
+	authService      *services.AuthService
+	accountService   *services.AccountService
+	twoFactorService *services.TwoFactorService
+	securityService  *services.SecurityService
+	apiKeyService    *services.APIKeyService
+	oidcService      *services.OIDCService
+	validator        *validator.Validate
 }
 
-func NewAuthHandlers(authService *services.AuthService) *AuthHandlers {
+func NewAuthHandlers(
+	authService *services.AuthService,
+	accountService *services.AccountService,
+	twoFactorService *services.TwoFactorService,
+	securityService *services.SecurityService,
+	apiKeyService *services.APIKeyService,
+	oidcService *services.OIDCService,
+) *AuthHandlers {
 	return &AuthHandlers{
-		authService: authService,
-		validator:   validator.New(),
+		authService:      authService,
+		accountService:   accountService,
+		twoFactorService: twoFactorService,
+		securityService:  securityService,
+		apiKeyService:    apiKeyService,
+		oidcService:      oidcService,
+		validator:        validator.New(),
 	}
 }
 
@@ -51,6 +71,14 @@ func (h *AuthHandlers) Register(c *gin.Context) {
 		zap.String("email", response.User.Email),
 	)
 
+	// The account exists either way; the link can be sent again
+	if err := h.accountService.SendVerification(&response.User); err != nil {
+		logger.Logger.Error("Failed to send verification email",
+			zap.Error(err),
+			zap.String("user_id", response.User.ID.Hex()),
+		)
+	}
+
 	c.JSON(http.StatusCreated, response)
 }
 
@@ -66,8 +94,23 @@ func (h *AuthHandlers) Login(c *gin.Context) {
 		return
 	}
 
-	response, err := h.authService.Login(&req)
+	response, err := h.authService.Login(&req, c.ClientIP())
 	if err != nil {
+		if errors.Is(err, services.ErrEmailNotVerified) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		var throttle *services.ThrottleError
+		if errors.As(err, &throttle) {
+			logger.Logger.Warn("Throttled login attempt",
+				zap.Bool("locked", throttle.Locked),
+				zap.String("email", req.Email),
+				zap.String("ip", c.ClientIP()),
+			)
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttle.RetryAfter.Seconds()))))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+			return
+		}
 		logger.Logger.Warn("Failed login attempt", 
 			zap.Error(err),
 			zap.String("email", req.Email),
@@ -76,6 +119,11 @@ func (h *AuthHandlers) Login(c *gin.Context) {
 		return
 	}
 
+	if response.TwoFactorRequired {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
 	logger.Logger.Info("User logged in successfully", 
 		zap.String("user_id", response.User.ID.Hex()),
 		zap.String("email", response.User.Email),
@@ -84,6 +132,512 @@ func (h *AuthHandlers) Login(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+func (h *AuthHandlers) Refresh(c *gin.Context) {
+	var req models.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+		return
+	}
+
+	response, err := h.authService.Refresh(req.RefreshToken)
+	if err != nil {
+		if errors.Is(err, services.ErrRefreshTokenReused) {
+			logger.Logger.Warn("Refresh token reused, session revoked",
+				zap.String("ip", c.ClientIP()),
+			)
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, services.ErrInvalidRefreshToken) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		logger.Logger.Error("Failed to refresh token", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *AuthHandlers) Logout(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if err := h.authService.Logout(c.GetString("session_id"), userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Logger.Info("User logged out",
+		zap.String("user_id", userID),
+	)
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *AuthHandlers) LogoutAll(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if err := h.authService.LogoutAll(userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Logger.Info("User logged out of every session",
+		zap.String("user_id", userID),
+	)
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *AuthHandlers) UpdateMe(c *gin.Context) {
+	var req models.UpdateProfileRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	user, err := h.accountService.UpdateProfile(c.GetString("user_id"), c.GetString("session_id"), &req, c.ClientIP())
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidPassword), errors.Is(err, services.ErrReauthRequired):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrEmailTaken):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+func (h *AuthHandlers) RequestDeletion(c *gin.Context) {
+	var req models.PasswordRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	confirmation, err := h.accountService.RequestDeletion(c.GetString("user_id"), c.GetString("session_id"), req.Password)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidPassword) || errors.Is(err, services.ErrReauthRequired) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, services.ErrSharedWorkspaceOwner) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, confirmation)
+}
+
+func (h *AuthHandlers) DeleteMe(c *gin.Context) {
+	var req models.DeleteAccountRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if err := h.accountService.DeleteAccount(userID, req.ConfirmationToken); err != nil {
+		if errors.Is(err, services.ErrInvalidAccountToken) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, services.ErrSharedWorkspaceOwner) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		logger.Logger.Error("Failed to delete account",
+			zap.Error(err),
+			zap.String("user_id", userID),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Logger.Info("Account deleted",
+		zap.String("user_id", userID),
+	)
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *AuthHandlers) ForgotPassword(c *gin.Context) {
+	var req models.EmailRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	if err := h.accountService.RequestPasswordReset(req.Email); err != nil {
+		logger.Logger.Error("Failed to send password reset email", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send email"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "If the account exists, a reset link was sent"})
+}
+
+func (h *AuthHandlers) ResetPassword(c *gin.Context) {
+	var req models.ResetPasswordRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	if err := h.accountService.ResetPassword(req.Token, req.Password, c.ClientIP()); err != nil {
+		if errors.Is(err, services.ErrInvalidAccountToken) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *AuthHandlers) VerifyEmail(c *gin.Context) {
+	var req models.TokenRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	if err := h.accountService.VerifyEmail(req.Token); err != nil {
+		if errors.Is(err, services.ErrInvalidAccountToken) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *AuthHandlers) UnlockAccount(c *gin.Context) {
+	var req models.TokenRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	if err := h.securityService.Unlock(req.Token, c.ClientIP()); err != nil {
+		if errors.Is(err, services.ErrInvalidAccountToken) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *AuthHandlers) SecurityEvents(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 200 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+		return
+	}
+
+	events, err := h.securityService.GetEvents(c.GetString("user_id"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+// JWKS publishes the public keys of access tokens for other services to
+// verify them.
+func (h *AuthHandlers) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.authService.JWKS())
+}
+
+func (h *AuthHandlers) CreateAPIKey(c *gin.Context) {
+	var req models.CreateAPIKeyRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	key, err := h.apiKeyService.Create(c.GetString("user_id"), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Logger.Info("API key created",
+		zap.String("user_id", key.UserID),
+		zap.String("api_key_id", key.ID.Hex()),
+		zap.Strings("scopes", key.Scopes))
+
+	c.JSON(http.StatusCreated, key)
+}
+
+func (h *AuthHandlers) APIKeys(c *gin.Context) {
+	keys, err := h.apiKeyService.GetAPIKeys(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, keys)
+}
+
+func (h *AuthHandlers) RevokeAPIKey(c *gin.Context) {
+	if err := h.apiKeyService.Revoke(c.Param("id"), c.GetString("user_id")); err != nil {
+		if errors.Is(err, services.ErrAPIKeyNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *AuthHandlers) ResendVerification(c *gin.Context) {
+	var req models.EmailRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	if err := h.accountService.ResendVerification(req.Email); err != nil {
+		logger.Logger.Error("Failed to send verification email", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send email"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "If the account needs it, a verification link was sent"})
+}
+
+func (h *AuthHandlers) VerifyTwoFactor(c *gin.Context) {
+	var req models.TwoFactorLoginRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	response, err := h.authService.VerifyTwoFactor(req.ChallengeToken, req.Code, c.ClientIP())
+	if err != nil {
+		var throttle *services.ThrottleError
+		if errors.As(err, &throttle) {
+			logger.Logger.Warn("Throttled two-factor attempt",
+				zap.String("ip", c.ClientIP()),
+			)
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttle.RetryAfter.Seconds()))))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, services.ErrInvalidChallenge) || errors.Is(err, services.ErrInvalidTwoFactorCode) {
+			logger.Logger.Warn("Failed two-factor attempt",
+				zap.Error(err),
+				zap.String("ip", c.ClientIP()),
+			)
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Logger.Info("User logged in successfully",
+		zap.String("user_id", response.User.ID.Hex()),
+		zap.String("email", response.User.Email),
+	)
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *AuthHandlers) EnrollTwoFactor(c *gin.Context) {
+	enrollment, err := h.twoFactorService.Enroll(c.GetString("user_id"))
+	if err != nil {
+		h.twoFactorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, enrollment)
+}
+
+func (h *AuthHandlers) ConfirmTwoFactor(c *gin.Context) {
+	var req models.TwoFactorCodeRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	codes, err := h.twoFactorService.Confirm(c.GetString("user_id"), req.Code)
+	if err != nil {
+		h.twoFactorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+func (h *AuthHandlers) DisableTwoFactor(c *gin.Context) {
+	var req models.DisableTwoFactorRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	if err := h.twoFactorService.Disable(c.GetString("user_id"), c.GetString("session_id"), req.Password, req.Code, c.ClientIP()); err != nil {
+		h.twoFactorError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *AuthHandlers) RegenerateRecoveryCodes(c *gin.Context) {
+	var req models.TwoFactorCodeRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	codes, err := h.twoFactorService.RegenerateRecoveryCodes(c.GetString("user_id"), req.Code, c.ClientIP())
+	if err != nil {
+		h.twoFactorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+func (h *AuthHandlers) twoFactorError(c *gin.Context, err error) {
+	var throttle *services.ThrottleError
+	switch {
+	case errors.As(err, &throttle):
+		logger.Logger.Warn("Throttled two-factor attempt",
+			zap.String("user_id", c.GetString("user_id")),
+			zap.String("ip", c.ClientIP()),
+		)
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttle.RetryAfter.Seconds()))))
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidPassword), errors.Is(err, services.ErrReauthRequired):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrTwoFactorEnabled), errors.Is(err, services.ErrTwoFactorDisabled):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrTwoFactorNotEnrolled), errors.Is(err, services.ErrInvalidTwoFactorCode):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func (h *AuthHandlers) OIDCProviders(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"providers": h.oidcService.Providers()})
+}
+
+// OIDCAuthorize starts a sign in at the provider. The client sends the user to
+// the returned URL and posts the code and state it gets back to OIDCCallback.
+func (h *AuthHandlers) OIDCAuthorize(c *gin.Context) {
+	authorization, err := h.oidcService.Authorize(c.Param("provider"), "")
+	if err != nil {
+		h.oidcError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, authorization)
+}
+
+func (h *AuthHandlers) OIDCCallback(c *gin.Context) {
+	var req models.OIDCCallbackRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	response, err := h.oidcService.Login(&req, c.ClientIP())
+	if err != nil {
+		h.oidcError(c, err)
+		return
+	}
+
+	if !response.TwoFactorRequired {
+		logger.Logger.Info("User logged in with identity provider",
+			zap.String("user_id", response.User.ID.Hex()),
+			zap.String("email", response.User.Email),
+		)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// OIDCLinkAuthorize starts linking the provider to the signed in user, who
+// finishes it with OIDCLink.
+func (h *AuthHandlers) OIDCLinkAuthorize(c *gin.Context) {
+	authorization, err := h.oidcService.Authorize(c.Param("provider"), c.GetString("user_id"))
+	if err != nil {
+		h.oidcError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, authorization)
+}
+
+func (h *AuthHandlers) OIDCLink(c *gin.Context) {
+	var req models.OIDCCallbackRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	user, err := h.oidcService.Link(&req, c.GetString("user_id"), c.ClientIP())
+	if err != nil {
+		h.oidcError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+func (h *AuthHandlers) OIDCUnlink(c *gin.Context) {
+	user, err := h.oidcService.Unlink(c.Param("provider"), c.GetString("user_id"), c.ClientIP())
+	if err != nil {
+		h.oidcError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+func (h *AuthHandlers) oidcError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrUnknownProvider), errors.Is(err, services.ErrIdentityNotLinked):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidOIDCState):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrOIDCLoginFailed):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrProviderEmailNotVerified):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrIdentityLinked), errors.Is(err, services.ErrProviderAlreadyLinked), errors.Is(err, services.ErrLastSignInMethod),
+		errors.Is(err, services.ErrAccountEmailNotVerified):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// bind decodes and validates the JSON body, answering 400 when it fails.
+func (h *AuthHandlers) bind(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
+		return false
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+		return false
+	}
+	return true
+}
+
 func (h *AuthHandlers) Me(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
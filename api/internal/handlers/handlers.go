@@ -1,11 +1,14 @@
 package handlers
 
 import (
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
 
 	"financial-api/internal/logger"
 	"financial-api/internal/models"
+	"financial-api/internal/repositories"
 	"financial-api/internal/services"
 
 	"github.com/gin-gonic/gin"
@@ -16,20 +19,68 @@ import (
 var validate = validator.New()
 
 type Handlers struct {
-	transactionService *services.TransactionService
-	investmentService  *services.InvestmentService
-	dashboardService   *services.DashboardService
+	transactionService  *services.TransactionService
+	investmentService   *services.InvestmentService
+	dashboardService    *services.DashboardService
+	indexService        *services.IndexService
+	positionService     *services.PositionService
+	quoteService        *services.QuoteService
+	dividendService     *services.DividendService
+	performanceService  *services.PerformanceService
+	allocationService   *services.AllocationService
+	netWorthService     *services.NetWorthService
+	loanService         *services.LoanService
+	recurringService    *services.RecurringService
+	forecastService     *services.ForecastService
+	comparisonService   *services.ComparisonService
+	insightService      *services.InsightService
+	subscriptionService *services.SubscriptionService
+	merchantService     *services.MerchantService
+	exportService       *services.ExportService
+	workspaceService    *services.WorkspaceService
 }
 
 func NewHandlers(
 	transactionService *services.TransactionService,
 	investmentService *services.InvestmentService,
 	dashboardService *services.DashboardService,
+	indexService *services.IndexService,
+	positionService *services.PositionService,
+	quoteService *services.QuoteService,
+	dividendService *services.DividendService,
+	performanceService *services.PerformanceService,
+	allocationService *services.AllocationService,
+	netWorthService *services.NetWorthService,
+	loanService *services.LoanService,
+	recurringService *services.RecurringService,
+	forecastService *services.ForecastService,
+	comparisonService *services.ComparisonService,
+	insightService *services.InsightService,
+	subscriptionService *services.SubscriptionService,
+	merchantService *services.MerchantService,
+	exportService *services.ExportService,
+	workspaceService *services.WorkspaceService,
 ) *Handlers {
 	return &Handlers{
-		transactionService: transactionService,
-		investmentService:  investmentService,
-		dashboardService:   dashboardService,
+		transactionService:  transactionService,
+		investmentService:   investmentService,
+		dashboardService:    dashboardService,
+		indexService:        indexService,
+		positionService:     positionService,
+		quoteService:        quoteService,
+		dividendService:     dividendService,
+		performanceService:  performanceService,
+		allocationService:   allocationService,
+		netWorthService:     netWorthService,
+		loanService:         loanService,
+		recurringService:    recurringService,
+		forecastService:     forecastService,
+		comparisonService:   comparisonService,
+		insightService:      insightService,
+		subscriptionService: subscriptionService,
+		merchantService:     merchantService,
+		exportService:       exportService,
+		workspaceService:    workspaceService,
 	}
 }
 
@@ -37,13 +88,13 @@ func NewHandlers(
 
 // Transaction handlers
 func (h *Handlers) getTransactions(c *gin.Context) {
-	userID := c.GetString("user_id")
+	workspaceID := c.GetString("workspace_id")
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "15"))
 	search := c.Query("search")
 	transactionType := c.DefaultQuery("type", "all")
 
-	response, err := h.transactionService.GetTransactionsPaginated(page, limit, search, transactionType, userID)
+	response, err := h.transactionService.GetTransactionsPaginated(page, limit, search, transactionType, workspaceID)
 	if err != nil {
 		logger.Logger.Error("Failed to get transactions", 
 			zap.Error(err),
@@ -60,7 +111,8 @@ func (h *Handlers) getTransactions(c *gin.Context) {
 }
 
 func (h *Handlers) createTransaction(c *gin.Context) {
-	userID := c.GetString("user_id")
+	workspaceID := c.GetString("workspace_id")
+	role := c.GetString("workspace_role")
 	var req models.CreateTransactionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
@@ -80,7 +132,10 @@ func (h *Handlers) createTransaction(c *gin.Context) {
 		CategoryID:  req.CategoryID,
 	}
 
-	if err := h.transactionService.CreateTransaction(&transaction, userID); err != nil {
+	if err := h.transactionService.CreateTransaction(&transaction, workspaceID, role); err != nil {
+		if forbidden(c, err) {
+			return
+		}
 		logger.Logger.Error("Failed to create transaction", 
 			zap.Error(err),
 			zap.String("type", transaction.Type),
@@ -101,12 +156,12 @@ func (h *Handlers) createTransaction(c *gin.Context) {
 
 // Investment handlers
 func (h *Handlers) getInvestments(c *gin.Context) {
-	userID := c.GetString("user_id")
+	workspaceID := c.GetString("workspace_id")
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 	search := c.Query("search")
 
-	response, err := h.investmentService.GetInvestmentsPaginated(page, limit, search, userID)
+	response, err := h.investmentService.GetInvestmentsPaginated(page, limit, search, workspaceID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -116,7 +171,8 @@ func (h *Handlers) getInvestments(c *gin.Context) {
 }
 
 func (h *Handlers) createInvestment(c *gin.Context) {
-	userID := c.GetString("user_id")
+	workspaceID := c.GetString("workspace_id")
+	role := c.GetString("workspace_role")
 	var req models.CreateInvestmentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
@@ -129,14 +185,26 @@ func (h *Handlers) createInvestment(c *gin.Context) {
 	}
 
 	investment := models.Investment{
-		Name:   req.Name,
-		Amount: req.Amount,
-		Rate:   req.Rate,
-		Date:   req.Date,
-		Type:   req.Type,
+		Name:         req.Name,
+		Amount:       req.Amount,
+		Rate:         req.Rate,
+		Date:         req.Date,
+		Type:         req.Type,
+		Indexer:      req.Indexer,
+		IndexPercent: req.IndexPercent,
+		Spread:       req.Spread,
+		MaturityDate: req.MaturityDate,
+		Liquidity:    req.Liquidity,
 	}
 
-	if err := h.investmentService.CreateInvestment(&investment, userID); err != nil {
+	if err := h.investmentService.CreateInvestment(&investment, workspaceID, role); err != nil {
+		if forbidden(c, err) {
+			return
+		}
+		if errors.Is(err, services.ErrInvalidDate) || errors.Is(err, services.ErrInvalidMaturity) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -144,38 +212,1339 @@ func (h *Handlers) createInvestment(c *gin.Context) {
 	c.JSON(http.StatusCreated, investment)
 }
 
-// Category handlers
-func (h *Handlers) getCategories(c *gin.Context) {
-	userID := c.GetString("user_id")
-	summary, err := h.dashboardService.GetSummary(userID)
+func (h *Handlers) getInvestmentMovements(c *gin.Context) {
+	workspaceID := c.GetString("workspace_id")
+	ledger, err := h.investmentService.GetLedger(c.Param("id"), workspaceID)
 	if err != nil {
+		if errors.Is(err, services.ErrInvestmentNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, summary.Categories)
+	c.JSON(http.StatusOK, ledger)
 }
 
-// Dashboard handlers
-func (h *Handlers) getDashboardSummary(c *gin.Context) {
-	userID := c.GetString("user_id")
-	summary, err := h.dashboardService.GetSummary(userID)
+func (h *Handlers) createInvestmentMovement(c *gin.Context) {
+	workspaceID := c.GetString("workspace_id")
+	role := c.GetString("workspace_role")
+	var req models.CreateMovementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
+		return
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+		return
+	}
+
+	movement := models.InvestmentMovement{
+		Type:        req.Type,
+		Amount:      req.Amount,
+		Date:        req.Date,
+		Description: req.Description,
+	}
+
+	ledger, err := h.investmentService.AddMovement(c.Param("id"), &movement, workspaceID, role)
+	if err != nil {
+		if forbidden(c, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, services.ErrInvestmentNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrInsufficientBalance):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			logger.Logger.Error("Failed to create investment movement",
+				zap.Error(err),
+				zap.String("investment_id", c.Param("id")),
+				zap.String("type", movement.Type),
+			)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, ledger)
+}
+
+func (h *Handlers) getInvestmentValuation(c *gin.Context) {
+	workspaceID := c.GetString("workspace_id")
+	valuation, err := h.investmentService.GetValuation(c.Param("id"), c.Query("date"), workspaceID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvestmentNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrInvalidDate):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, valuation)
+}
+
+func (h *Handlers) getLiquidity(c *gin.Context) {
+	workspaceID := c.GetString("workspace_id")
+	days, err := strconv.Atoi(c.DefaultQuery("days", "365"))
+	if err != nil || days < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid days"})
+		return
+	}
+
+	report, err := h.investmentService.GetLiquidity(workspaceID, days)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, summary)
+	c.JSON(http.StatusOK, report)
 }
 
-// Overview handlers
-func (h *Handlers) getOverview(c *gin.Context) {
-	userID := c.GetString("user_id")
-	overview, err := h.dashboardService.GetOverview(userID)
+// Index handlers
+func (h *Handlers) getIndexValues(c *gin.Context) {
+	values, err := h.indexService.GetValues(c.Param("index"), c.Query("from"), c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, values)
+}
+
+func (h *Handlers) importIndexValues(c *gin.Context) {
+	body, closeBody, err := csvUpload(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid file"})
+		return
+	}
+	defer closeBody()
+
+	result, err := h.indexService.ImportCSV(body, c.Query("index"))
+	if err != nil {
+		logger.Logger.Error("Failed to import index values",
+			zap.Error(err),
+			zap.String("index", c.Query("index")),
+		)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Logger.Info("Index values imported",
+		zap.Int("imported", result.Imported),
+		zap.Int("skipped", result.Skipped),
+	)
+
+	c.JSON(http.StatusOK, result)
+}
+
+// Variable income handlers
+func (h *Handlers) createTrade(c *gin.Context) {
+	workspaceID := c.GetString("workspace_id")
+	role := c.GetString("workspace_role")
+	var req models.CreateTradeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
+		return
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+		return
+	}
+
+	trade := models.Trade{
+		Ticker:     req.Ticker,
+		AssetClass: req.AssetClass,
+		Side:       req.Side,
+		Quantity:   req.Quantity,
+		Price:      req.Price,
+		Fees:       req.Fees,
+		Date:       req.Date,
+	}
+
+	if err := h.positionService.CreateTrade(&trade, workspaceID, role); err != nil {
+		if forbidden(c, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, services.ErrInvalidDate), errors.Is(err, services.ErrInsufficientQuantity):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			logger.Logger.Error("Failed to create trade",
+				zap.Error(err),
+				zap.String("ticker", trade.Ticker),
+				zap.String("side", trade.Side),
+			)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, trade)
+}
+
+func (h *Handlers) getTrades(c *gin.Context) {
+	workspaceID := c.GetString("workspace_id")
+	trades, err := h.positionService.GetTrades(workspaceID, c.Query("ticker"))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, overview)
-}
\ No newline at end of file
+	c.JSON(http.StatusOK, trades)
+}
+
+func (h *Handlers) getPositions(c *gin.Context) {
+	workspaceID := c.GetString("workspace_id")
+	positions, err := h.positionService.GetPositions(workspaceID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, positions)
+}
+
+func (h *Handlers) getPosition(c *gin.Context) {
+	workspaceID := c.GetString("workspace_id")
+	position, err := h.positionService.GetPosition(workspaceID, c.Param("ticker"))
+	if err != nil {
+		if errors.Is(err, services.ErrPositionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, position)
+}
+
+func (h *Handlers) getQuotes(c *gin.Context) {
+	quotes, err := h.quoteService.GetQuotes(c.Param("ticker"), c.Query("from"), c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, quotes)
+}
+
+func (h *Handlers) importQuotes(c *gin.Context) {
+	body, closeBody, err := csvUpload(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid file"})
+		return
+	}
+	defer closeBody()
+
+	result, err := h.quoteService.ImportCSV(body, c.Query("ticker"))
+	if err != nil {
+		logger.Logger.Error("Failed to import quotes",
+			zap.Error(err),
+			zap.String("ticker", c.Query("ticker")),
+		)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Logger.Info("Quotes imported",
+		zap.Int("imported", result.Imported),
+		zap.Int("skipped", result.Skipped),
+	)
+
+	c.JSON(http.StatusOK, result)
+}
+
+// Dividend handlers
+func (h *Handlers) createDividend(c *gin.Context) {
+	workspaceID := c.GetString("workspace_id")
+	role := c.GetString("workspace_role")
+	var req models.CreateDividendRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
+		return
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+		return
+	}
+
+	dividend := models.Dividend{
+		Ticker:      req.Ticker,
+		Kind:        req.Kind,
+		GrossAmount: req.GrossAmount,
+		PayDate:     req.PayDate,
+	}
+
+	if err := h.dividendService.CreateDividend(&dividend, req.WithholdingTax, workspaceID, role); err != nil {
+		if forbidden(c, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, services.ErrInvalidDate), errors.Is(err, services.ErrInvalidWithholding):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrPositionNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			logger.Logger.Error("Failed to create dividend",
+				zap.Error(err),
+				zap.String("ticker", dividend.Ticker),
+			)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, dividend)
+}
+
+func (h *Handlers) getDividends(c *gin.Context) {
+	workspaceID := c.GetString("workspace_id")
+	dividends, err := h.dividendService.GetDividends(workspaceID, c.Query("ticker"), c.Query("from"), c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dividends)
+}
+
+func (h *Handlers) getDividendReport(c *gin.Context) {
+	workspaceID := c.GetString("workspace_id")
+	report, err := h.dividendService.GetReport(workspaceID, c.Query("date"))
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidDate) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// Performance handlers
+func (h *Handlers) getPerformance(c *gin.Context) {
+	workspaceID := c.GetString("workspace_id")
+	period := c.DefaultQuery("period", "12m")
+
+	report, err := h.performanceService.GetPerformance(workspaceID, period, c.Query("from"), c.Query("to"))
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidPeriod) || errors.Is(err, services.ErrInvalidDate) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		logger.Logger.Error("Failed to compute performance",
+			zap.Error(err),
+			zap.String("period", period),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// Allocation handlers
+func (h *Handlers) getAllocationTargets(c *gin.Context) {
+	workspaceID := c.GetString("workspace_id")
+	targets, err := h.allocationService.GetTargets(workspaceID)
+	if err != nil {
+		if errors.Is(err, services.ErrTargetsNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, targets)
+}
+
+func (h *Handlers) setAllocationTargets(c *gin.Context) {
+	workspaceID := c.GetString("workspace_id")
+	role := c.GetString("workspace_role")
+	var req models.SetAllocationTargetsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
+		return
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+		return
+	}
+
+	targets := models.AllocationTargets{
+		GroupBy:      req.GroupBy,
+		Targets:      req.Targets,
+		Tolerance:    req.Tolerance,
+		MinTradeSize: req.MinTradeSize,
+	}
+
+	if err := h.allocationService.SetTargets(&targets, workspaceID, role); err != nil {
+		if forbidden(c, err) {
+			return
+		}
+		if errors.Is(err, services.ErrInvalidTargets) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, targets)
+}
+
+func (h *Handlers) getRebalance(c *gin.Context) {
+	workspaceID := c.GetString("workspace_id")
+	mode := c.DefaultQuery("mode", services.RebalanceContribution)
+	contribution, err := strconv.ParseFloat(c.DefaultQuery("contribution", "0"), 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid contribution"})
+		return
+	}
+
+	report, err := h.allocationService.Rebalance(workspaceID, mode, contribution)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrTargetsNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrInvalidRebalancing):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// Net worth handlers
+func (h *Handlers) getNetWorth(c *gin.Context) {
+	workspaceID := c.GetString("workspace_id")
+	interval := c.DefaultQuery("interval", services.IntervalDaily)
+	snapshots, err := h.netWorthService.GetHistory(workspaceID, c.Query("from"), c.Query("to"), interval)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidInterval) || errors.Is(err, services.ErrInvalidDate) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, snapshots)
+}
+
+func (h *Handlers) takeNetWorthSnapshot(c *gin.Context) {
+	workspaceID := c.GetString("workspace_id")
+	role := c.GetString("workspace_role")
+	snapshot, err := h.netWorthService.TakeSnapshot(workspaceID, role)
+	if err != nil {
+		if forbidden(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, snapshot)
+}
+
+func (h *Handlers) getNetWorthItems(c *gin.Context) {
+	workspaceID := c.GetString("workspace_id")
+	items, err := h.netWorthService.GetItems(workspaceID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+func (h *Handlers) createNetWorthItem(c *gin.Context) {
+	workspaceID := c.GetString("workspace_id")
+	role := c.GetString("workspace_role")
+	item, ok := bindNetWorthItem(c)
+	if !ok {
+		return
+	}
+
+	if err := h.netWorthService.CreateItem(item, workspaceID, role); err != nil {
+		if forbidden(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, item)
+}
+
+func (h *Handlers) updateNetWorthItem(c *gin.Context) {
+	workspaceID := c.GetString("workspace_id")
+	role := c.GetString("workspace_role")
+	item, ok := bindNetWorthItem(c)
+	if !ok {
+		return
+	}
+
+	if err := h.netWorthService.UpdateItem(c.Param("id"), item, workspaceID, role); err != nil {
+		if forbidden(c, err) {
+			return
+		}
+		if errors.Is(err, services.ErrNetWorthItemNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+func (h *Handlers) deleteNetWorthItem(c *gin.Context) {
+	workspaceID := c.GetString("workspace_id")
+	role := c.GetString("workspace_role")
+	if err := h.netWorthService.DeleteItem(c.Param("id"), workspaceID, role); err != nil {
+		if forbidden(c, err) {
+			return
+		}
+		if errors.Is(err, services.ErrNetWorthItemNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// bindNetWorthItem reads and validates an asset or liability from the body,
+// writing the error response itself when it fails.
+func bindNetWorthItem(c *gin.Context) (*models.NetWorthItem, bool) {
+	var req models.NetWorthItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
+		return nil, false
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+		return nil, false
+	}
+
+	return &models.NetWorthItem{
+		Kind:     req.Kind,
+		Name:     req.Name,
+		Category: req.Category,
+		Value:    req.Value,
+	}, true
+}
+
+// Loan handlers
+func (h *Handlers) getLoans(c *gin.Context) {
+	workspaceID := c.GetString("workspace_id")
+	loans, err := h.loanService.GetLoans(workspaceID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, loans)
+}
+
+func (h *Handlers) createLoan(c *gin.Context) {
+	workspaceID := c.GetString("workspace_id")
+	role := c.GetString("workspace_role")
+	loan, ok := bindLoan(c)
+	if !ok {
+		return
+	}
+
+	if err := h.loanService.CreateLoan(loan, workspaceID, role); err != nil {
+		if forbidden(c, err) {
+			return
+		}
+		if errors.Is(err, services.ErrInvalidDate) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, loan)
+}
+
+func (h *Handlers) simulateLoan(c *gin.Context) {
+	loan, ok := bindLoan(c)
+	if !ok {
+		return
+	}
+
+	schedule, err := services.Schedule(loan)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+func (h *Handlers) getLoanSchedule(c *gin.Context) {
+	workspaceID := c.GetString("workspace_id")
+	schedule, err := h.loanService.GetSchedule(c.Param("id"), workspaceID)
+	if err != nil {
+		if errors.Is(err, services.ErrLoanNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+func (h *Handlers) deleteLoan(c *gin.Context) {
+	workspaceID := c.GetString("workspace_id")
+	role := c.GetString("workspace_role")
+	if err := h.loanService.DeleteLoan(c.Param("id"), workspaceID, role); err != nil {
+		if forbidden(c, err) {
+			return
+		}
+		if errors.Is(err, services.ErrLoanNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handlers) addLoanExtraPayment(c *gin.Context) {
+	workspaceID := c.GetString("workspace_id")
+	role := c.GetString("workspace_role")
+	var req models.ExtraPaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
+		return
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+		return
+	}
+
+	extra := models.ExtraPayment{
+		Installment: req.Installment,
+		Amount:      req.Amount,
+		Mode:        req.Mode,
+	}
+
+	schedule, err := h.loanService.AddExtraPayment(c.Param("id"), extra, workspaceID, role)
+	if err != nil {
+		if forbidden(c, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, services.ErrLoanNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrInvalidExtraPayment):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, schedule)
+}
+
+func (h *Handlers) postLoanInstallments(c *gin.Context) {
+	workspaceID := c.GetString("workspace_id")
+	role := c.GetString("workspace_role")
+	result, err := h.loanService.PostInstallments(c.Param("id"), c.Query("until"), workspaceID, role)
+	if err != nil {
+		if forbidden(c, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, services.ErrLoanNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrInvalidDate):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// bindLoan reads and validates a loan from the body, writing the error
+// response itself when it fails.
+func bindLoan(c *gin.Context) (*models.Loan, bool) {
+	var req models.CreateLoanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
+		return nil, false
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+		return nil, false
+	}
+
+	return &models.Loan{
+		Name:       req.Name,
+		System:     req.System,
+		Principal:  req.Principal,
+		Rate:       req.Rate,
+		Term:       req.Term,
+		StartDate:  req.StartDate,
+		CategoryID: req.CategoryID,
+	}, true
+}
+
+// Recurring item handlers
+func (h *Handlers) getRecurringItems(c *gin.Context) {
+	workspaceID := c.GetString("workspace_id")
+	items, err := h.recurringService.GetItems(workspaceID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+func (h *Handlers) createRecurringItem(c *gin.Context) {
+	workspaceID := c.GetString("workspace_id")
+	role := c.GetString("workspace_role")
+	item, ok := bindRecurringItem(c)
+	if !ok {
+		return
+	}
+
+	if err := h.recurringService.CreateItem(item, workspaceID, role); err != nil {
+		if forbidden(c, err) {
+			return
+		}
+		if errors.Is(err, services.ErrInvalidRecurrence) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, item)
+}
+
+func (h *Handlers) updateRecurringItem(c *gin.Context) {
+	workspaceID := c.GetString("workspace_id")
+	role := c.GetString("workspace_role")
+	item, ok := bindRecurringItem(c)
+	if !ok {
+		return
+	}
+
+	if err := h.recurringService.UpdateItem(c.Param("id"), item, workspaceID, role); err != nil {
+		if forbidden(c, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, services.ErrRecurringItemNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrInvalidRecurrence):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+func (h *Handlers) deleteRecurringItem(c *gin.Context) {
+	workspaceID := c.GetString("workspace_id")
+	role := c.GetString("workspace_role")
+	if err := h.recurringService.DeleteItem(c.Param("id"), workspaceID, role); err != nil {
+		if forbidden(c, err) {
+			return
+		}
+		if errors.Is(err, services.ErrRecurringItemNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// bindRecurringItem reads and validates a recurring item from the body,
+// writing the error response itself when it fails.
+func bindRecurringItem(c *gin.Context) (*models.RecurringItem, bool) {
+	var req models.RecurringItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
+		return nil, false
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+		return nil, false
+	}
+
+	return &models.RecurringItem{
+		Type:        req.Type,
+		Description: req.Description,
+		Amount:      req.Amount,
+		Frequency:   req.Frequency,
+		StartDate:   req.StartDate,
+		EndDate:     req.EndDate,
+		CategoryID:  req.CategoryID,
+	}, true
+}
+
+// Forecast handlers
+func (h *Handlers) getForecast(c *gin.Context) {
+	workspaceID := c.GetString("workspace_id")
+	months, err := strconv.Atoi(c.DefaultQuery("months", "3"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid months"})
+		return
+	}
+	trend, err := strconv.ParseBool(c.DefaultQuery("trend", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid trend"})
+		return
+	}
+
+	report, err := h.forecastService.GetForecast(workspaceID, months, c.DefaultQuery("interval", services.IntervalDaily), trend)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidForecast) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// Report handlers
+func (h *Handlers) getComparison(c *gin.Context) {
+	workspaceID := c.GetString("workspace_id")
+	current, previous, err := services.ComparePeriods(
+		c.DefaultQuery("mode", services.CompareMonthOverMonth),
+		c.Query("month"),
+		models.ReportPeriod{From: c.Query("currentFrom"), To: c.Query("currentTo")},
+		models.ReportPeriod{From: c.Query("previousFrom"), To: c.Query("previousTo")},
+	)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	report, err := h.comparisonService.Compare(workspaceID, current, previous)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// Insight handlers
+func (h *Handlers) getInsights(c *gin.Context) {
+	workspaceID := c.GetString("workspace_id")
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 200 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+		return
+	}
+	includeDismissed, _ := strconv.ParseBool(c.DefaultQuery("dismissed", "false"))
+
+	insights, err := h.insightService.GetInsights(workspaceID, includeDismissed, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, insights)
+}
+
+func (h *Handlers) sweepInsights(c *gin.Context) {
+	workspaceID := c.GetString("workspace_id")
+	role := c.GetString("workspace_role")
+	result, err := h.insightService.Sweep(workspaceID, role)
+	if err != nil {
+		if forbidden(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *Handlers) dismissInsight(c *gin.Context) {
+	workspaceID := c.GetString("workspace_id")
+	role := c.GetString("workspace_role")
+	if err := h.insightService.Dismiss(c.Param("id"), workspaceID, role); err != nil {
+		if forbidden(c, err) {
+			return
+		}
+		if errors.Is(err, services.ErrInsightNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Subscription handlers
+func (h *Handlers) getSubscriptions(c *gin.Context) {
+	workspaceID := c.GetString("workspace_id")
+	subscriptions, err := h.subscriptionService.GetSubscriptions(workspaceID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, subscriptions)
+}
+
+func (h *Handlers) confirmSubscription(c *gin.Context) {
+	workspaceID := c.GetString("workspace_id")
+	role := c.GetString("workspace_role")
+	var req models.ConfirmSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
+		return
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+		return
+	}
+
+	item, err := h.subscriptionService.Confirm(req.Merchant, workspaceID, role)
+	if err != nil {
+		if forbidden(c, err) {
+			return
+		}
+		if errors.Is(err, services.ErrSubscriptionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, item)
+}
+
+// Merchant handlers
+func (h *Handlers) getMerchants(c *gin.Context) {
+	workspaceID := c.GetString("workspace_id")
+	merchants, err := h.merchantService.GetMerchants(workspaceID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, merchants)
+}
+
+func (h *Handlers) updateMerchant(c *gin.Context) {
+	workspaceID := c.GetString("workspace_id")
+	role := c.GetString("workspace_role")
+	var req models.UpdateMerchantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
+		return
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+		return
+	}
+
+	merchant, err := h.merchantService.UpdateMerchant(c.Param("id"), req.Name, req.Aliases, workspaceID, role)
+	if err != nil {
+		if forbidden(c, err) {
+			return
+		}
+		if errors.Is(err, services.ErrMerchantNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, merchant)
+}
+
+func (h *Handlers) getTopMerchants(c *gin.Context) {
+	workspaceID := c.GetString("workspace_id")
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+		return
+	}
+
+	merchants, err := h.merchantService.GetTopMerchants(workspaceID, c.Query("from"), c.Query("to"), limit)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidTopMerchants) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, merchants)
+}
+
+// Export handlers
+func (h *Handlers) requestExport(c *gin.Context) {
+	userID := c.GetString("user_id")
+	job, err := h.exportService.Request(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, job)
+}
+
+func (h *Handlers) getExports(c *gin.Context) {
+	userID := c.GetString("user_id")
+	jobs, err := h.exportService.GetExports(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, jobs)
+}
+
+func (h *Handlers) getExport(c *gin.Context) {
+	userID := c.GetString("user_id")
+	job, err := h.exportService.GetExport(c.Param("id"), userID)
+	if err != nil {
+		if errors.Is(err, services.ErrExportNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// downloadExport serves the archive to anyone holding a valid signed link, so
+// it can be opened straight from the browser without the access token.
+func (h *Handlers) downloadExport(c *gin.Context) {
+	job, err := h.exportService.Download(c.Param("id"), c.Query("expires"), c.Query("signature"))
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidExportLink) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.FileAttachment(job.Path, "export-"+job.CreatedAt.Format("2006-01-02")+".zip")
+}
+
+// Workspace handlers
+func (h *Handlers) getWorkspaces(c *gin.Context) {
+	userID := c.GetString("user_id")
+	workspaces, err := h.workspaceService.GetWorkspaces(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, workspaces)
+}
+
+func (h *Handlers) createWorkspace(c *gin.Context) {
+	userID := c.GetString("user_id")
+	var req models.CreateWorkspaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
+		return
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+		return
+	}
+
+	workspace, err := h.workspaceService.CreateWorkspace(userID, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, workspace)
+}
+
+func (h *Handlers) updateWorkspace(c *gin.Context) {
+	userID := c.GetString("user_id")
+	var req models.UpdateWorkspaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
+		return
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+		return
+	}
+
+	workspace, err := h.workspaceService.RenameWorkspace(c.Param("id"), userID, &req)
+	if err != nil {
+		workspaceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, workspace)
+}
+
+func (h *Handlers) deleteWorkspace(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if err := h.workspaceService.DeleteWorkspace(c.Param("id"), userID); err != nil {
+		workspaceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handlers) getWorkspaceMembers(c *gin.Context) {
+	userID := c.GetString("user_id")
+	members, err := h.workspaceService.GetMembers(c.Param("id"), userID)
+	if err != nil {
+		workspaceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, members)
+}
+
+func (h *Handlers) updateWorkspaceMember(c *gin.Context) {
+	userID := c.GetString("user_id")
+	var req models.UpdateMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
+		return
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+		return
+	}
+
+	member, err := h.workspaceService.UpdateMember(c.Param("id"), userID, c.Param("userId"), &req)
+	if err != nil {
+		workspaceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
+
+// removeWorkspaceMember removes a member, or lets users leave when it is
+// their own ID.
+func (h *Handlers) removeWorkspaceMember(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if err := h.workspaceService.RemoveMember(c.Param("id"), userID, c.Param("userId")); err != nil {
+		workspaceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handlers) inviteWorkspaceMember(c *gin.Context) {
+	userID := c.GetString("user_id")
+	var req models.InviteMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
+		return
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+		return
+	}
+
+	invitation, err := h.workspaceService.Invite(c.Param("id"), userID, &req)
+	if err != nil {
+		workspaceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, invitation)
+}
+
+func (h *Handlers) getWorkspaceInvitations(c *gin.Context) {
+	userID := c.GetString("user_id")
+	invitations, err := h.workspaceService.GetInvitations(c.Param("id"), userID)
+	if err != nil {
+		workspaceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, invitations)
+}
+
+func (h *Handlers) revokeWorkspaceInvitation(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if err := h.workspaceService.RevokeInvitation(c.Param("id"), userID, c.Param("invitationId")); err != nil {
+		workspaceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handlers) acceptWorkspaceInvitation(c *gin.Context) {
+	userID := c.GetString("user_id")
+	var req models.AcceptInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
+		return
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+		return
+	}
+
+	workspace, err := h.workspaceService.AcceptInvitation(userID, req.Token)
+	if err != nil {
+		workspaceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, workspace)
+}
+
+// forbidden answers 403 when the user's role in the workspace does not allow
+// the write, reporting whether it did.
+func forbidden(c *gin.Context, err error) bool {
+	if !errors.Is(err, services.ErrWorkspaceRole) {
+		return false
+	}
+	c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	return true
+}
+
+func workspaceError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrWorkspaceNotFound), errors.Is(err, services.ErrMemberNotFound), errors.Is(err, services.ErrInvitationNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrWorkspaceRole), errors.Is(err, services.ErrInvitationEmail):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidInvitation):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrPersonalWorkspace), errors.Is(err, services.ErrLastOwner), errors.Is(err, services.ErrAlreadyMember):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// Category handlers
+func (h *Handlers) getCategories(c *gin.Context) {
+	workspaceID := c.GetString("workspace_id")
+	summary, err := h.dashboardService.GetSummary(workspaceID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, summary.Categories)
+}
+
+// Dashboard handlers
+func (h *Handlers) getDashboardSummary(c *gin.Context) {
+	workspaceID := c.GetString("workspace_id")
+	summary, err := h.dashboardService.GetSummary(workspaceID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// Overview handlers
+func (h *Handlers) getOverview(c *gin.Context) {
+	workspaceID := c.GetString("workspace_id")
+	groupBy := c.DefaultQuery("groupBy", repositories.GroupByType)
+	if groupBy != repositories.GroupByType && groupBy != repositories.GroupByAssetClass {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "groupBy must be type or assetClass"})
+		return
+	}
+
+	overview, err := h.dashboardService.GetOverview(workspaceID, groupBy)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, overview)
+}
+
+// csvUpload returns the uploaded "file" form field, or the raw request body
+// when the CSV is posted directly.
+func csvUpload(c *gin.Context) (io.Reader, func(), error) {
+	file, err := c.FormFile("file")
+	if err != nil {
+		return c.Request.Body, func() {}, nil
+	}
+
+	opened, err := file.Open()
+	if err != nil {
+		return nil, nil, err
+	}
+	return opened, func() { opened.Close() }, nil
+}
@@ -1,10 +1,26 @@
 package handlers
 
 import (
+	"financial-api/internal/middleware"
+	"financial-api/internal/models"
+
 	"github.com/gin-gonic/gin"
 )
 
-func (h *Handlers) SetupRoutes(r *gin.Engine, authHandlers *AuthHandlers, authMiddleware gin.HandlerFunc) {
+func (h *Handlers) SetupRoutes(r *gin.Engine, authHandlers *AuthHandlers, authMiddleware, workspaceMiddleware, operatorMiddleware gin.HandlerFunc) {
+	// API keys can only reach the routes of their scopes, and never the
+	// account itself. Viewers of a workspace can only read it.
+	sessionOnly := middleware.RequireSession()
+	transactionsRead := middleware.RequireAccess(models.ScopeTransactionsRead, models.RoleViewer)
+	transactionsWrite := middleware.RequireAccess(models.ScopeTransactionsWrite, models.RoleEditor)
+	investmentsRead := middleware.RequireAccess(models.ScopeInvestmentsRead, models.RoleViewer)
+	investmentsWrite := middleware.RequireAccess(models.ScopeInvestmentsWrite, models.RoleEditor)
+	planningRead := middleware.RequireAccess(models.ScopePlanningRead, models.RoleViewer)
+	planningWrite := middleware.RequireAccess(models.ScopePlanningWrite, models.RoleEditor)
+	reportsRead := middleware.RequireAccess(models.ScopeReportsRead, models.RoleViewer)
+
+	r.GET("/.well-known/jwks.json", authHandlers.JWKS)
+
 	api := r.Group("/api")
 	{
 		// Auth routes (public)
@@ -12,29 +28,146 @@ func (h *Handlers) SetupRoutes(r *gin.Engine, authHandlers *AuthHandlers, authMi
 		{
 			auth.POST("/register", authHandlers.Register)
 			auth.POST("/login", authHandlers.Login)
+			auth.POST("/refresh", authHandlers.Refresh)
+			auth.POST("/logout", authMiddleware, sessionOnly, authHandlers.Logout)
+			auth.POST("/logout-all", authMiddleware, sessionOnly, authHandlers.LogoutAll)
+			auth.POST("/password/forgot", authHandlers.ForgotPassword)
+			auth.POST("/password/reset", authHandlers.ResetPassword)
+			auth.POST("/email/verify", authHandlers.VerifyEmail)
+			auth.POST("/email/resend", authHandlers.ResendVerification)
+			auth.POST("/unlock", authHandlers.UnlockAccount)
+			auth.GET("/security-events", authMiddleware, sessionOnly, authHandlers.SecurityEvents)
+			auth.POST("/2fa/verify", authHandlers.VerifyTwoFactor)
+			auth.POST("/2fa/enroll", authMiddleware, sessionOnly, authHandlers.EnrollTwoFactor)
+			auth.POST("/2fa/confirm", authMiddleware, sessionOnly, authHandlers.ConfirmTwoFactor)
+			auth.POST("/2fa/disable", authMiddleware, sessionOnly, authHandlers.DisableTwoFactor)
+			auth.POST("/2fa/recovery-codes", authMiddleware, sessionOnly, authHandlers.RegenerateRecoveryCodes)
 			auth.GET("/me", authMiddleware, authHandlers.Me)
+			auth.PATCH("/me", authMiddleware, sessionOnly, authHandlers.UpdateMe)
+			auth.POST("/me/delete-request", authMiddleware, sessionOnly, authHandlers.RequestDeletion)
+			auth.DELETE("/me", authMiddleware, sessionOnly, authHandlers.DeleteMe)
+			auth.GET("/api-keys", authMiddleware, sessionOnly, authHandlers.APIKeys)
+			auth.POST("/api-keys", authMiddleware, sessionOnly, authHandlers.CreateAPIKey)
+			auth.DELETE("/api-keys/:id", authMiddleware, sessionOnly, authHandlers.RevokeAPIKey)
+			auth.GET("/oidc/providers", authHandlers.OIDCProviders)
+			auth.POST("/oidc/callback", authHandlers.OIDCCallback)
+			auth.POST("/oidc/link", authMiddleware, sessionOnly, authHandlers.OIDCLink)
+			auth.POST("/oidc/:provider/authorize", authHandlers.OIDCAuthorize)
+			auth.POST("/oidc/:provider/link", authMiddleware, sessionOnly, authHandlers.OIDCLinkAuthorize)
+			auth.DELETE("/oidc/:provider", authMiddleware, sessionOnly, authHandlers.OIDCUnlink)
 		}
 
+		// Market data shared by every user can only be changed by the operator
+		api.POST("/indexes/import", operatorMiddleware, h.importIndexValues)
+		api.POST("/quotes/import", operatorMiddleware, h.importQuotes)
+
+		// Signed export downloads carry their own authorization
+		api.GET("/exports/:id/download", h.downloadExport)
+
 		// Protected routes
 		protected := api.Group("/")
-		protected.Use(authMiddleware)
+		protected.Use(authMiddleware, workspaceMiddleware)
 		{
 			// Transactions
-			protected.GET("/transactions", h.getTransactions)
-			protected.POST("/transactions", h.createTransaction)
+			protected.GET("/transactions", transactionsRead, h.getTransactions)
+			protected.POST("/transactions", transactionsWrite, h.createTransaction)
 
 			// Investments
-			protected.GET("/investments", h.getInvestments)
-			protected.POST("/investments", h.createInvestment)
+			protected.GET("/investments", investmentsRead, h.getInvestments)
+			protected.POST("/investments", investmentsWrite, h.createInvestment)
+			protected.GET("/investments/maturities", investmentsRead, h.getLiquidity)
+			protected.GET("/investments/:id/movements", investmentsRead, h.getInvestmentMovements)
+			protected.POST("/investments/:id/movements", investmentsWrite, h.createInvestmentMovement)
+			protected.GET("/investments/:id/valuation", investmentsRead, h.getInvestmentValuation)
+
+			// Indexes
+			protected.GET("/indexes/:index", investmentsRead, h.getIndexValues)
+
+			// Variable income
+			protected.GET("/trades", investmentsRead, h.getTrades)
+			protected.POST("/trades", investmentsWrite, h.createTrade)
+			protected.GET("/positions", investmentsRead, h.getPositions)
+			protected.GET("/positions/:ticker", investmentsRead, h.getPosition)
+			protected.GET("/quotes/:ticker", investmentsRead, h.getQuotes)
+
+			// Dividends
+			protected.GET("/dividends", investmentsRead, h.getDividends)
+			protected.POST("/dividends", investmentsWrite, h.createDividend)
+			protected.GET("/dividends/report", investmentsRead, h.getDividendReport)
+
+			// Performance
+			protected.GET("/performance", investmentsRead, h.getPerformance)
+
+			// Allocation
+			protected.GET("/allocation/targets", investmentsRead, h.getAllocationTargets)
+			protected.PUT("/allocation/targets", investmentsWrite, h.setAllocationTargets)
+			protected.GET("/allocation/rebalance", investmentsRead, h.getRebalance)
+
+			// Net worth
+			protected.GET("/net-worth", planningRead, h.getNetWorth)
+			protected.POST("/net-worth/snapshots", planningWrite, h.takeNetWorthSnapshot)
+			protected.GET("/net-worth/items", planningRead, h.getNetWorthItems)
+			protected.POST("/net-worth/items", planningWrite, h.createNetWorthItem)
+			protected.PUT("/net-worth/items/:id", planningWrite, h.updateNetWorthItem)
+			protected.DELETE("/net-worth/items/:id", planningWrite, h.deleteNetWorthItem)
+
+			// Loans
+			protected.GET("/loans", planningRead, h.getLoans)
+			protected.POST("/loans", planningWrite, h.createLoan)
+			protected.POST("/loans/simulate", planningRead, h.simulateLoan)
+			protected.GET("/loans/:id", planningRead, h.getLoanSchedule)
+			protected.DELETE("/loans/:id", planningWrite, h.deleteLoan)
+			protected.POST("/loans/:id/extra-payments", planningWrite, h.addLoanExtraPayment)
+			protected.POST("/loans/:id/post", planningWrite, h.postLoanInstallments)
+
+			// Recurring items and forecast
+			protected.GET("/recurring", planningRead, h.getRecurringItems)
+			protected.POST("/recurring", planningWrite, h.createRecurringItem)
+			protected.PUT("/recurring/:id", planningWrite, h.updateRecurringItem)
+			protected.DELETE("/recurring/:id", planningWrite, h.deleteRecurringItem)
+			protected.GET("/forecast", planningRead, h.getForecast)
+
+			// Reports
+			protected.GET("/reports/comparison", reportsRead, h.getComparison)
+
+			// Insights
+			protected.GET("/insights", reportsRead, h.getInsights)
+			protected.POST("/insights/sweep", transactionsWrite, h.sweepInsights)
+			protected.POST("/insights/:id/dismiss", transactionsWrite, h.dismissInsight)
+
+			// Subscriptions
+			protected.GET("/subscriptions", transactionsRead, h.getSubscriptions)
+			protected.POST("/subscriptions/confirm", planningWrite, h.confirmSubscription)
+			protected.GET("/merchants", transactionsRead, h.getMerchants)
+			protected.GET("/merchants/top", transactionsRead, h.getTopMerchants)
+			protected.PUT("/merchants/:id", transactionsWrite, h.updateMerchant)
+
+			// Personal data exports
+			protected.POST("/exports", sessionOnly, h.requestExport)
+			protected.GET("/exports", sessionOnly, h.getExports)
+			protected.GET("/exports/:id", sessionOnly, h.getExport)
+
+			// Workspaces, whose roles are checked by the workspace service
+			protected.GET("/workspaces", sessionOnly, h.getWorkspaces)
+			protected.POST("/workspaces", sessionOnly, h.createWorkspace)
+			protected.POST("/workspaces/invitations/accept", sessionOnly, h.acceptWorkspaceInvitation)
+			protected.PATCH("/workspaces/:id", sessionOnly, h.updateWorkspace)
+			protected.DELETE("/workspaces/:id", sessionOnly, h.deleteWorkspace)
+			protected.GET("/workspaces/:id/members", sessionOnly, h.getWorkspaceMembers)
+			protected.PUT("/workspaces/:id/members/:userId", sessionOnly, h.updateWorkspaceMember)
+			protected.DELETE("/workspaces/:id/members/:userId", sessionOnly, h.removeWorkspaceMember)
+			protected.GET("/workspaces/:id/invitations", sessionOnly, h.getWorkspaceInvitations)
+			protected.POST("/workspaces/:id/invitations", sessionOnly, h.inviteWorkspaceMember)
+			protected.DELETE("/workspaces/:id/invitations/:invitationId", sessionOnly, h.revokeWorkspaceInvitation)
 
 			// Categories
-			protected.GET("/categories", h.getCategories)
+			protected.GET("/categories", transactionsRead, h.getCategories)
 
 			// Dashboard
-			protected.GET("/dashboard/summary", h.getDashboardSummary)
+			protected.GET("/dashboard/summary", reportsRead, h.getDashboardSummary)
 
 			// Overview
-			protected.GET("/overview", h.getOverview)
+			protected.GET("/overview", reportsRead, h.getOverview)
 		}
 	}
 }
\ No newline at end of file
@@ -0,0 +1,12 @@
+package jobs
+
+import (
+	"time"
+
+	"financial-api/internal/services"
+)
+
+// RunExportCleanup deletes the data export archives past their expiry.
+func RunExportCleanup(service *services.ExportService, interval time.Duration) {
+	every(interval, "export_cleanup", service.CleanupExpired)
+}
@@ -0,0 +1,13 @@
+package jobs
+
+import (
+	"time"
+
+	"financial-api/internal/services"
+)
+
+// RunInsightSweep re-checks recent transactions and looks for monthly spending
+// spikes for every workspace.
+func RunInsightSweep(service *services.InsightService, interval time.Duration) {
+	every(interval, "insight_sweep", service.SweepAll)
+}
@@ -0,0 +1,27 @@
+package jobs
+
+import (
+	"time"
+
+	"financial-api/internal/logger"
+
+	"go.uber.org/zap"
+)
+
+// every runs the task right away and then on every tick of the interval,
+// logging how many items it processed. It blocks and is meant to run in its
+// own goroutine.
+func every(interval time.Duration, name string, task func() (int, error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		count, err := task()
+		if err != nil {
+			logger.Logger.Warn("Background job failed", zap.String("job", name), zap.Int("processed", count), zap.Error(err))
+		} else {
+			logger.Logger.Info("Background job finished", zap.String("job", name), zap.Int("processed", count))
+		}
+		<-ticker.C
+	}
+}
@@ -0,0 +1,14 @@
+package jobs
+
+import (
+	"time"
+
+	"financial-api/internal/services"
+)
+
+// RunNetWorthSnapshots snapshots the net worth of every workspace. Snapshots
+// are kept one per workspace and day, so running more often than daily only
+// refreshes today's snapshot.
+func RunNetWorthSnapshots(service *services.NetWorthService, interval time.Duration) {
+	every(interval, "net_worth_snapshots", service.SnapshotAll)
+}
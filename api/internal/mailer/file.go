@@ -0,0 +1,43 @@
+package mailer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"financial-api/internal/logger"
+
+	"go.uber.org/zap"
+)
+
+// FileMailer writes each message to an .eml file in the outbox directory and
+// logs it instead of delivering it.
+type FileMailer struct {
+	dir  string
+	from string
+}
+
+func NewFileMailer(dir, from string) *FileMailer {
+	return &FileMailer{dir: dir, from: from}
+}
+
+func (m *FileMailer) Send(message Message) error {
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return err
+	}
+
+	recipient := strings.NewReplacer("@", "_at_", "/", "_").Replace(message.To)
+	name := fmt.Sprintf("%d-%s.eml", time.Now().UnixNano(), recipient)
+	path := filepath.Join(m.dir, name)
+	if err := os.WriteFile(path, format(m.from, message), 0o600); err != nil {
+		return err
+	}
+
+	logger.Logger.Info("Email written to outbox",
+		zap.String("to", message.To),
+		zap.String("subject", message.Subject),
+		zap.String("path", path))
+	return nil
+}
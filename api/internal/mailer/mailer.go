@@ -0,0 +1,44 @@
+package mailer
+
+import (
+	"fmt"
+	"mime"
+	"strings"
+	"time"
+)
+
+// Message is a plain text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers account emails such as password resets and email
+// verification links.
+type Mailer interface {
+	Send(message Message) error
+}
+
+// New returns the SMTP mailer for the "smtp" driver and the file mailer
+// otherwise, which suits local development and tests.
+func New(driver string, smtp SMTPConfig, outboxDir string) Mailer {
+	if driver == "smtp" {
+		return NewSMTPMailer(smtp)
+	}
+	return NewFileMailer(outboxDir, smtp.From)
+}
+
+// format renders the message as RFC 5322 text.
+func format(from string, message Message) []byte {
+	headers := []string{
+		"From: " + from,
+		"To: " + message.To,
+		"Subject: " + mime.QEncoding.Encode("utf-8", message.Subject),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+	}
+	body := strings.ReplaceAll(message.Body, "\n", "\r\n")
+	return []byte(fmt.Sprintf("%s\r\n\r\n%s\r\n", strings.Join(headers, "\r\n"), body))
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"crypto/subtle"
 	"net/http"
 	"strings"
 	"time"
@@ -42,10 +43,12 @@ func extractToken(authHeader string) (string, bool) {
 }
 
 func validateClaims(claims *models.JWTClaims) bool {
-	return claims != nil && claims.UserID != "" && claims.Email != ""
+	return claims != nil && claims.UserID != "" && claims.Email != "" && claims.SessionID != ""
 }
 
-func AuthMiddleware(authService *services.AuthService) gin.HandlerFunc {
+// AuthMiddleware accepts a session JWT or an API key as the bearer token. A
+// request made with a key carries the key in the context for RequireAccess.
+func AuthMiddleware(authService *services.AuthService, apiKeyService *services.APIKeyService) gin.HandlerFunc {
 	return gin.HandlerFunc(func(c *gin.Context) {
 		start := time.Now()
 		auth := &authContext{
@@ -71,6 +74,27 @@ func AuthMiddleware(authService *services.AuthService) gin.HandlerFunc {
 			return
 		}
 
+		if strings.HasPrefix(token, models.APIKeyPrefix) {
+			key, user, err := apiKeyService.Authenticate(token)
+			if err != nil {
+				auth.logAndAbort(c, http.StatusUnauthorized, "Access denied", "Auth attempt with invalid API key", err)
+				return
+			}
+
+			logger.Logger.Info("Successful API key auth",
+				zap.String("user_id", key.UserID),
+				zap.String("api_key_id", key.ID.Hex()),
+				zap.String("ip", auth.clientIP),
+				zap.String("path", auth.path),
+				zap.Duration("duration", time.Since(start)))
+
+			c.Set("user_id", key.UserID)
+			c.Set("user_email", user.Email)
+			c.Set("api_key", key)
+			c.Next()
+			return
+		}
+
 		claims, err := authService.ValidateToken(token)
 		if err != nil {
 			auth.logAndAbort(c, http.StatusUnauthorized, "Access denied", "Auth attempt with invalid token", err)
@@ -80,6 +104,10 @@ func AuthMiddleware(authService *services.AuthService) gin.HandlerFunc {
 			auth.logAndAbort(c, http.StatusUnauthorized, "Access denied", "Auth attempt with invalid claims", nil)
 			return
 		}
+		if err := authService.CheckSession(claims); err != nil {
+			auth.logAndAbort(c, http.StatusUnauthorized, "Access denied", "Auth attempt with revoked session", err)
+			return
+		}
 
 		logger.Logger.Info("Successful auth",
 			zap.String("user_id", claims.UserID),
@@ -90,6 +118,79 @@ func AuthMiddleware(authService *services.AuthService) gin.HandlerFunc {
 
 		c.Set("user_id", claims.UserID)
 		c.Set("user_email", claims.Email)
+		c.Set("session_id", claims.SessionID)
 		c.Next()
 	})
 }
+
+// Workspace resolves the workspace of the request from the X-Workspace-ID
+// header, the user's personal workspace without it, and sets it in the
+// context with the user's role. Workspaces the user is not a member of are
+// not found.
+func Workspace(workspaceService *services.WorkspaceService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		workspaceID, role, err := workspaceService.Resolve(c.GetString("user_id"), c.GetHeader(models.WorkspaceHeader))
+		if err != nil {
+			if err == services.ErrWorkspaceNotFound {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Workspace not found"})
+			} else {
+				logger.Logger.Error("Failed to resolve workspace", zap.Error(err))
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve workspace"})
+			}
+			c.Abort()
+			return
+		}
+
+		c.Set("workspace_id", workspaceID)
+		c.Set("workspace_role", role)
+		c.Next()
+	}
+}
+
+// RequireAccess lets a request through only if the user's role in the
+// workspace allows what role does and, for API keys, the key grants the
+// scope. Sessions are not limited by scopes.
+func RequireAccess(scope, role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if value, ok := c.Get("api_key"); ok && !value.(*models.APIKey).HasScope(scope) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "API key lacks the " + scope + " scope"})
+			c.Abort()
+			return
+		}
+		if !services.HasRole(c.GetString("workspace_role"), role) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Requires the " + role + " role in this workspace"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireSession rejects API keys, keeping account management to logged in
+// users.
+func RequireSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := c.Get("api_key"); ok {
+			c.JSON(http.StatusForbidden, gin.H{"error": "API keys cannot access this route"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireOperator guards the routes that change data shared by every user,
+// such as the index and quote tables. They need the operator token in the
+// X-Operator-Token header and are disabled when no token is configured.
+func RequireOperator(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		given := c.GetHeader("X-Operator-Token")
+		if token == "" || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			logger.Logger.Warn("Operator route denied", zap.String("ip", c.ClientIP()), zap.String("path", c.Request.URL.Path))
+			c.JSON(http.StatusForbidden, gin.H{"error": "Operator access required"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
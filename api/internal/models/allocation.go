@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AllocationTargets is the target portfolio split of a workspace, by investment
+// type or by asset class.
+type AllocationTargets struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	GroupBy      string             `bson:"groupBy" json:"groupBy"`
+	Targets      []AllocationTarget `bson:"targets" json:"targets"`
+	Tolerance    float64            `bson:"tolerance" json:"tolerance"`
+	MinTradeSize float64            `bson:"minTradeSize" json:"minTradeSize"`
+	WorkspaceID  *string            `bson:"workspaceId,omitempty" json:"workspaceId,omitempty"`
+	UpdatedAt    time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
+
+type AllocationTarget struct {
+	Name       string  `bson:"name" json:"name" validate:"required"`
+	Percentage float64 `bson:"percentage" json:"percentage" validate:"gte=0,lte=100"`
+}
+
+type RebalanceReport struct {
+	GroupBy      string          `json:"groupBy"`
+	Mode         string          `json:"mode"`
+	TotalValue   float64         `json:"totalValue"`
+	Contribution float64         `json:"contribution"`
+	Unallocated  float64         `json:"unallocated"`
+	Tolerance    float64         `json:"tolerance"`
+	MinTradeSize float64         `json:"minTradeSize"`
+	Balanced     bool            `json:"balanced"`
+	Items        []RebalanceItem `json:"items"`
+}
+
+// RebalanceItem compares a group with its target. Drift is in percentage
+// points; a positive Suggested amount is a buy and a negative one a sell.
+type RebalanceItem struct {
+	Name              string  `json:"name"`
+	CurrentValue      float64 `json:"currentValue"`
+	CurrentPercentage float64 `json:"currentPercentage"`
+	TargetPercentage  float64 `json:"targetPercentage"`
+	TargetValue       float64 `json:"targetValue"`
+	Drift             float64 `json:"drift"`
+	WithinTolerance   bool    `json:"withinTolerance"`
+	Suggested         float64 `json:"suggested"`
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// API key scopes. A key can only call the routes of its scopes; sessions are
+// not limited by scopes.
+const (
+	ScopeTransactionsRead  = "transactions:read"
+	ScopeTransactionsWrite = "transactions:write"
+	ScopeInvestmentsRead   = "investments:read"
+	ScopeInvestmentsWrite  = "investments:write"
+	ScopePlanningRead      = "planning:read"
+	ScopePlanningWrite     = "planning:write"
+	ScopeReportsRead       = "reports:read"
+)
+
+// APIKeyPrefix starts every API key, telling keys apart from JWTs.
+const APIKeyPrefix = "fin_"
+
+// APIKey is a personal access token for scripts and integrations. Only the
+// hash of the key is stored; Prefix identifies it in listings.
+type APIKey struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID     string             `bson:"userId" json:"-"`
+	Name       string             `bson:"name" json:"name"`
+	Prefix     string             `bson:"prefix" json:"prefix"`
+	KeyHash    string             `bson:"keyHash" json:"-"`
+	Scopes     []string           `bson:"scopes" json:"scopes"`
+	ExpiresAt  *time.Time         `bson:"expiresAt,omitempty" json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time         `bson:"lastUsedAt,omitempty" json:"lastUsedAt,omitempty"`
+	CreatedAt  time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// HasScope reports whether the key grants the scope.
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateAPIKeyRequest creates a key. Without ExpiresInDays the key does not
+// expire.
+type CreateAPIKeyRequest struct {
+	Name          string   `json:"name" validate:"required,max=100"`
+	Scopes        []string `json:"scopes" validate:"required,min=1,unique,dive,oneof=transactions:read transactions:write investments:read investments:write planning:read planning:write reports:read"`
+	ExpiresInDays int      `json:"expiresInDays" validate:"omitempty,min=1,max=365"`
+}
+
+// CreatedAPIKey is returned once, on creation, with the key in clear.
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
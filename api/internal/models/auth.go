@@ -7,12 +7,15 @@ import (
 )
 
 type User struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Email     string             `bson:"email" json:"email"`
-	Password  string             `bson:"password" json:"-"`
-	Name      string             `bson:"name" json:"name"`
-	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
-	UpdatedAt time.Time          `bson:"updatedAt" json:"updatedAt"`
+	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Email           string             `bson:"email" json:"email"`
+	Password        string             `bson:"password" json:"-"`
+	Name            string             `bson:"name" json:"name"`
+	EmailVerifiedAt *time.Time         `bson:"emailVerifiedAt,omitempty" json:"emailVerifiedAt,omitempty"`
+	TwoFactor       TwoFactor          `bson:"twoFactor" json:"twoFactor"`
+	Identities      []Identity         `bson:"identities,omitempty" json:"identities,omitempty"`
+	CreatedAt       time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt       time.Time          `bson:"updatedAt" json:"updatedAt"`
 }
 
 type RegisterRequest struct {
@@ -26,12 +29,149 @@ type LoginRequest struct {
 	Password string `json:"password" validate:"required"`
 }
 
+// TwoFactor holds the TOTP state of a user. PendingSecret waits for the first
+// code before it becomes Secret; LastStep is the last accepted TOTP step, so a
+// code cannot be replayed. Recovery codes are SHA-256 hashes.
+type TwoFactor struct {
+	Enabled       bool     `bson:"enabled" json:"enabled"`
+	Secret        string   `bson:"secret,omitempty" json:"-"`
+	PendingSecret string   `bson:"pendingSecret,omitempty" json:"-"`
+	LastStep      int64    `bson:"lastStep,omitempty" json:"-"`
+	RecoveryCodes []string `bson:"recoveryCodes,omitempty" json:"-"`
+}
+
+type TwoFactorEnrollment struct {
+	Secret     string `json:"secret"`
+	OtpauthURI string `json:"otpauthUri"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+// DisableTwoFactorRequest confirms turning two-factor authentication off with
+// the current password, empty for accounts without one, and a valid code.
+type DisableTwoFactorRequest struct {
+	Password string `json:"password"`
+	Code     string `json:"code" validate:"required"`
+}
+
+// TwoFactorLoginRequest is the second login step. Code is a TOTP code or a
+// recovery code.
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challengeToken" validate:"required"`
+	Code           string `json:"code" validate:"required"`
+}
+
+// UpdateProfileRequest changes any of the fields given. Changing the email or
+// the password requires the current password, or a recent sign in for
+// accounts without one.
+type UpdateProfileRequest struct {
+	Name            *string `json:"name" validate:"omitempty,min=2"`
+	Email           *string `json:"email" validate:"omitempty,email"`
+	NewPassword     *string `json:"newPassword" validate:"omitempty,min=6"`
+	CurrentPassword string  `json:"currentPassword"`
+}
+
+// PasswordRequest confirms a sensitive action. Accounts without a password
+// leave it empty and must have signed in recently.
+type PasswordRequest struct {
+	Password string `json:"password"`
+}
+
+// DeletionConfirmation is the second step of an account deletion: sending the
+// token back deletes the account until ExpiresAt.
+type DeletionConfirmation struct {
+	ConfirmationToken string    `json:"confirmationToken"`
+	ExpiresAt         time.Time `json:"expiresAt"`
+}
+
+type DeleteAccountRequest struct {
+	ConfirmationToken string `json:"confirmationToken" validate:"required"`
+}
+
+type EmailRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,min=6"`
+}
+
+// TokenRequest confirms a flow with the token from an email.
+type TokenRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken" validate:"required"`
+}
+
+// AuthResponse carries a short-lived access token and the refresh token that
+// replaces it. ExpiresIn is the access token lifetime in seconds. The tokens
+// are left out when the account must verify its email first, or when it has
+// two-factor authentication and ChallengeToken starts the second step.
 type AuthResponse struct {
-	Token string `json:"token"`
-	User  User   `json:"user"`
+	Token             string `json:"token,omitempty"`
+	RefreshToken      string `json:"refreshToken,omitempty"`
+	ExpiresIn         int64  `json:"expiresIn,omitempty"`
+	TwoFactorRequired bool   `json:"twoFactorRequired,omitempty"`
+	ChallengeToken    string `json:"challengeToken,omitempty"`
+	User              User   `json:"user"`
 }
 
 type JWTClaims struct {
-	UserID string `json:"user_id"`
-	Email  string `json:"email"`
+	UserID    string `json:"user_id"`
+	Email     string `json:"email"`
+	SessionID string `json:"sid"`
+}
+
+// Session is one login. Its refresh tokens rotate on every use and form one
+// family; revoking the session logs that login out and rejects its tokens.
+type Session struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID     string             `bson:"userId" json:"-"`
+	RevokedAt  *time.Time         `bson:"revokedAt,omitempty" json:"revokedAt,omitempty"`
+	ExpiresAt  time.Time          `bson:"expiresAt" json:"expiresAt"`
+	LastUsedAt time.Time          `bson:"lastUsedAt" json:"lastUsedAt"`
+	CreatedAt  time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// RefreshToken is stored as a SHA-256 hash and can be used once.
+type RefreshToken struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	SessionID primitive.ObjectID `bson:"sessionId"`
+	UserID    string             `bson:"userId"`
+	TokenHash string             `bson:"tokenHash"`
+	UsedAt    *time.Time         `bson:"usedAt,omitempty"`
+	ExpiresAt time.Time          `bson:"expiresAt"`
+	CreatedAt time.Time          `bson:"createdAt"`
+}
+
+const (
+	TokenPasswordReset      = "password_reset"
+	TokenEmailVerification  = "email_verification"
+	TokenTwoFactorChallenge = "two_factor_challenge"
+	TokenAccountUnlock      = "account_unlock"
+	TokenAccountDeletion    = "account_deletion"
+)
+
+// AccountToken is a single-use token stored as a SHA-256 hash. Purpose is a
+// password reset, an email verification or an account unlock, mailed to the
+// user, or a token returned to confirm the second step of a login or of an
+// account deletion. Attempts counts wrong second-step codes.
+type AccountToken struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	UserID    string             `bson:"userId"`
+	Purpose   string             `bson:"purpose"`
+	TokenHash string             `bson:"tokenHash"`
+	Attempts  int                `bson:"attempts,omitempty"`
+	UsedAt    *time.Time         `bson:"usedAt,omitempty"`
+	ExpiresAt time.Time          `bson:"expiresAt"`
+	CreatedAt time.Time          `bson:"createdAt"`
 }
\ No newline at end of file
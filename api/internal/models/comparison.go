@@ -0,0 +1,34 @@
+package models
+
+type ReportPeriod struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Delta compares a value across two periods. ChangePercent is null when the
+// previous value is zero.
+type Delta struct {
+	Current       float64  `json:"current"`
+	Previous      float64  `json:"previous"`
+	Change        float64  `json:"change"`
+	ChangePercent *float64 `json:"changePercent"`
+}
+
+type CategoryDelta struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+	Delta
+}
+
+// ComparisonReport compares income, expenses and spending per category in two
+// periods. Increases and Decreases rank the categories that changed the most.
+type ComparisonReport struct {
+	Current    ReportPeriod    `json:"current"`
+	Previous   ReportPeriod    `json:"previous"`
+	Income     Delta           `json:"income"`
+	Expenses   Delta           `json:"expenses"`
+	Balance    Delta           `json:"balance"`
+	Categories []CategoryDelta `json:"categories"`
+	Increases  []CategoryDelta `json:"increases"`
+	Decreases  []CategoryDelta `json:"decreases"`
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	DividendKindDividend = "dividend"
+	DividendKindJCP      = "jcp"
+	DividendKindIncome   = "rendimento"
+)
+
+// Dividend is a cash distribution ("provento") paid by a position.
+type Dividend struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Ticker         string             `bson:"ticker" json:"ticker"`
+	Kind           string             `bson:"kind" json:"kind"`
+	GrossAmount    float64            `bson:"grossAmount" json:"grossAmount"`
+	WithholdingTax float64            `bson:"withholdingTax" json:"withholdingTax"`
+	NetAmount      float64            `bson:"netAmount" json:"netAmount"`
+	PayDate        string             `bson:"payDate" json:"payDate"`
+	WorkspaceID    *string            `bson:"workspaceId,omitempty" json:"workspaceId,omitempty"`
+	CreatedAt      time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+type DividendReport struct {
+	From           string               `json:"from"`
+	To             string               `json:"to"`
+	TotalGross     float64              `json:"totalGross"`
+	TotalNet       float64              `json:"totalNet"`
+	PortfolioYield float64              `json:"portfolioYield"`
+	Assets         []AssetDividendYield `json:"assets"`
+	Months         []MonthlyDividend    `json:"months"`
+}
+
+// AssetDividendYield is the trailing 12 months yield of a ticker over its
+// current market value.
+type AssetDividendYield struct {
+	Ticker      string  `json:"ticker"`
+	Gross       float64 `json:"gross"`
+	Net         float64 `json:"net"`
+	MarketValue float64 `json:"marketValue"`
+	Yield       float64 `json:"yield"`
+}
+
+type MonthlyDividend struct {
+	Month string  `json:"month"`
+	Gross float64 `json:"gross"`
+	Net   float64 `json:"net"`
+	Yield float64 `json:"yield"`
+}
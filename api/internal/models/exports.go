@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	ExportPending = "pending"
+	ExportRunning = "running"
+	ExportReady   = "ready"
+	ExportFailed  = "failed"
+)
+
+// ExportJob builds the archive of all the data of a user. The archive and the
+// job are deleted at ExpiresAt. DownloadURL is a signed link, valid until
+// LinkExpiresAt, filled in when the archive is ready.
+type ExportJob struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID        string             `bson:"userId" json:"-"`
+	Status        string             `bson:"status" json:"status"`
+	Error         string             `bson:"error,omitempty" json:"error,omitempty"`
+	Path          string             `bson:"path,omitempty" json:"-"`
+	Size          int64              `bson:"size,omitempty" json:"size,omitempty"`
+	DownloadURL   string             `bson:"-" json:"downloadUrl,omitempty"`
+	LinkExpiresAt *time.Time         `bson:"-" json:"linkExpiresAt,omitempty"`
+	ExpiresAt     time.Time          `bson:"expiresAt" json:"expiresAt"`
+	CompletedAt   *time.Time         `bson:"completedAt,omitempty" json:"completedAt,omitempty"`
+	CreatedAt     time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// ExportManifest describes the files of the archive.
+type ExportManifest struct {
+	UserID      string               `json:"userId"`
+	GeneratedAt time.Time            `json:"generatedAt"`
+	Files       []ExportManifestFile `json:"files"`
+}
+
+type ExportManifestFile struct {
+	Name    string `json:"name"`
+	Format  string `json:"format"`
+	Records int    `json:"records"`
+}
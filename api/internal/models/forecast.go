@@ -0,0 +1,39 @@
+package models
+
+const (
+	ForecastRecurring = "recurring"
+	ForecastLoan      = "loan"
+	ForecastMaturity  = "maturity"
+	ForecastTrend     = "trend"
+)
+
+// ForecastReport projects the balance from today. Points are daily or monthly;
+// a monthly point holds the month's flows and the balance at its end.
+type ForecastReport struct {
+	StartDate         string          `json:"startDate"`
+	EndDate           string          `json:"endDate"`
+	Interval          string          `json:"interval"`
+	StartBalance      float64         `json:"startBalance"`
+	EndBalance        float64         `json:"endBalance"`
+	LowestBalance     float64         `json:"lowestBalance"`
+	LowestBalanceDate string          `json:"lowestBalanceDate"`
+	FirstNegativeDate *string         `json:"firstNegativeDate"`
+	TrendMonthly      float64         `json:"trendMonthly"`
+	Points            []ForecastPoint `json:"points"`
+	Events            []ForecastEvent `json:"events"`
+}
+
+type ForecastPoint struct {
+	Date     string  `json:"date"`
+	Inflows  float64 `json:"inflows"`
+	Outflows float64 `json:"outflows"`
+	Balance  float64 `json:"balance"`
+}
+
+// ForecastEvent is a single expected flow; Amount is negative for outflows.
+type ForecastEvent struct {
+	Date        string  `json:"date"`
+	Source      string  `json:"source"`
+	Description string  `json:"description"`
+	Amount      float64 `json:"amount"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+const (
+	IndexCDI   = "CDI"
+	IndexSelic = "SELIC"
+	IndexIPCA  = "IPCA"
+)
+
+// IndexValue is one published value of an economic index. Value is the rate for
+// the period in percent: daily for CDI and SELIC, monthly for IPCA.
+type IndexValue struct {
+	Index     string    `bson:"index" json:"index"`
+	Date      string    `bson:"date" json:"date"`
+	Value     float64   `bson:"value" json:"value"`
+	UpdatedAt time.Time `bson:"updatedAt" json:"updatedAt"`
+}
+
+// IsMonthlyIndex reports whether the index is published once per month.
+func IsMonthlyIndex(index string) bool {
+	return index == IndexIPCA
+}
+
+type ImportResult struct {
+	Imported int      `json:"imported"`
+	Skipped  int      `json:"skipped"`
+	Errors   []string `json:"errors,omitempty"`
+}
+
+type InvestmentValuation struct {
+	InvestmentID  string  `json:"investmentId"`
+	Date          string  `json:"date"`
+	Invested      float64 `json:"invested"`
+	GrossValue    float64 `json:"grossValue"`
+	Earnings      float64 `json:"earnings"`
+	IOF           float64 `json:"iof"`
+	IncomeTax     float64 `json:"incomeTax"`
+	TaxDue        float64 `json:"taxDue"`
+	NetValue      float64 `json:"netValue"`
+	Factor        float64 `json:"factor"`
+	BusinessDays  int     `json:"businessDays"`
+	LastIndexDate string  `json:"lastIndexDate,omitempty"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Transaction flags
+const (
+	FlagUnusualForCategory = "unusual_for_category"
+	FlagUnusualForMerchant = "unusual_for_merchant"
+)
+
+// Insight kinds
+const (
+	InsightLargeTransaction = "large_transaction"
+	InsightCategorySpike    = "category_spike"
+)
+
+// Insight is an entry of the insights feed. Key identifies what it is about, so
+// checking the same transaction or month again updates the insight instead of
+// repeating it. Baseline is the median of the trailing months and Score the
+// robust z-score of the amount against it.
+type Insight struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Key           string             `bson:"key" json:"-"`
+	Kind          string             `bson:"kind" json:"kind"`
+	Message       string             `bson:"message" json:"message"`
+	Date          string             `bson:"date" json:"date"`
+	TransactionID *string            `bson:"transactionId,omitempty" json:"transactionId,omitempty"`
+	Category      string             `bson:"category,omitempty" json:"category,omitempty"`
+	Merchant      string             `bson:"merchant,omitempty" json:"merchant,omitempty"`
+	Month         string             `bson:"month,omitempty" json:"month,omitempty"`
+	Amount        float64            `bson:"amount" json:"amount"`
+	Baseline      float64            `bson:"baseline" json:"baseline"`
+	Score         float64            `bson:"score" json:"score"`
+	Dismissed     bool               `bson:"dismissed" json:"dismissed"`
+	WorkspaceID   *string            `bson:"workspaceId,omitempty" json:"workspaceId,omitempty"`
+	CreatedAt     time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// SweepResult counts what an insight sweep found.
+type SweepResult struct {
+	Transactions int `json:"transactions"`
+	Flagged      int `json:"flagged"`
+	Spikes       int `json:"spikes"`
+}
@@ -0,0 +1,41 @@
+package models
+
+const (
+	LiquidityDaily      = "D+0"
+	LiquidityD30        = "D+30"
+	LiquidityAtMaturity = "maturity"
+)
+
+// LiquidityHorizons are the windows, in calendar days, of the liquidity ladder.
+var LiquidityHorizons = []int{0, 30, 90, 365}
+
+// LiquidityReport tells how much of the fixed income portfolio can be redeemed
+// within each horizon and which investments mature soon.
+type LiquidityReport struct {
+	Date       string            `json:"date"`
+	TotalValue float64           `json:"totalValue"`
+	Buckets    []LiquidityBucket `json:"buckets"`
+	Maturities []MaturityItem    `json:"maturities"`
+}
+
+// LiquidityBucket is cumulative: the 90-day bucket includes what is available
+// within 30 days. Amounts are net of redemption taxes.
+type LiquidityBucket struct {
+	Days       int     `json:"days"`
+	Amount     float64 `json:"amount"`
+	Percentage float64 `json:"percentage"`
+}
+
+// MaturityItem projects an investment to its maturity date using its stored
+// rate; the net value is after the taxes due at maturity.
+type MaturityItem struct {
+	InvestmentID        string  `json:"investmentId"`
+	Name                string  `json:"name"`
+	Type                *string `json:"type,omitempty"`
+	MaturityDate        string  `json:"maturityDate"`
+	DaysToMaturity      int     `json:"daysToMaturity"`
+	Liquidity           string  `json:"liquidity"`
+	CurrentValue        float64 `json:"currentValue"`
+	ProjectedGrossValue float64 `json:"projectedGrossValue"`
+	ProjectedNetValue   float64 `json:"projectedNetValue"`
+}
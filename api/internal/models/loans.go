@@ -0,0 +1,68 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	AmortizationSAC   = "sac"
+	AmortizationPrice = "price"
+
+	// An extra payment either keeps the installment and shortens the term or
+	// keeps the term and lowers the following installments
+	ExtraPaymentReduceTerm        = "term"
+	ExtraPaymentReduceInstallment = "installment"
+)
+
+// Loan is a financing contract. Rate is the effective annual rate in percent
+// and Term the number of monthly installments; the first one is due a month
+// after StartDate.
+type Loan struct {
+	ID                 primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name               string             `bson:"name" json:"name"`
+	System             string             `bson:"system" json:"system"`
+	Principal          float64            `bson:"principal" json:"principal"`
+	Rate               float64            `bson:"rate" json:"rate"`
+	Term               int                `bson:"term" json:"term"`
+	StartDate          string             `bson:"startDate" json:"startDate"`
+	CategoryID         *string            `bson:"categoryId,omitempty" json:"categoryId,omitempty"`
+	ExtraPayments      []ExtraPayment     `bson:"extraPayments" json:"extraPayments"`
+	PostedInstallments []int              `bson:"postedInstallments" json:"postedInstallments"`
+	WorkspaceID        *string            `bson:"workspaceId,omitempty" json:"workspaceId,omitempty"`
+	CreatedAt          time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt          time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
+
+// ExtraPayment is paid together with the given installment, after it.
+type ExtraPayment struct {
+	Installment int     `bson:"installment" json:"installment"`
+	Amount      float64 `bson:"amount" json:"amount"`
+	Mode        string  `bson:"mode" json:"mode"`
+}
+
+type LoanInstallment struct {
+	Number    int     `json:"number"`
+	Date      string  `json:"date"`
+	Payment   float64 `json:"payment"`
+	Interest  float64 `json:"interest"`
+	Principal float64 `json:"principal"`
+	Extra     float64 `json:"extra"`
+	Balance   float64 `json:"balance"`
+	Posted    bool    `json:"posted"`
+}
+
+type LoanSchedule struct {
+	Loan          Loan              `json:"loan"`
+	Term          int               `json:"term"`
+	TotalPaid     float64           `json:"totalPaid"`
+	TotalInterest float64           `json:"totalInterest"`
+	Installments  []LoanInstallment `json:"installments"`
+}
+
+// LoanPostResult lists the installments posted as expense transactions.
+type LoanPostResult struct {
+	Posted       []int `json:"posted"`
+	Transactions int   `json:"transactions"`
+}
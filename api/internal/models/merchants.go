@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Merchant groups the transactions whose descriptions normalize to its key or
+// to one of its aliases. Name is what the user sees and may be renamed.
+type Merchant struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name        string             `bson:"name" json:"name"`
+	Key         string             `bson:"key" json:"key"`
+	Aliases     []string           `bson:"aliases" json:"aliases"`
+	WorkspaceID *string            `bson:"workspaceId,omitempty" json:"workspaceId,omitempty"`
+	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt   time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
+
+type MerchantItem struct {
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+	Count int     `json:"count"`
+}
@@ -13,7 +13,11 @@ type Transaction struct {
 	Amount      float64            `bson:"amount" json:"amount"`
 	Date        string             `bson:"date" json:"date"`
 	CategoryID  *string            `bson:"categoryId,omitempty" json:"categoryId,omitempty"`
-	UserID      *string            `bson:"userId,omitempty" json:"userId,omitempty"`
+	Merchant    string             `bson:"merchant,omitempty" json:"merchant,omitempty"`
+	MerchantID  *string            `bson:"merchantId,omitempty" json:"merchantId,omitempty"`
+	MerchantKey string             `bson:"merchantKey,omitempty" json:"-"`
+	Flags       []string           `bson:"flags,omitempty" json:"flags,omitempty"`
+	WorkspaceID *string            `bson:"workspaceId,omitempty" json:"workspaceId,omitempty"`
 	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
 	UpdatedAt   time.Time          `bson:"updatedAt" json:"updatedAt"`
 }
@@ -26,7 +30,14 @@ type Investment struct {
 	MonthlyReturn float64            `bson:"monthlyReturn" json:"monthlyReturn"`
 	Date          string             `bson:"date" json:"date"`
 	Type          *string            `bson:"type,omitempty" json:"type,omitempty"`
-	UserID        *string            `bson:"userId,omitempty" json:"userId,omitempty"`
+	Indexer       *string            `bson:"indexer,omitempty" json:"indexer,omitempty"`
+	IndexPercent  float64            `bson:"indexPercent,omitempty" json:"indexPercent,omitempty"`
+	Spread        float64            `bson:"spread,omitempty" json:"spread,omitempty"`
+	MaturityDate  *string            `bson:"maturityDate,omitempty" json:"maturityDate,omitempty"`
+	Liquidity     string             `bson:"liquidity,omitempty" json:"liquidity,omitempty"`
+	Position      float64            `bson:"-" json:"position"`
+	Valuation     *InvestmentValuation `bson:"-" json:"valuation,omitempty"`
+	WorkspaceID   *string            `bson:"workspaceId,omitempty" json:"workspaceId,omitempty"`
 	CreatedAt     time.Time          `bson:"createdAt" json:"createdAt"`
 	UpdatedAt     time.Time          `bson:"updatedAt" json:"updatedAt"`
 }
@@ -62,6 +73,9 @@ type Totals struct {
 	TotalInvestments    float64 `json:"totalInvestments"`
 	TotalMonthlyReturn  float64 `json:"totalMonthlyReturn"`
 	AverageRate         float64 `json:"averageRate"`
+	TotalGrossValue     float64 `json:"totalGrossValue"`
+	TotalTaxDue         float64 `json:"totalTaxDue"`
+	TotalNetValue       float64 `json:"totalNetValue"`
 }
 
 type OverviewData struct {
@@ -91,6 +105,7 @@ type MonthlyItem struct {
 	Despesas      float64 `json:"despesas"`
 	Saldo         float64 `json:"saldo"`
 	Investimentos float64 `json:"investimentos"`
+	Proventos     float64 `json:"proventos"`
 }
 
 type CategoryItem struct {
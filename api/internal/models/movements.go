@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	MovementContribution = "contribution"
+	MovementWithdrawal   = "withdrawal"
+	MovementIncome       = "income"
+	MovementFee          = "fee"
+	MovementTax          = "tax"
+)
+
+// OutflowMovementTypes lists the movement types that reduce a position.
+var OutflowMovementTypes = []string{MovementWithdrawal, MovementFee, MovementTax}
+
+type InvestmentMovement struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	InvestmentID primitive.ObjectID `bson:"investmentId" json:"investmentId"`
+	Type         string             `bson:"type" json:"type"`
+	Amount       float64            `bson:"amount" json:"amount"`
+	Date         string             `bson:"date" json:"date"`
+	Description  string             `bson:"description,omitempty" json:"description,omitempty"`
+	WorkspaceID  *string            `bson:"workspaceId,omitempty" json:"workspaceId,omitempty"`
+	CreatedAt    time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// SignedAmount returns the movement amount with the sign it contributes to the position.
+func (m *InvestmentMovement) SignedAmount() float64 {
+	for _, t := range OutflowMovementTypes {
+		if m.Type == t {
+			return -m.Amount
+		}
+	}
+	return m.Amount
+}
+
+type InvestmentLedger struct {
+	Investment Investment           `json:"investment"`
+	Movements  []InvestmentMovement `json:"movements"`
+	Position   float64              `json:"position"`
+}
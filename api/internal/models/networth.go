@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	NetWorthAsset     = "asset"
+	NetWorthLiability = "liability"
+)
+
+// NetWorthItem is a manually tracked asset, such as a property or a car, or a
+// liability, such as a mortgage or card debt. Value is always positive; Kind
+// tells on which side of the balance sheet it goes.
+type NetWorthItem struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Kind        string             `bson:"kind" json:"kind"`
+	Name        string             `bson:"name" json:"name"`
+	Category    string             `bson:"category,omitempty" json:"category,omitempty"`
+	Value       float64            `bson:"value" json:"value"`
+	WorkspaceID *string            `bson:"workspaceId,omitempty" json:"workspaceId,omitempty"`
+	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt   time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
+
+// NetWorthSnapshot is the balance sheet of a workspace on a given day. There is
+// at most one snapshot per workspace and day.
+type NetWorthSnapshot struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Date        string             `bson:"date" json:"date"`
+	Cash        float64            `bson:"cash" json:"cash"`
+	Investments float64            `bson:"investments" json:"investments"`
+	Positions   float64            `bson:"positions" json:"positions"`
+	Assets      float64            `bson:"assets" json:"assets"`
+	Liabilities float64            `bson:"liabilities" json:"liabilities"`
+	NetWorth    float64            `bson:"netWorth" json:"netWorth"`
+	WorkspaceID *string            `bson:"workspaceId,omitempty" json:"workspaceId,omitempty"`
+	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Identity is an account at an identity provider linked to the user, who can
+// then sign in through it.
+type Identity struct {
+	Provider string    `bson:"provider" json:"provider"`
+	Subject  string    `bson:"subject" json:"-"`
+	Email    string    `bson:"email" json:"email"`
+	LinkedAt time.Time `bson:"linkedAt" json:"linkedAt"`
+}
+
+// OIDCState keeps what the callback needs to finish a sign in started at a
+// provider. UserID is set when a signed in user is linking a provider.
+type OIDCState struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	StateHash string             `bson:"stateHash"`
+	Provider  string             `bson:"provider"`
+	UserID    string             `bson:"userId,omitempty"`
+	Verifier  string             `bson:"verifier"`
+	Nonce     string             `bson:"nonce"`
+	ExpiresAt time.Time          `bson:"expiresAt"`
+	CreatedAt time.Time          `bson:"createdAt"`
+}
+
+// OIDCAuthorization is where to send the user to sign in at the provider. The
+// client keeps State to check it against the one on the callback.
+type OIDCAuthorization struct {
+	AuthorizationURL string `json:"authorizationUrl"`
+	State            string `json:"state"`
+}
+
+type OIDCCallbackRequest struct {
+	Code  string `json:"code" validate:"required"`
+	State string `json:"state" validate:"required"`
+}
@@ -0,0 +1,27 @@
+package models
+
+const (
+	BenchmarkCDI      = "CDI"
+	BenchmarkIbovespa = "IBOV"
+)
+
+// PerformanceReport holds portfolio returns for a period, as percentages.
+type PerformanceReport struct {
+	Period          string            `json:"period"`
+	From            string            `json:"from"`
+	To              string            `json:"to"`
+	StartValue      float64           `json:"startValue"`
+	EndValue        float64           `json:"endValue"`
+	NetContribution float64           `json:"netContribution"`
+	TWR             float64           `json:"twr"`
+	TWRAnnualized   float64           `json:"twrAnnualized"`
+	XIRR            *float64          `json:"xirr"`
+	Benchmarks      []BenchmarkReturn `json:"benchmarks"`
+}
+
+type BenchmarkReturn struct {
+	Name      string  `json:"name"`
+	Return    float64 `json:"return"`
+	Excess    float64 `json:"excess"`
+	Available bool    `json:"available"`
+}
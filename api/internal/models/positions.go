@@ -0,0 +1,63 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	AssetClassStock = "stock"
+	AssetClassFII   = "fii"
+	AssetClassETF   = "etf"
+
+	TradeBuy  = "buy"
+	TradeSell = "sell"
+)
+
+type Trade struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Ticker      string             `bson:"ticker" json:"ticker"`
+	AssetClass  string             `bson:"assetClass" json:"assetClass"`
+	Side        string             `bson:"side" json:"side"`
+	Quantity    float64            `bson:"quantity" json:"quantity"`
+	Price       float64            `bson:"price" json:"price"`
+	Fees        float64            `bson:"fees" json:"fees"`
+	Date        string             `bson:"date" json:"date"`
+	WorkspaceID *string            `bson:"workspaceId,omitempty" json:"workspaceId,omitempty"`
+	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// Quote is the closing price of a ticker on a day.
+type Quote struct {
+	Ticker    string    `bson:"ticker" json:"ticker"`
+	Date      string    `bson:"date" json:"date"`
+	Close     float64   `bson:"close" json:"close"`
+	UpdatedAt time.Time `bson:"updatedAt" json:"updatedAt"`
+}
+
+// Position is the holding of a ticker derived from its trades, valued at the
+// latest imported quote.
+type Position struct {
+	Ticker        string       `json:"ticker"`
+	AssetClass    string       `json:"assetClass"`
+	Quantity      float64      `json:"quantity"`
+	AverageCost   float64      `json:"averageCost"`
+	TotalCost     float64      `json:"totalCost"`
+	LastPrice     float64      `json:"lastPrice"`
+	PriceDate     string       `json:"priceDate,omitempty"`
+	MarketValue   float64      `json:"marketValue"`
+	UnrealizedPnL float64      `json:"unrealizedPnl"`
+	RealizedPnL   float64      `json:"realizedPnl"`
+	Sales         []SaleResult `json:"sales"`
+}
+
+// SaleResult is the realized result of a sell trade at the average cost method.
+type SaleResult struct {
+	TradeID     string  `json:"tradeId"`
+	Date        string  `json:"date"`
+	Quantity    float64 `json:"quantity"`
+	Price       float64 `json:"price"`
+	AverageCost float64 `json:"averageCost"`
+	RealizedPnL float64 `json:"realizedPnl"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	FrequencyWeekly  = "weekly"
+	FrequencyMonthly = "monthly"
+	FrequencyYearly  = "yearly"
+)
+
+// RecurringItem is a scheduled income or expense, such as a salary, rent or a
+// subscription. It repeats from StartDate at the given frequency until EndDate,
+// when set.
+type RecurringItem struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Type        string             `bson:"type" json:"type"`
+	Description string             `bson:"description" json:"description"`
+	Amount      float64            `bson:"amount" json:"amount"`
+	Frequency   string             `bson:"frequency" json:"frequency"`
+	StartDate   string             `bson:"startDate" json:"startDate"`
+	EndDate     *string            `bson:"endDate,omitempty" json:"endDate,omitempty"`
+	CategoryID  *string            `bson:"categoryId,omitempty" json:"categoryId,omitempty"`
+	WorkspaceID *string            `bson:"workspaceId,omitempty" json:"workspaceId,omitempty"`
+	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt   time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
@@ -11,7 +11,90 @@ type CreateTransactionRequest struct {
 type CreateInvestmentRequest struct {
 	Name   string  `json:"name" validate:"required,min=1,max=255"`
 	Amount float64 `json:"amount" validate:"required,gt=0"`
-	Rate   float64 `json:"rate" validate:"required,gte=0"`
+	Rate   float64 `json:"rate" validate:"required_without=Indexer,gte=0"`
 	Date   string  `json:"date" validate:"required"`
 	Type   *string `json:"type,omitempty"`
-}
\ No newline at end of file
+
+	// Indexed fixed income: "110% do CDI" is IndexPercent=110, "IPCA + 6%" is Spread=6
+	Indexer      *string `json:"indexer,omitempty" validate:"omitempty,oneof=CDI SELIC IPCA"`
+	IndexPercent float64 `json:"indexPercent,omitempty" validate:"gte=0"`
+	Spread       float64 `json:"spread,omitempty" validate:"gte=-100"`
+
+	// Liquidity is D+0 (daily), D+30 or only at maturity
+	MaturityDate *string `json:"maturityDate,omitempty" validate:"required_if=Liquidity maturity"`
+	Liquidity    string  `json:"liquidity,omitempty" validate:"omitempty,oneof=D+0 D+30 maturity"`
+}
+
+type CreateMovementRequest struct {
+	Type        string  `json:"type" validate:"required,oneof=contribution withdrawal income fee tax"`
+	Amount      float64 `json:"amount" validate:"required,gt=0"`
+	Date        string  `json:"date" validate:"required"`
+	Description string  `json:"description,omitempty" validate:"max=255"`
+}
+
+type CreateTradeRequest struct {
+	Ticker     string  `json:"ticker" validate:"required,min=4,max=12"`
+	AssetClass string  `json:"assetClass" validate:"required,oneof=stock fii etf"`
+	Side       string  `json:"side" validate:"required,oneof=buy sell"`
+	Quantity   float64 `json:"quantity" validate:"required,gt=0"`
+	Price      float64 `json:"price" validate:"required,gt=0"`
+	Fees       float64 `json:"fees" validate:"gte=0"`
+	Date       string  `json:"date" validate:"required"`
+}
+
+type CreateDividendRequest struct {
+	Ticker         string   `json:"ticker" validate:"required,min=4,max=12"`
+	Kind           string   `json:"kind" validate:"required,oneof=dividend jcp rendimento"`
+	GrossAmount    float64  `json:"grossAmount" validate:"required,gt=0"`
+	WithholdingTax *float64 `json:"withholdingTax,omitempty" validate:"omitempty,gte=0"`
+	PayDate        string   `json:"payDate" validate:"required"`
+}
+
+type SetAllocationTargetsRequest struct {
+	GroupBy      string             `json:"groupBy" validate:"required,oneof=type assetClass"`
+	Targets      []AllocationTarget `json:"targets" validate:"required,min=1,dive"`
+	Tolerance    float64            `json:"tolerance" validate:"gte=0,lte=100"`
+	MinTradeSize float64            `json:"minTradeSize" validate:"gte=0"`
+}
+
+type NetWorthItemRequest struct {
+	Kind     string  `json:"kind" validate:"required,oneof=asset liability"`
+	Name     string  `json:"name" validate:"required,min=1,max=255"`
+	Category string  `json:"category,omitempty" validate:"max=100"`
+	Value    float64 `json:"value" validate:"gte=0"`
+}
+
+type CreateLoanRequest struct {
+	Name       string  `json:"name" validate:"required,min=1,max=255"`
+	System     string  `json:"system" validate:"required,oneof=sac price"`
+	Principal  float64 `json:"principal" validate:"required,gt=0"`
+	Rate       float64 `json:"rate" validate:"gte=0,lte=1000"`
+	Term       int     `json:"term" validate:"required,gt=0,lte=600"`
+	StartDate  string  `json:"startDate" validate:"required"`
+	CategoryID *string `json:"categoryId,omitempty"`
+}
+
+type ExtraPaymentRequest struct {
+	Installment int     `json:"installment" validate:"required,gt=0"`
+	Amount      float64 `json:"amount" validate:"required,gt=0"`
+	Mode        string  `json:"mode" validate:"required,oneof=term installment"`
+}
+
+type RecurringItemRequest struct {
+	Type        string  `json:"type" validate:"required,oneof=income expense"`
+	Description string  `json:"description" validate:"required,min=1,max=255"`
+	Amount      float64 `json:"amount" validate:"required,gt=0"`
+	Frequency   string  `json:"frequency" validate:"required,oneof=weekly monthly yearly"`
+	StartDate   string  `json:"startDate" validate:"required"`
+	EndDate     *string `json:"endDate,omitempty"`
+	CategoryID  *string `json:"categoryId,omitempty"`
+}
+
+type ConfirmSubscriptionRequest struct {
+	Merchant string `json:"merchant" validate:"required"`
+}
+
+type UpdateMerchantRequest struct {
+	Name    string   `json:"name" validate:"required,min=1,max=255"`
+	Aliases []string `json:"aliases" validate:"dive,required,max=255"`
+}
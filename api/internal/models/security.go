@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	SecurityAccountLocked    = "account_locked"
+	SecurityAccountUnlocked  = "account_unlocked"
+	SecurityIPLocked         = "ip_locked"
+	SecurityPasswordReset    = "password_reset"
+	SecurityPasswordChanged  = "password_changed"
+	SecurityEmailChanged     = "email_changed"
+	SecurityIdentityLinked   = "identity_linked"
+	SecurityIdentityUnlinked = "identity_unlinked"
+	SecurityTwoFactorLocked  = "two_factor_locked"
+)
+
+// LoginThrottle counts the failed logins of one key, an email, an IP or the
+// second factor of a user, within a window that restarts with each failure.
+type LoginThrottle struct {
+	Key           string     `bson:"key"`
+	Failures      int        `bson:"failures"`
+	LastFailureAt time.Time  `bson:"lastFailureAt"`
+	LockedUntil   *time.Time `bson:"lockedUntil,omitempty"`
+	ExpiresAt     time.Time  `bson:"expiresAt"`
+}
+
+// SecurityEvent is an entry of the security audit log.
+type SecurityEvent struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Event     string             `bson:"event" json:"event"`
+	UserID    *string            `bson:"userId,omitempty" json:"-"`
+	Email     string             `bson:"email,omitempty" json:"email,omitempty"`
+	IP        string             `bson:"ip,omitempty" json:"ip,omitempty"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+}
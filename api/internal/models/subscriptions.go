@@ -0,0 +1,21 @@
+package models
+
+// Subscription is a charge detected repeating in the transaction history. The
+// merchant is the one shared by the charges; Amount is the latest charge and
+// PreviousAmount the one before it.
+type Subscription struct {
+	Merchant        string  `json:"merchant"`
+	Description     string  `json:"description"`
+	Frequency       string  `json:"frequency"`
+	Amount          float64 `json:"amount"`
+	PreviousAmount  float64 `json:"previousAmount"`
+	PriceIncrease   bool    `json:"priceIncrease"`
+	Charges         int     `json:"charges"`
+	FirstCharge     string  `json:"firstCharge"`
+	LastCharge      string  `json:"lastCharge"`
+	NextCharge      string  `json:"nextCharge"`
+	AnnualCost      float64 `json:"annualCost"`
+	Active          bool    `json:"active"`
+	CategoryID      *string `json:"categoryId,omitempty"`
+	RecurringItemID *string `json:"recurringItemId,omitempty"`
+}
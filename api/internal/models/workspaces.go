@@ -0,0 +1,78 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Workspace roles, from the most to the least privileged. Owners manage the
+// workspace and its members, editors change its data and viewers only read
+// it.
+const (
+	RoleOwner  = "owner"
+	RoleEditor = "editor"
+	RoleViewer = "viewer"
+)
+
+// WorkspaceHeader selects the workspace of a request. Without it requests go
+// to the user's personal workspace.
+const WorkspaceHeader = "X-Workspace-ID"
+
+// Workspace owns the financial records: transactions, investments, loans and
+// the rest. Every user has a personal workspace, whose ID is the user's ID,
+// and can share others with a household.
+type Workspace struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name      string             `bson:"name" json:"name"`
+	Personal  bool               `bson:"personal" json:"personal"`
+	Role      string             `bson:"-" json:"role,omitempty"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
+
+// WorkspaceMember gives a user a role in a workspace. Name and Email are
+// filled in from the user for listings.
+type WorkspaceMember struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	WorkspaceID string             `bson:"workspaceId" json:"workspaceId"`
+	UserID      string             `bson:"userId" json:"userId"`
+	Role        string             `bson:"role" json:"role"`
+	Name        string             `bson:"-" json:"name,omitempty"`
+	Email       string             `bson:"-" json:"email,omitempty"`
+	JoinedAt    time.Time          `bson:"joinedAt" json:"joinedAt"`
+}
+
+// WorkspaceInvitation is mailed to someone who joins the workspace with Role
+// on accepting it. Only the hash of the token is stored.
+type WorkspaceInvitation struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	WorkspaceID string             `bson:"workspaceId" json:"workspaceId"`
+	Email       string             `bson:"email" json:"email"`
+	Role        string             `bson:"role" json:"role"`
+	TokenHash   string             `bson:"tokenHash" json:"-"`
+	InvitedBy   string             `bson:"invitedBy" json:"invitedBy"`
+	ExpiresAt   time.Time          `bson:"expiresAt" json:"expiresAt"`
+	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+type CreateWorkspaceRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+type UpdateWorkspaceRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+type InviteMemberRequest struct {
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role" validate:"required,oneof=owner editor viewer"`
+}
+
+type UpdateMemberRequest struct {
+	Role string `json:"role" validate:"required,oneof=owner editor viewer"`
+}
+
+type AcceptInvitationRequest struct {
+	Token string `json:"token" validate:"required"`
+}
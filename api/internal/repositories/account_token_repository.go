@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"financial-api/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type AccountTokenRepository struct {
+	collection *mongo.Collection
+}
+
+func NewAccountTokenRepository(db *mongo.Database) *AccountTokenRepository {
+	return &AccountTokenRepository{
+		collection: db.Collection("account_tokens"),
+	}
+}
+
+// Replace stores the token after deleting the unused tokens of the user for
+// the same purpose, so only the latest email works.
+func (r *AccountTokenRepository) Replace(token *models.AccountToken) error {
+	_, err := r.collection.DeleteMany(context.Background(), bson.M{
+		"userId":  token.UserID,
+		"purpose": token.Purpose,
+		"usedAt":  bson.M{"$exists": false},
+	})
+	if err != nil {
+		return err
+	}
+
+	token.CreatedAt = time.Now()
+	result, err := r.collection.InsertOne(context.Background(), token)
+	if err != nil {
+		return err
+	}
+
+	token.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// Consume spends an unexpired, unused token, returning mongo.ErrNoDocuments
+// when there is none.
+func (r *AccountTokenRepository) Consume(tokenHash, purpose string) (*models.AccountToken, error) {
+	filter := bson.M{
+		"tokenHash": tokenHash,
+		"purpose":   purpose,
+		"usedAt":    bson.M{"$exists": false},
+		"expiresAt": bson.M{"$gt": time.Now()},
+	}
+
+	var token models.AccountToken
+	err := r.collection.FindOneAndUpdate(context.Background(), filter, bson.M{"$set": bson.M{"usedAt": time.Now()}}).Decode(&token)
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Find returns an unexpired, unused token without spending it.
+func (r *AccountTokenRepository) Find(tokenHash, purpose string) (*models.AccountToken, error) {
+	filter := bson.M{
+		"tokenHash": tokenHash,
+		"purpose":   purpose,
+		"usedAt":    bson.M{"$exists": false},
+		"expiresAt": bson.M{"$gt": time.Now()},
+	}
+
+	var token models.AccountToken
+	if err := r.collection.FindOne(context.Background(), filter).Decode(&token); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// MarkUsed spends the token, reporting false when it was already spent.
+func (r *AccountTokenRepository) MarkUsed(id primitive.ObjectID) (bool, error) {
+	filter := bson.M{"_id": id, "usedAt": bson.M{"$exists": false}}
+	result, err := r.collection.UpdateOne(context.Background(), filter, bson.M{"$set": bson.M{"usedAt": time.Now()}})
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// AddAttempt counts a failed use of the token and returns the new count.
+func (r *AccountTokenRepository) AddAttempt(id primitive.ObjectID) (int, error) {
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var token models.AccountToken
+	err := r.collection.FindOneAndUpdate(context.Background(), bson.M{"_id": id}, bson.M{"$inc": bson.M{"attempts": 1}}, opts).Decode(&token)
+	if err != nil {
+		return 0, err
+	}
+	return token.Attempts, nil
+}
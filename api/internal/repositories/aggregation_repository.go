@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"sort"
 
 	"financial-api/internal/models"
 
@@ -13,6 +14,8 @@ type AggregationRepository struct {
 	transactionCollection *mongo.Collection
 	investmentCollection  *mongo.Collection
 	categoryCollection    *mongo.Collection
+	tradeCollection       *mongo.Collection
+	dividendCollection    *mongo.Collection
 }
 
 func NewAggregationRepository(db *mongo.Database) *AggregationRepository {
@@ -20,13 +23,15 @@ func NewAggregationRepository(db *mongo.Database) *AggregationRepository {
 		transactionCollection: db.Collection("transactions"),
 		investmentCollection:  db.Collection("investments"),
 		categoryCollection:    db.Collection("categories"),
+		tradeCollection:       db.Collection("trades"),
+		dividendCollection:    db.Collection("dividends"),
 	}
 }
 
-func (r *AggregationRepository) GetMonthlyData(userID string) ([]models.MonthlyItem, error) {
+func (r *AggregationRepository) GetMonthlyData(workspaceID string) ([]models.MonthlyItem, error) {
 	pipeline := []bson.M{
 		{
-			"$match": bson.M{"userId": userID},
+			"$match": bson.M{"workspaceId": workspaceID},
 		},
 		{
 			"$addFields": bson.M{
@@ -91,6 +96,11 @@ func (r *AggregationRepository) GetMonthlyData(userID string) ([]models.MonthlyI
 		return nil, err
 	}
 
+	proventos, err := r.getMonthlyDividends(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
 	monthlyData := make([]models.MonthlyItem, len(results))
 	for i, result := range results {
 		monthlyData[i] = models.MonthlyItem{
@@ -99,23 +109,123 @@ func (r *AggregationRepository) GetMonthlyData(userID string) ([]models.MonthlyI
 			Despesas:      result.Despesas,
 			Saldo:         result.Saldo,
 			Investimentos: 0, // TODO: Add investment data per month
+			Proventos:     proventos[result.ID],
 		}
+		delete(proventos, result.ID)
+	}
+
+	// Months with dividends but no transactions
+	for month, value := range proventos {
+		monthlyData = append(monthlyData, models.MonthlyItem{Month: month, Proventos: value})
+	}
+	sort.Slice(monthlyData, func(i, j int) bool { return monthlyData[i].Month < monthlyData[j].Month })
+	if len(monthlyData) > 12 {
+		monthlyData = monthlyData[:12]
 	}
 
 	return monthlyData, nil
 }
 
-func (r *AggregationRepository) GetExpenseCategories(userID string) ([]models.CategoryItem, error) {
+// GetRecentMonthlyExpenses returns the expenses of each of the latest months
+// with any before the given date, newest first.
+func (r *AggregationRepository) GetRecentMonthlyExpenses(workspaceID, before string, months int) ([]float64, error) {
 	pipeline := []bson.M{
 		{
-			"$match": bson.M{"type": "expense", "userId": userID},
+			"$match": bson.M{"workspaceId": workspaceID, "type": "expense", "date": bson.M{"$lt": before}},
+		},
+		{
+			"$group": bson.M{
+				"_id":   bson.M{"$substrBytes": []interface{}{"$date", 0, 7}},
+				"total": bson.M{"$sum": "$amount"},
+			},
+		},
+		{
+			"$sort": bson.M{"_id": -1},
 		},
 		{
+			"$limit": months,
+		},
+	}
+
+	cursor, err := r.transactionCollection.Aggregate(context.Background(), pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	var results []struct {
+		Total float64 `bson:"total"`
+	}
+	if err := cursor.All(context.Background(), &results); err != nil {
+		return nil, err
+	}
+
+	expenses := make([]float64, len(results))
+	for i, result := range results {
+		expenses[i] = result.Total
+	}
+	return expenses, nil
+}
+
+// getMonthlyDividends returns the net dividends received per month.
+func (r *AggregationRepository) getMonthlyDividends(workspaceID string) (map[string]float64, error) {
+	pipeline := []bson.M{
+		{
+			"$match": bson.M{"workspaceId": workspaceID},
+		},
+		{
+			"$group": bson.M{
+				"_id":   bson.M{"$substrBytes": []interface{}{"$payDate", 0, 7}},
+				"total": bson.M{"$sum": "$netAmount"},
+			},
+		},
+	}
+
+	cursor, err := r.dividendCollection.Aggregate(context.Background(), pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	var results []struct {
+		ID    string  `bson:"_id"`
+		Total float64 `bson:"total"`
+	}
+	if err := cursor.All(context.Background(), &results); err != nil {
+		return nil, err
+	}
+
+	proventos := make(map[string]float64, len(results))
+	for _, result := range results {
+		proventos[result.ID] = result.Total
+	}
+
+	return proventos, nil
+}
+
+func (r *AggregationRepository) GetExpenseCategories(workspaceID string) ([]models.CategoryItem, error) {
+	return r.GetCategoryTotals(workspaceID, "expense", "", "")
+}
+
+// GetCategoryTotals sums the transactions of a type by category within
+// [from, to], largest first. Empty bounds are not filtered.
+func (r *AggregationRepository) GetCategoryTotals(workspaceID, transactionType, from, to string) ([]models.CategoryItem, error) {
+	pipeline := []bson.M{
+		{
+			"$match": transactionMatch(workspaceID, transactionType, from, to),
+		},
+		{
+			// Transactions keep the category id as a hex string
 			"$lookup": bson.M{
-				"from":         "categories",
-				"localField":   "categoryId",
-				"foreignField": "_id",
-				"as":           "category",
+				"from": "categories",
+				"let":  bson.M{"categoryId": "$categoryId"},
+				"pipeline": []bson.M{
+					{"$match": bson.M{"$expr": bson.M{"$eq": []interface{}{
+						"$_id",
+						bson.M{"$convert": bson.M{"input": "$$categoryId", "to": "objectId", "onError": nil, "onNull": nil}},
+					}}}},
+				},
+				"as": "category",
 			},
 		},
 		{
@@ -157,8 +267,10 @@ func (r *AggregationRepository) GetExpenseCategories(userID string) ([]models.Ca
 		return nil, err
 	}
 
-	categories := make([]models.CategoryItem, len(results))
-	for i, result := range results {
+	// Transactions without a category are grouped under Outros
+	categories := []models.CategoryItem{}
+	index := map[string]int{}
+	for _, result := range results {
 		name := result.ID.Name
 		if name == "" {
 			name = "Outros"
@@ -168,24 +280,35 @@ func (r *AggregationRepository) GetExpenseCategories(userID string) ([]models.Ca
 			color = "#6b7280"
 		}
 
-		categories[i] = models.CategoryItem{
+		if i, ok := index[name]; ok {
+			categories[i].Value += result.Total
+			continue
+		}
+		index[name] = len(categories)
+		categories = append(categories, models.CategoryItem{
 			Name:  name,
 			Value: result.Total,
 			Color: color,
-		}
+		})
 	}
 
+	sort.SliceStable(categories, func(i, j int) bool {
+		return categories[i].Value > categories[j].Value
+	})
+
 	return categories, nil
 }
 
-func (r *AggregationRepository) GetInvestmentTypes(userID string) ([]models.InvestmentType, error) {
+// GetTopMerchants ranks merchants by expense total. Transactions created
+// before merchants were tracked are grouped by their description.
+func (r *AggregationRepository) GetTopMerchants(workspaceID, from, to string, limit int) ([]models.MerchantItem, error) {
 	pipeline := []bson.M{
 		{
-			"$match": bson.M{"userId": userID},
+			"$match": transactionMatch(workspaceID, "expense", from, to),
 		},
 		{
 			"$group": bson.M{
-				"_id":   "$type",
+				"_id":   bson.M{"$ifNull": []interface{}{"$merchant", "$description"}},
 				"total": bson.M{"$sum": "$amount"},
 				"count": bson.M{"$sum": 1},
 			},
@@ -193,16 +316,19 @@ func (r *AggregationRepository) GetInvestmentTypes(userID string) ([]models.Inve
 		{
 			"$sort": bson.M{"total": -1},
 		},
+		{
+			"$limit": limit,
+		},
 	}
 
-	cursor, err := r.investmentCollection.Aggregate(context.Background(), pipeline)
+	cursor, err := r.transactionCollection.Aggregate(context.Background(), pipeline)
 	if err != nil {
 		return nil, err
 	}
 	defer cursor.Close(context.Background())
 
 	var results []struct {
-		ID    *string `bson:"_id"`
+		Name  string  `bson:"_id"`
 		Total float64 `bson:"total"`
 		Count int     `bson:"count"`
 	}
@@ -211,6 +337,109 @@ func (r *AggregationRepository) GetInvestmentTypes(userID string) ([]models.Inve
 		return nil, err
 	}
 
+	merchants := make([]models.MerchantItem, 0, len(results))
+	for _, result := range results {
+		merchants = append(merchants, models.MerchantItem{
+			Name:  result.Name,
+			Value: result.Total,
+			Count: result.Count,
+		})
+	}
+
+	return merchants, nil
+}
+
+// GetPeriodTotals returns the income and the expenses within [from, to].
+func (r *AggregationRepository) GetPeriodTotals(workspaceID, from, to string) (float64, float64, error) {
+	pipeline := []bson.M{
+		{
+			"$match": transactionMatch(workspaceID, "", from, to),
+		},
+		{
+			"$group": bson.M{
+				"_id":   "$type",
+				"total": bson.M{"$sum": "$amount"},
+			},
+		},
+	}
+
+	cursor, err := r.transactionCollection.Aggregate(context.Background(), pipeline)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer cursor.Close(context.Background())
+
+	var results []struct {
+		ID    string  `bson:"_id"`
+		Total float64 `bson:"total"`
+	}
+
+	if err := cursor.All(context.Background(), &results); err != nil {
+		return 0, 0, err
+	}
+
+	var income, expenses float64
+	for _, result := range results {
+		switch result.ID {
+		case "income":
+			income = result.Total
+		case "expense":
+			expenses = result.Total
+		}
+	}
+
+	return income, expenses, nil
+}
+
+func transactionMatch(workspaceID, transactionType, from, to string) bson.M {
+	match := bson.M{"workspaceId": workspaceID}
+	if transactionType != "" {
+		match["type"] = transactionType
+	}
+	dateFilter := bson.M{}
+	if from != "" {
+		dateFilter["$gte"] = from
+	}
+	if to != "" {
+		dateFilter["$lte"] = to
+	}
+	if len(dateFilter) > 0 {
+		match["date"] = dateFilter
+	}
+	return match
+}
+
+const (
+	GroupByType       = "type"
+	GroupByAssetClass = "assetClass"
+)
+
+var assetClassNames = map[string]string{
+	models.AssetClassStock: "Ações",
+	models.AssetClassFII:   "FIIs",
+	models.AssetClassETF:   "ETFs",
+}
+
+type investmentGroup struct {
+	ID    *string `bson:"_id"`
+	Total float64 `bson:"total"`
+}
+
+// GetInvestmentTypes breaks the portfolio down by investment type or, with
+// GroupByAssetClass, by asset class: fixed income as a whole plus each variable
+// income class valued at market price.
+func (r *AggregationRepository) GetInvestmentTypes(workspaceID, groupBy string) ([]models.InvestmentType, error) {
+	var results []investmentGroup
+	var err error
+	if groupBy == GroupByAssetClass {
+		results, err = r.groupByAssetClass(workspaceID)
+	} else {
+		results, err = r.groupFixedIncome(workspaceID, "$type")
+	}
+	if err != nil {
+		return nil, err
+	}
+
 	// Calculate total for percentages
 	var grandTotal float64
 	for _, result := range results {
@@ -242,4 +471,121 @@ func (r *AggregationRepository) GetInvestmentTypes(userID string) ([]models.Inve
 	}
 
 	return investmentTypes, nil
-}
\ No newline at end of file
+}
+
+func (r *AggregationRepository) groupFixedIncome(workspaceID string, groupKey interface{}) ([]investmentGroup, error) {
+	pipeline := []bson.M{
+		{
+			"$match": bson.M{"workspaceId": workspaceID},
+		},
+	}
+	pipeline = append(pipeline, positionStages()...)
+	pipeline = append(pipeline,
+		bson.M{
+			"$group": bson.M{
+				"_id":   groupKey,
+				"total": bson.M{"$sum": "$position"},
+				"count": bson.M{"$sum": 1},
+			},
+		},
+		bson.M{
+			"$sort": bson.M{"total": -1},
+		},
+	)
+
+	cursor, err := r.investmentCollection.Aggregate(context.Background(), pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	var results []investmentGroup
+	if err := cursor.All(context.Background(), &results); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func (r *AggregationRepository) groupByAssetClass(workspaceID string) ([]investmentGroup, error) {
+	fixedIncome, err := r.groupFixedIncome(workspaceID, "Renda Fixa")
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline := []bson.M{
+		{
+			"$match": bson.M{"workspaceId": workspaceID},
+		},
+		{
+			"$sort": bson.D{{Key: "date", Value: 1}, {Key: "createdAt", Value: 1}},
+		},
+		{
+			"$group": bson.M{
+				"_id":        "$ticker",
+				"assetClass": bson.M{"$last": "$assetClass"},
+				"lastPrice":  bson.M{"$last": "$price"},
+				"quantity": bson.M{
+					"$sum": bson.M{
+						"$cond": bson.M{
+							"if":   bson.M{"$eq": []interface{}{"$side", models.TradeBuy}},
+							"then": "$quantity",
+							"else": bson.M{"$multiply": []interface{}{"$quantity", -1}},
+						},
+					},
+				},
+			},
+		},
+		{
+			"$match": bson.M{"quantity": bson.M{"$gt": 0}},
+		},
+		{
+			"$lookup": bson.M{
+				"from": "quotes",
+				"let":  bson.M{"ticker": "$_id"},
+				"pipeline": []bson.M{
+					{"$match": bson.M{"$expr": bson.M{"$eq": []interface{}{"$ticker", "$$ticker"}}}},
+					{"$sort": bson.M{"date": -1}},
+					{"$limit": 1},
+				},
+				"as": "quote",
+			},
+		},
+		{
+			"$addFields": bson.M{
+				// Without a quote the position is valued at the last traded price
+				"price": bson.M{"$ifNull": []interface{}{bson.M{"$arrayElemAt": []interface{}{"$quote.close", 0}}, "$lastPrice"}},
+			},
+		},
+		{
+			"$group": bson.M{
+				"_id":   "$assetClass",
+				"total": bson.M{"$sum": bson.M{"$multiply": []interface{}{"$quantity", "$price"}}},
+			},
+		},
+	}
+
+	cursor, err := r.tradeCollection.Aggregate(context.Background(), pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	var variableIncome []investmentGroup
+	if err := cursor.All(context.Background(), &variableIncome); err != nil {
+		return nil, err
+	}
+
+	for i := range variableIncome {
+		if variableIncome[i].ID == nil {
+			continue
+		}
+		if name, ok := assetClassNames[*variableIncome[i].ID]; ok {
+			variableIncome[i].ID = &name
+		}
+	}
+
+	results := append(fixedIncome, variableIncome...)
+	sort.Slice(results, func(i, j int) bool { return results[i].Total > results[j].Total })
+	return results, nil
+}
@@ -0,0 +1,40 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"financial-api/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type AllocationRepository struct {
+	collection *mongo.Collection
+}
+
+func NewAllocationRepository(db *mongo.Database) *AllocationRepository {
+	return &AllocationRepository{
+		collection: db.Collection("allocation_targets"),
+	}
+}
+
+// Save replaces the targets of the workspace.
+func (r *AllocationRepository) Save(targets *models.AllocationTargets, workspaceID string) error {
+	targets.UpdatedAt = time.Now()
+	targets.WorkspaceID = &workspaceID
+
+	opts := options.FindOneAndReplace().SetUpsert(true).SetReturnDocument(options.After)
+	return r.collection.FindOneAndReplace(context.Background(), bson.M{"workspaceId": workspaceID}, targets, opts).Decode(targets)
+}
+
+func (r *AllocationRepository) FindByWorkspace(workspaceID string) (*models.AllocationTargets, error) {
+	var targets models.AllocationTargets
+	err := r.collection.FindOne(context.Background(), bson.M{"workspaceId": workspaceID}).Decode(&targets)
+	if err != nil {
+		return nil, err
+	}
+	return &targets, nil
+}
@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"financial-api/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type APIKeyRepository struct {
+	collection *mongo.Collection
+}
+
+func NewAPIKeyRepository(db *mongo.Database) *APIKeyRepository {
+	return &APIKeyRepository{
+		collection: db.Collection("api_keys"),
+	}
+}
+
+func (r *APIKeyRepository) Create(key *models.APIKey) error {
+	key.CreatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(context.Background(), key)
+	if err != nil {
+		return err
+	}
+
+	key.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *APIKeyRepository) FindByUser(userID string) ([]models.APIKey, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cursor, err := r.collection.Find(context.Background(), bson.M{"userId": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	keys := []models.APIKey{}
+	if err := cursor.All(context.Background(), &keys); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (r *APIKeyRepository) FindByHash(keyHash string) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := r.collection.FindOne(context.Background(), bson.M{"keyHash": keyHash}).Decode(&key); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// Touch records a use of the key, at most once per interval to spare a write
+// on every request.
+func (r *APIKeyRepository) Touch(id primitive.ObjectID, interval time.Duration) error {
+	now := time.Now()
+	filter := bson.M{
+		"_id": id,
+		"$or": []bson.M{
+			{"lastUsedAt": bson.M{"$exists": false}},
+			{"lastUsedAt": bson.M{"$lt": now.Add(-interval)}},
+		},
+	}
+	_, err := r.collection.UpdateOne(context.Background(), filter, bson.M{"$set": bson.M{"lastUsedAt": now}})
+	return err
+}
+
+// DeleteAll revokes every key of the user.
+func (r *APIKeyRepository) DeleteAll(userID string) error {
+	_, err := r.collection.DeleteMany(context.Background(), bson.M{"userId": userID})
+	return err
+}
+
+// Delete revokes a key of the user. It returns mongo.ErrNoDocuments when the
+// user has no such key.
+func (r *APIKeyRepository) Delete(id, userID string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return mongo.ErrNoDocuments
+	}
+
+	result, err := r.collection.DeleteOne(context.Background(), bson.M{"_id": objectID, "userId": userID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"financial-api/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type DividendRepository struct {
+	collection *mongo.Collection
+}
+
+func NewDividendRepository(db *mongo.Database) *DividendRepository {
+	return &DividendRepository{
+		collection: db.Collection("dividends"),
+	}
+}
+
+func (r *DividendRepository) Create(dividend *models.Dividend, workspaceID string) error {
+	dividend.CreatedAt = time.Now()
+	dividend.WorkspaceID = &workspaceID
+
+	result, err := r.collection.InsertOne(context.Background(), dividend)
+	if err != nil {
+		return err
+	}
+
+	dividend.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// FindByWorkspace returns the dividends paid in [from, to], most recent first.
+// Empty bounds and ticker are not filtered.
+func (r *DividendRepository) FindByWorkspace(workspaceID, ticker, from, to string) ([]models.Dividend, error) {
+	filter := bson.M{"workspaceId": workspaceID}
+	if ticker != "" {
+		filter["ticker"] = ticker
+	}
+	dateFilter := bson.M{}
+	if from != "" {
+		dateFilter["$gte"] = from
+	}
+	if to != "" {
+		dateFilter["$lte"] = to
+	}
+	if len(dateFilter) > 0 {
+		filter["payDate"] = dateFilter
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "payDate", Value: -1}})
+	cursor, err := r.collection.Find(context.Background(), filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	dividends := []models.Dividend{}
+	if err := cursor.All(context.Background(), &dividends); err != nil {
+		return nil, err
+	}
+
+	return dividends, nil
+}
@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"financial-api/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ExportRepository struct {
+	collection *mongo.Collection
+}
+
+func NewExportRepository(db *mongo.Database) *ExportRepository {
+	return &ExportRepository{
+		collection: db.Collection("data_exports"),
+	}
+}
+
+func (r *ExportRepository) Create(job *models.ExportJob) error {
+	job.CreatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(context.Background(), job)
+	if err != nil {
+		return err
+	}
+
+	job.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// FindByID returns the job, of the user unless userID is empty.
+func (r *ExportRepository) FindByID(id, userID string) (*models.ExportJob, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, mongo.ErrNoDocuments
+	}
+
+	filter := bson.M{"_id": objectID}
+	if userID != "" {
+		filter["userId"] = userID
+	}
+
+	var job models.ExportJob
+	if err := r.collection.FindOne(context.Background(), filter).Decode(&job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *ExportRepository) FindByUser(userID string) ([]models.ExportJob, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	return r.find(bson.M{"userId": userID}, opts)
+}
+
+// FindActive returns the pending or running job of the user, if any.
+func (r *ExportRepository) FindActive(userID string) (*models.ExportJob, error) {
+	filter := bson.M{"userId": userID, "status": bson.M{"$in": []string{models.ExportPending, models.ExportRunning}}}
+
+	var job models.ExportJob
+	if err := r.collection.FindOne(context.Background(), filter).Decode(&job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// FindExpired returns the jobs past their expiry.
+func (r *ExportRepository) FindExpired() ([]models.ExportJob, error) {
+	return r.find(bson.M{"expiresAt": bson.M{"$lte": time.Now()}}, options.Find())
+}
+
+// FindInterrupted returns the jobs left pending or running by a restart.
+func (r *ExportRepository) FindInterrupted(before time.Time) ([]models.ExportJob, error) {
+	filter := bson.M{
+		"status":    bson.M{"$in": []string{models.ExportPending, models.ExportRunning}},
+		"createdAt": bson.M{"$lt": before},
+	}
+	return r.find(filter, options.Find())
+}
+
+func (r *ExportRepository) Update(job *models.ExportJob) error {
+	update := bson.M{"$set": bson.M{
+		"status":      job.Status,
+		"error":       job.Error,
+		"path":        job.Path,
+		"size":        job.Size,
+		"completedAt": job.CompletedAt,
+	}}
+	_, err := r.collection.UpdateOne(context.Background(), bson.M{"_id": job.ID}, update)
+	return err
+}
+
+func (r *ExportRepository) Delete(id primitive.ObjectID) error {
+	_, err := r.collection.DeleteOne(context.Background(), bson.M{"_id": id})
+	return err
+}
+
+func (r *ExportRepository) find(filter bson.M, opts *options.FindOptions) ([]models.ExportJob, error) {
+	cursor, err := r.collection.Find(context.Background(), filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	jobs := []models.ExportJob{}
+	if err := cursor.All(context.Background(), &jobs); err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
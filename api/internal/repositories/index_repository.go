@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"financial-api/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type IndexRepository struct {
+	collection *mongo.Collection
+}
+
+func NewIndexRepository(db *mongo.Database) *IndexRepository {
+	return &IndexRepository{
+		collection: db.Collection("index_values"),
+	}
+}
+
+// Upsert stores the values, replacing any existing value for the same index and date.
+func (r *IndexRepository) Upsert(values []models.IndexValue) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	writes := make([]mongo.WriteModel, len(values))
+	for i := range values {
+		values[i].UpdatedAt = time.Now()
+		writes[i] = mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"index": values[i].Index, "date": values[i].Date}).
+			SetReplacement(values[i]).
+			SetUpsert(true)
+	}
+
+	_, err := r.collection.BulkWrite(context.Background(), writes, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+// FindRange returns the values of an index with dates in [from, to], sorted by date.
+func (r *IndexRepository) FindRange(index, from, to string) ([]models.IndexValue, error) {
+	filter := bson.M{"index": index}
+	dateFilter := bson.M{}
+	if from != "" {
+		dateFilter["$gte"] = from
+	}
+	if to != "" {
+		dateFilter["$lte"] = to
+	}
+	if len(dateFilter) > 0 {
+		filter["date"] = dateFilter
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: 1}})
+	cursor, err := r.collection.Find(context.Background(), filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	values := []models.IndexValue{}
+	if err := cursor.All(context.Background(), &values); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
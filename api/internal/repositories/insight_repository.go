@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"financial-api/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type InsightRepository struct {
+	collection *mongo.Collection
+}
+
+func NewInsightRepository(db *mongo.Database) *InsightRepository {
+	return &InsightRepository{
+		collection: db.Collection("insights"),
+	}
+}
+
+// Upsert stores the insight under its key. An insight found again keeps its
+// creation time and dismissed state.
+func (r *InsightRepository) Upsert(insight *models.Insight, workspaceID string) error {
+	insight.WorkspaceID = &workspaceID
+
+	update := bson.M{
+		"$set": bson.M{
+			"kind":          insight.Kind,
+			"message":       insight.Message,
+			"date":          insight.Date,
+			"transactionId": insight.TransactionID,
+			"category":      insight.Category,
+			"merchant":      insight.Merchant,
+			"month":         insight.Month,
+			"amount":        insight.Amount,
+			"baseline":      insight.Baseline,
+			"score":         insight.Score,
+		},
+		"$setOnInsert": bson.M{
+			"dismissed": false,
+			"createdAt": time.Now(),
+		},
+	}
+
+	filter := bson.M{"workspaceId": workspaceID, "key": insight.Key}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	return r.collection.FindOneAndUpdate(context.Background(), filter, update, opts).Decode(insight)
+}
+
+// FindByWorkspace returns the most recent insights first. Dismissed insights are
+// left out unless asked for.
+func (r *InsightRepository) FindByWorkspace(workspaceID string, includeDismissed bool, limit int) ([]models.Insight, error) {
+	filter := bson.M{"workspaceId": workspaceID}
+	if !includeDismissed {
+		filter["dismissed"] = false
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "date", Value: -1}, {Key: "createdAt", Value: -1}}).
+		SetLimit(int64(limit))
+	cursor, err := r.collection.Find(context.Background(), filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	insights := []models.Insight{}
+	if err := cursor.All(context.Background(), &insights); err != nil {
+		return nil, err
+	}
+
+	return insights, nil
+}
+
+func (r *InsightRepository) Dismiss(id primitive.ObjectID, workspaceID string) (bool, error) {
+	result, err := r.collection.UpdateOne(context.Background(), bson.M{"_id": id, "workspaceId": workspaceID}, bson.M{"$set": bson.M{"dismissed": true}})
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
@@ -22,10 +22,10 @@ func NewInvestmentRepository(db *mongo.Database) *InvestmentRepository {
 	}
 }
 
-func (r *InvestmentRepository) Create(investment *models.Investment, userID string) error {
+func (r *InvestmentRepository) Create(investment *models.Investment, workspaceID string) error {
 	investment.CreatedAt = time.Now()
 	investment.UpdatedAt = time.Now()
-	investment.UserID = &userID
+	investment.WorkspaceID = &workspaceID
 
 	// Calculate monthly return
 	investment.MonthlyReturn = (investment.Amount * (investment.Rate / 100)) / 12
@@ -39,8 +39,8 @@ func (r *InvestmentRepository) Create(investment *models.Investment, userID stri
 	return nil
 }
 
-func (r *InvestmentRepository) FindPaginated(page, limit int, search, userID string) ([]models.Investment, int64, error) {
-	filter := bson.M{"userId": userID}
+func (r *InvestmentRepository) FindPaginated(page, limit int, search, workspaceID string) ([]models.Investment, int64, error) {
+	filter := bson.M{"workspaceId": workspaceID}
 
 	if search != "" {
 		filter["name"] = bson.M{"$regex": search, "$options": "i"}
@@ -76,20 +76,62 @@ func (r *InvestmentRepository) FindPaginated(page, limit int, search, userID str
 	return investments, total, nil
 }
 
-func (r *InvestmentRepository) GetTotals(userID string) (float64, float64, float64, error) {
+func (r *InvestmentRepository) FindAll(workspaceID string) ([]models.Investment, error) {
+	cursor, err := r.collection.Find(context.Background(), bson.M{"workspaceId": workspaceID})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	investments := []models.Investment{}
+	if err := cursor.All(context.Background(), &investments); err != nil {
+		return nil, err
+	}
+
+	return investments, nil
+}
+
+func (r *InvestmentRepository) FindByID(id primitive.ObjectID, workspaceID string) (*models.Investment, error) {
+	var investment models.Investment
+	err := r.collection.FindOne(context.Background(), bson.M{"_id": id, "workspaceId": workspaceID}).Decode(&investment)
+	if err != nil {
+		return nil, err
+	}
+	return &investment, nil
+}
+
+func (r *InvestmentRepository) GetTotals(workspaceID string) (float64, float64, float64, error) {
 	pipeline := []bson.M{
 		{
-			"$match": bson.M{"userId": userID},
+			"$match": bson.M{"workspaceId": workspaceID},
 		},
-		{
-			"$group": bson.M{
-				"_id":               nil,
-				"totalInvestments":  bson.M{"$sum": "$amount"},
-				"totalMonthlyReturn": bson.M{"$sum": "$monthlyReturn"},
-				"averageRate":       bson.M{"$avg": "$rate"},
+	}
+	pipeline = append(pipeline, positionStages()...)
+	pipeline = append(pipeline, bson.M{
+		"$addFields": bson.M{
+			"fixedPosition": bson.M{
+				"$cond": []interface{}{bson.M{"$eq": []interface{}{bson.M{"$ifNull": []interface{}{"$indexer", nil}}, nil}}, "$position", 0},
 			},
 		},
-	}
+	}, bson.M{
+		"$group": bson.M{
+			"_id":              nil,
+			"totalInvestments": bson.M{"$sum": "$position"},
+			"totalMonthlyReturn": bson.M{
+				"$sum": bson.M{
+					"$divide": []interface{}{
+						bson.M{"$multiply": []interface{}{"$position", bson.M{"$divide": []interface{}{"$rate", 100}}}},
+						12,
+					},
+				},
+			},
+			// Rates weighted by position size. Indexed investments store their
+			// rate as 0 or as a percentage of the index, not an annual rate, so
+			// they are left out of both the weights and the total they divide by
+			"fixedInvestments": bson.M{"$sum": "$fixedPosition"},
+			"weightedRate":     bson.M{"$sum": bson.M{"$multiply": []interface{}{"$fixedPosition", "$rate"}}},
+		},
+	})
 
 	cursor, err := r.collection.Aggregate(context.Background(), pipeline)
 	if err != nil {
@@ -100,7 +142,8 @@ func (r *InvestmentRepository) GetTotals(userID string) (float64, float64, float
 	var result struct {
 		TotalInvestments    float64 `bson:"totalInvestments"`
 		TotalMonthlyReturn  float64 `bson:"totalMonthlyReturn"`
-		AverageRate         float64 `bson:"averageRate"`
+		FixedInvestments    float64 `bson:"fixedInvestments"`
+		WeightedRate        float64 `bson:"weightedRate"`
 	}
 
 	if cursor.Next(context.Background()) {
@@ -109,5 +152,10 @@ func (r *InvestmentRepository) GetTotals(userID string) (float64, float64, float
 		}
 	}
 
-	return result.TotalInvestments, result.TotalMonthlyReturn, result.AverageRate, nil
+	averageRate := float64(0)
+	if result.FixedInvestments > 0 {
+		averageRate = result.WeightedRate / result.FixedInvestments
+	}
+
+	return result.TotalInvestments, result.TotalMonthlyReturn, averageRate, nil
 }
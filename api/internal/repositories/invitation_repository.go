@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"financial-api/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type InvitationRepository struct {
+	collection *mongo.Collection
+}
+
+func NewInvitationRepository(db *mongo.Database) *InvitationRepository {
+	return &InvitationRepository{
+		collection: db.Collection("workspace_invitations"),
+	}
+}
+
+// Replace stores the invitation, dropping an earlier one to the same email
+// for the workspace.
+func (r *InvitationRepository) Replace(invitation *models.WorkspaceInvitation) error {
+	filter := bson.M{"workspaceId": invitation.WorkspaceID, "email": invitation.Email}
+	if _, err := r.collection.DeleteMany(context.Background(), filter); err != nil {
+		return err
+	}
+
+	invitation.CreatedAt = time.Now()
+	result, err := r.collection.InsertOne(context.Background(), invitation)
+	if err != nil {
+		return err
+	}
+
+	invitation.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// FindByWorkspace returns the unexpired invitations of the workspace, newest
+// first.
+func (r *InvitationRepository) FindByWorkspace(workspaceID string) ([]models.WorkspaceInvitation, error) {
+	filter := bson.M{"workspaceId": workspaceID, "expiresAt": bson.M{"$gt": time.Now()}}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cursor, err := r.collection.Find(context.Background(), filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	invitations := []models.WorkspaceInvitation{}
+	if err := cursor.All(context.Background(), &invitations); err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
+
+// FindByHash returns the unexpired invitation with the token hash.
+func (r *InvitationRepository) FindByHash(tokenHash string) (*models.WorkspaceInvitation, error) {
+	filter := bson.M{"tokenHash": tokenHash, "expiresAt": bson.M{"$gt": time.Now()}}
+
+	var invitation models.WorkspaceInvitation
+	if err := r.collection.FindOne(context.Background(), filter).Decode(&invitation); err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// Delete removes an invitation of the workspace. It returns
+// mongo.ErrNoDocuments when the workspace has no such invitation.
+func (r *InvitationRepository) Delete(id, workspaceID string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return mongo.ErrNoDocuments
+	}
+
+	result, err := r.collection.DeleteOne(context.Background(), bson.M{"_id": objectID, "workspaceId": workspaceID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
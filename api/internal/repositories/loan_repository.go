@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"financial-api/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type LoanRepository struct {
+	collection *mongo.Collection
+}
+
+func NewLoanRepository(db *mongo.Database) *LoanRepository {
+	return &LoanRepository{
+		collection: db.Collection("loans"),
+	}
+}
+
+func (r *LoanRepository) Create(loan *models.Loan, workspaceID string) error {
+	loan.CreatedAt = time.Now()
+	loan.UpdatedAt = time.Now()
+	loan.WorkspaceID = &workspaceID
+
+	result, err := r.collection.InsertOne(context.Background(), loan)
+	if err != nil {
+		return err
+	}
+
+	loan.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *LoanRepository) FindByWorkspace(workspaceID string) ([]models.Loan, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "startDate", Value: -1}})
+	cursor, err := r.collection.Find(context.Background(), bson.M{"workspaceId": workspaceID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	loans := []models.Loan{}
+	if err := cursor.All(context.Background(), &loans); err != nil {
+		return nil, err
+	}
+
+	return loans, nil
+}
+
+func (r *LoanRepository) FindByID(id primitive.ObjectID, workspaceID string) (*models.Loan, error) {
+	var loan models.Loan
+	err := r.collection.FindOne(context.Background(), bson.M{"_id": id, "workspaceId": workspaceID}).Decode(&loan)
+	if err != nil {
+		return nil, err
+	}
+	return &loan, nil
+}
+
+// AddExtraPayment appends an extra payment and returns the updated loan.
+func (r *LoanRepository) AddExtraPayment(id primitive.ObjectID, extra models.ExtraPayment, workspaceID string) (*models.Loan, error) {
+	update := bson.M{
+		"$push": bson.M{"extraPayments": extra},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	}
+
+	var loan models.Loan
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err := r.collection.FindOneAndUpdate(context.Background(), bson.M{"_id": id, "workspaceId": workspaceID}, update, opts).Decode(&loan)
+	if err != nil {
+		return nil, err
+	}
+	return &loan, nil
+}
+
+// MarkPosted records installments as posted to the transactions.
+func (r *LoanRepository) MarkPosted(id primitive.ObjectID, numbers []int, workspaceID string) error {
+	update := bson.M{
+		"$addToSet": bson.M{"postedInstallments": bson.M{"$each": numbers}},
+		"$set":      bson.M{"updatedAt": time.Now()},
+	}
+
+	_, err := r.collection.UpdateOne(context.Background(), bson.M{"_id": id, "workspaceId": workspaceID}, update)
+	return err
+}
+
+func (r *LoanRepository) Delete(id primitive.ObjectID, workspaceID string) (bool, error) {
+	result, err := r.collection.DeleteOne(context.Background(), bson.M{"_id": id, "workspaceId": workspaceID})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
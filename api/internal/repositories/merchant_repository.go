@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"financial-api/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MerchantRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMerchantRepository(db *mongo.Database) *MerchantRepository {
+	return &MerchantRepository{
+		collection: db.Collection("merchants"),
+	}
+}
+
+// FindOrCreate returns the merchant owning the key, directly or as an alias,
+// creating it under the given name when there is none.
+func (r *MerchantRepository) FindOrCreate(key, name, workspaceID string) (*models.Merchant, error) {
+	var merchant models.Merchant
+	filter := bson.M{"workspaceId": workspaceID, "$or": []bson.M{{"key": key}, {"aliases": key}}}
+	err := r.collection.FindOne(context.Background(), filter).Decode(&merchant)
+	if err == nil {
+		return &merchant, nil
+	}
+	if err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+
+	update := bson.M{"$setOnInsert": bson.M{
+		"name":        name,
+		"key":         key,
+		"aliases":     []string{},
+		"workspaceId": workspaceID,
+		"createdAt":   time.Now(),
+		"updatedAt":   time.Now(),
+	}}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	err = r.collection.FindOneAndUpdate(context.Background(), bson.M{"workspaceId": workspaceID, "key": key}, update, opts).Decode(&merchant)
+	if err != nil {
+		return nil, err
+	}
+	return &merchant, nil
+}
+
+func (r *MerchantRepository) FindByWorkspace(workspaceID string) ([]models.Merchant, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := r.collection.Find(context.Background(), bson.M{"workspaceId": workspaceID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	merchants := []models.Merchant{}
+	if err := cursor.All(context.Background(), &merchants); err != nil {
+		return nil, err
+	}
+
+	return merchants, nil
+}
+
+// Update renames the merchant and replaces its aliases, returning it updated.
+func (r *MerchantRepository) Update(merchant *models.Merchant, workspaceID string) error {
+	update := bson.M{"$set": bson.M{
+		"name":      merchant.Name,
+		"aliases":   merchant.Aliases,
+		"updatedAt": time.Now(),
+	}}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	return r.collection.FindOneAndUpdate(context.Background(), bson.M{"_id": merchant.ID, "workspaceId": workspaceID}, update, opts).Decode(merchant)
+}
+
+// DeleteByKeys removes the other merchants of the workspace keyed by any of the
+// keys, once those keys became aliases of the given merchant.
+func (r *MerchantRepository) DeleteByKeys(keys []string, except primitive.ObjectID, workspaceID string) error {
+	_, err := r.collection.DeleteMany(context.Background(), bson.M{
+		"workspaceId": workspaceID,
+		"key":         bson.M{"$in": keys},
+		"_id":         bson.M{"$ne": except},
+	})
+	return err
+}
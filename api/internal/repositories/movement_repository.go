@@ -0,0 +1,162 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"financial-api/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MovementRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMovementRepository(db *mongo.Database) *MovementRepository {
+	return &MovementRepository{
+		collection: db.Collection("investment_movements"),
+	}
+}
+
+func (r *MovementRepository) Create(movement *models.InvestmentMovement, workspaceID string) error {
+	movement.CreatedAt = time.Now()
+	movement.WorkspaceID = &workspaceID
+
+	result, err := r.collection.InsertOne(context.Background(), movement)
+	if err != nil {
+		return err
+	}
+
+	movement.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *MovementRepository) FindByInvestment(investmentID primitive.ObjectID, workspaceID string) ([]models.InvestmentMovement, error) {
+	filter := bson.M{"investmentId": investmentID, "workspaceId": workspaceID}
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: 1}, {Key: "createdAt", Value: 1}})
+
+	cursor, err := r.collection.Find(context.Background(), filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	movements := []models.InvestmentMovement{}
+	if err := cursor.All(context.Background(), &movements); err != nil {
+		return nil, err
+	}
+
+	return movements, nil
+}
+
+// FindByInvestments returns the movements of the given investments grouped by investment.
+func (r *MovementRepository) FindByInvestments(investmentIDs []primitive.ObjectID, workspaceID string) (map[primitive.ObjectID][]models.InvestmentMovement, error) {
+	filter := bson.M{"investmentId": bson.M{"$in": investmentIDs}, "workspaceId": workspaceID}
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: 1}, {Key: "createdAt", Value: 1}})
+
+	cursor, err := r.collection.Find(context.Background(), filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	var movements []models.InvestmentMovement
+	if err := cursor.All(context.Background(), &movements); err != nil {
+		return nil, err
+	}
+
+	grouped := make(map[primitive.ObjectID][]models.InvestmentMovement)
+	for _, movement := range movements {
+		grouped[movement.InvestmentID] = append(grouped[movement.InvestmentID], movement)
+	}
+
+	return grouped, nil
+}
+
+// GetPositions returns the ledger balance of each given investment that has movements.
+func (r *MovementRepository) GetPositions(investmentIDs []primitive.ObjectID, workspaceID string) (map[primitive.ObjectID]float64, error) {
+	pipeline := []bson.M{
+		{
+			"$match": bson.M{"workspaceId": workspaceID, "investmentId": bson.M{"$in": investmentIDs}},
+		},
+		{
+			"$group": bson.M{
+				"_id":      "$investmentId",
+				"position": bson.M{"$sum": signedMovementAmount("$type", "$amount")},
+			},
+		},
+	}
+
+	cursor, err := r.collection.Aggregate(context.Background(), pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	var results []struct {
+		ID       primitive.ObjectID `bson:"_id"`
+		Position float64            `bson:"position"`
+	}
+	if err := cursor.All(context.Background(), &results); err != nil {
+		return nil, err
+	}
+
+	positions := make(map[primitive.ObjectID]float64, len(results))
+	for _, result := range results {
+		positions[result.ID] = result.Position
+	}
+
+	return positions, nil
+}
+
+// signedMovementAmount builds an expression that negates outflow movements.
+func signedMovementAmount(typeField, amountField string) bson.M {
+	return bson.M{
+		"$cond": bson.M{
+			"if":   bson.M{"$in": []interface{}{typeField, models.OutflowMovementTypes}},
+			"then": bson.M{"$multiply": []interface{}{amountField, -1}},
+			"else": amountField,
+		},
+	}
+}
+
+// positionStages adds a "position" field to investment documents, derived from
+// their ledger. Investments recorded before the ledger existed fall back to amount.
+func positionStages() []bson.M {
+	return []bson.M{
+		{
+			"$lookup": bson.M{
+				"from":         "investment_movements",
+				"localField":   "_id",
+				"foreignField": "investmentId",
+				"as":           "movements",
+			},
+		},
+		{
+			"$addFields": bson.M{
+				"position": bson.M{
+					"$cond": bson.M{
+						"if": bson.M{"$gt": []interface{}{bson.M{"$size": "$movements"}, 0}},
+						"then": bson.M{
+							"$sum": bson.M{
+								"$map": bson.M{
+									"input": "$movements",
+									"as":    "m",
+									"in":    signedMovementAmount("$$m.type", "$$m.amount"),
+								},
+							},
+						},
+						"else": "$amount",
+					},
+				},
+			},
+		},
+		{
+			"$project": bson.M{"movements": 0},
+		},
+	}
+}
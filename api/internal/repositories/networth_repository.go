@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"financial-api/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type NetWorthRepository struct {
+	itemCollection     *mongo.Collection
+	snapshotCollection *mongo.Collection
+}
+
+func NewNetWorthRepository(db *mongo.Database) *NetWorthRepository {
+	return &NetWorthRepository{
+		itemCollection:     db.Collection("net_worth_items"),
+		snapshotCollection: db.Collection("net_worth_snapshots"),
+	}
+}
+
+func (r *NetWorthRepository) CreateItem(item *models.NetWorthItem, workspaceID string) error {
+	item.CreatedAt = time.Now()
+	item.UpdatedAt = time.Now()
+	item.WorkspaceID = &workspaceID
+
+	result, err := r.itemCollection.InsertOne(context.Background(), item)
+	if err != nil {
+		return err
+	}
+
+	item.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *NetWorthRepository) FindItems(workspaceID string) ([]models.NetWorthItem, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "kind", Value: 1}, {Key: "name", Value: 1}})
+	cursor, err := r.itemCollection.Find(context.Background(), bson.M{"workspaceId": workspaceID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	items := []models.NetWorthItem{}
+	if err := cursor.All(context.Background(), &items); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// UpdateItem rewrites the editable fields of an item and returns it updated.
+func (r *NetWorthRepository) UpdateItem(item *models.NetWorthItem, workspaceID string) error {
+	update := bson.M{"$set": bson.M{
+		"kind":      item.Kind,
+		"name":      item.Name,
+		"category":  item.Category,
+		"value":     item.Value,
+		"updatedAt": time.Now(),
+	}}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	return r.itemCollection.FindOneAndUpdate(context.Background(), bson.M{"_id": item.ID, "workspaceId": workspaceID}, update, opts).Decode(item)
+}
+
+func (r *NetWorthRepository) DeleteItem(id primitive.ObjectID, workspaceID string) (bool, error) {
+	result, err := r.itemCollection.DeleteOne(context.Background(), bson.M{"_id": id, "workspaceId": workspaceID})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
+
+// UpsertSnapshot stores the snapshot of the workspace for its date, replacing
+// the one taken earlier that day.
+func (r *NetWorthRepository) UpsertSnapshot(snapshot *models.NetWorthSnapshot, workspaceID string) error {
+	snapshot.CreatedAt = time.Now()
+	snapshot.WorkspaceID = &workspaceID
+
+	filter := bson.M{"workspaceId": workspaceID, "date": snapshot.Date}
+	opts := options.FindOneAndReplace().SetUpsert(true).SetReturnDocument(options.After)
+	return r.snapshotCollection.FindOneAndReplace(context.Background(), filter, snapshot, opts).Decode(snapshot)
+}
+
+// FindSnapshots returns the snapshots taken in [from, to], oldest first. Empty
+// bounds are not filtered.
+func (r *NetWorthRepository) FindSnapshots(workspaceID, from, to string) ([]models.NetWorthSnapshot, error) {
+	filter := bson.M{"workspaceId": workspaceID}
+	dateFilter := bson.M{}
+	if from != "" {
+		dateFilter["$gte"] = from
+	}
+	if to != "" {
+		dateFilter["$lte"] = to
+	}
+	if len(dateFilter) > 0 {
+		filter["date"] = dateFilter
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: 1}})
+	cursor, err := r.snapshotCollection.Find(context.Background(), filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	snapshots := []models.NetWorthSnapshot{}
+	if err := cursor.All(context.Background(), &snapshots); err != nil {
+		return nil, err
+	}
+
+	return snapshots, nil
+}
@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"financial-api/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type OIDCStateRepository struct {
+	collection *mongo.Collection
+}
+
+func NewOIDCStateRepository(db *mongo.Database) *OIDCStateRepository {
+	return &OIDCStateRepository{
+		collection: db.Collection("oidc_states"),
+	}
+}
+
+func (r *OIDCStateRepository) Create(state *models.OIDCState) error {
+	state.CreatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(context.Background(), state)
+	if err != nil {
+		return err
+	}
+
+	state.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// Consume deletes and returns the unexpired state, so each can be used once.
+// It returns mongo.ErrNoDocuments when there is none.
+func (r *OIDCStateRepository) Consume(stateHash string) (*models.OIDCState, error) {
+	filter := bson.M{"stateHash": stateHash, "expiresAt": bson.M{"$gt": time.Now()}}
+
+	var state models.OIDCState
+	if err := r.collection.FindOneAndDelete(context.Background(), filter).Decode(&state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
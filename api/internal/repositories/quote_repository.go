@@ -0,0 +1,108 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"financial-api/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type QuoteRepository struct {
+	collection *mongo.Collection
+}
+
+func NewQuoteRepository(db *mongo.Database) *QuoteRepository {
+	return &QuoteRepository{
+		collection: db.Collection("quotes"),
+	}
+}
+
+// Upsert stores the quotes, replacing any existing quote for the same ticker and date.
+func (r *QuoteRepository) Upsert(quotes []models.Quote) error {
+	if len(quotes) == 0 {
+		return nil
+	}
+
+	writes := make([]mongo.WriteModel, len(quotes))
+	for i := range quotes {
+		quotes[i].UpdatedAt = time.Now()
+		writes[i] = mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"ticker": quotes[i].Ticker, "date": quotes[i].Date}).
+			SetReplacement(quotes[i]).
+			SetUpsert(true)
+	}
+
+	_, err := r.collection.BulkWrite(context.Background(), writes, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+// FindRange returns the quotes of a ticker with dates in [from, to], sorted by date.
+func (r *QuoteRepository) FindRange(ticker, from, to string) ([]models.Quote, error) {
+	filter := bson.M{"ticker": ticker}
+	dateFilter := bson.M{}
+	if from != "" {
+		dateFilter["$gte"] = from
+	}
+	if to != "" {
+		dateFilter["$lte"] = to
+	}
+	if len(dateFilter) > 0 {
+		filter["date"] = dateFilter
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: 1}})
+	cursor, err := r.collection.Find(context.Background(), filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	quotes := []models.Quote{}
+	if err := cursor.All(context.Background(), &quotes); err != nil {
+		return nil, err
+	}
+
+	return quotes, nil
+}
+
+// FindLatest returns the most recent quote of each ticker.
+func (r *QuoteRepository) FindLatest(tickers []string) (map[string]models.Quote, error) {
+	pipeline := []bson.M{
+		{
+			"$match": bson.M{"ticker": bson.M{"$in": tickers}},
+		},
+		{
+			"$sort": bson.M{"date": -1},
+		},
+		{
+			"$group": bson.M{
+				"_id":   "$ticker",
+				"quote": bson.M{"$first": "$$ROOT"},
+			},
+		},
+	}
+
+	cursor, err := r.collection.Aggregate(context.Background(), pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	var results []struct {
+		Quote models.Quote `bson:"quote"`
+	}
+	if err := cursor.All(context.Background(), &results); err != nil {
+		return nil, err
+	}
+
+	latest := make(map[string]models.Quote, len(results))
+	for _, result := range results {
+		latest[result.Quote.Ticker] = result.Quote
+	}
+
+	return latest, nil
+}
@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"financial-api/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type RecurringRepository struct {
+	collection *mongo.Collection
+}
+
+func NewRecurringRepository(db *mongo.Database) *RecurringRepository {
+	return &RecurringRepository{
+		collection: db.Collection("recurring_items"),
+	}
+}
+
+func (r *RecurringRepository) Create(item *models.RecurringItem, workspaceID string) error {
+	item.CreatedAt = time.Now()
+	item.UpdatedAt = time.Now()
+	item.WorkspaceID = &workspaceID
+
+	result, err := r.collection.InsertOne(context.Background(), item)
+	if err != nil {
+		return err
+	}
+
+	item.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *RecurringRepository) FindByWorkspace(workspaceID string) ([]models.RecurringItem, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "startDate", Value: 1}})
+	cursor, err := r.collection.Find(context.Background(), bson.M{"workspaceId": workspaceID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	items := []models.RecurringItem{}
+	if err := cursor.All(context.Background(), &items); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// Update rewrites the editable fields of an item and returns it updated.
+func (r *RecurringRepository) Update(item *models.RecurringItem, workspaceID string) error {
+	update := bson.M{"$set": bson.M{
+		"type":        item.Type,
+		"description": item.Description,
+		"amount":      item.Amount,
+		"frequency":   item.Frequency,
+		"startDate":   item.StartDate,
+		"endDate":     item.EndDate,
+		"categoryId":  item.CategoryID,
+		"updatedAt":   time.Now(),
+	}}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	return r.collection.FindOneAndUpdate(context.Background(), bson.M{"_id": item.ID, "workspaceId": workspaceID}, update, opts).Decode(item)
+}
+
+func (r *RecurringRepository) Delete(id primitive.ObjectID, workspaceID string) (bool, error) {
+	result, err := r.collection.DeleteOne(context.Background(), bson.M{"_id": id, "workspaceId": workspaceID})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"financial-api/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type SecurityRepository struct {
+	throttleCollection *mongo.Collection
+	auditCollection    *mongo.Collection
+}
+
+func NewSecurityRepository(db *mongo.Database) *SecurityRepository {
+	return &SecurityRepository{
+		throttleCollection: db.Collection("login_throttles"),
+		auditCollection:    db.Collection("security_audit"),
+	}
+}
+
+// FindThrottle returns the live throttle of the key, or nil.
+func (r *SecurityRepository) FindThrottle(key string) (*models.LoginThrottle, error) {
+	var throttle models.LoginThrottle
+	filter := bson.M{"key": key, "expiresAt": bson.M{"$gt": time.Now()}}
+	err := r.throttleCollection.FindOne(context.Background(), filter).Decode(&throttle)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &throttle, nil
+}
+
+// AddFailure counts a failure for the key and keeps it for window from now.
+// An expired throttle the TTL monitor has not removed yet starts over.
+func (r *SecurityRepository) AddFailure(key string, window time.Duration) (*models.LoginThrottle, error) {
+	now := time.Now()
+	if _, err := r.throttleCollection.DeleteOne(context.Background(), bson.M{"key": key, "expiresAt": bson.M{"$lte": now}}); err != nil {
+		return nil, err
+	}
+
+	update := bson.M{
+		"$inc": bson.M{"failures": 1},
+		"$set": bson.M{"lastFailureAt": now, "expiresAt": now.Add(window)},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var throttle models.LoginThrottle
+	if err := r.throttleCollection.FindOneAndUpdate(context.Background(), bson.M{"key": key}, update, opts).Decode(&throttle); err != nil {
+		return nil, err
+	}
+	return &throttle, nil
+}
+
+// Lock locks the key until the given time, keeping the throttle until then.
+func (r *SecurityRepository) Lock(key string, until time.Time) error {
+	update := bson.M{"$set": bson.M{"lockedUntil": until, "expiresAt": until}}
+	_, err := r.throttleCollection.UpdateOne(context.Background(), bson.M{"key": key}, update)
+	return err
+}
+
+func (r *SecurityRepository) ClearThrottle(key string) error {
+	_, err := r.throttleCollection.DeleteOne(context.Background(), bson.M{"key": key})
+	return err
+}
+
+func (r *SecurityRepository) AddEvent(event *models.SecurityEvent) error {
+	event.CreatedAt = time.Now()
+	_, err := r.auditCollection.InsertOne(context.Background(), event)
+	return err
+}
+
+// FindEvents returns the latest events of the user.
+func (r *SecurityRepository) FindEvents(userID string, limit int) ([]models.SecurityEvent, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}}).SetLimit(int64(limit))
+	cursor, err := r.auditCollection.Find(context.Background(), bson.M{"userId": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	events := []models.SecurityEvent{}
+	if err := cursor.All(context.Background(), &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
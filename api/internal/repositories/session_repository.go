@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"financial-api/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type SessionRepository struct {
+	collection      *mongo.Collection
+	tokenCollection *mongo.Collection
+}
+
+func NewSessionRepository(db *mongo.Database) *SessionRepository {
+	return &SessionRepository{
+		collection:      db.Collection("sessions"),
+		tokenCollection: db.Collection("refresh_tokens"),
+	}
+}
+
+func (r *SessionRepository) Create(session *models.Session) error {
+	session.CreatedAt = time.Now()
+	session.LastUsedAt = session.CreatedAt
+
+	result, err := r.collection.InsertOne(context.Background(), session)
+	if err != nil {
+		return err
+	}
+
+	session.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *SessionRepository) FindByID(id string) (*models.Session, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, mongo.ErrNoDocuments
+	}
+
+	var session models.Session
+	if err := r.collection.FindOne(context.Background(), bson.M{"_id": objectID}).Decode(&session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// Extend pushes the session expiry after a refresh.
+func (r *SessionRepository) Extend(id primitive.ObjectID, expiresAt time.Time) error {
+	update := bson.M{"$set": bson.M{"expiresAt": expiresAt, "lastUsedAt": time.Now()}}
+	_, err := r.collection.UpdateOne(context.Background(), bson.M{"_id": id}, update)
+	return err
+}
+
+// Revoke ends one session of the user. Revoking it again is a no-op.
+func (r *SessionRepository) Revoke(id primitive.ObjectID, userID string) error {
+	filter := bson.M{"_id": id, "userId": userID, "revokedAt": bson.M{"$exists": false}}
+	_, err := r.collection.UpdateOne(context.Background(), filter, bson.M{"$set": bson.M{"revokedAt": time.Now()}})
+	return err
+}
+
+// RevokeAll ends every session of the user.
+func (r *SessionRepository) RevokeAll(userID string) error {
+	filter := bson.M{"userId": userID, "revokedAt": bson.M{"$exists": false}}
+	_, err := r.collection.UpdateMany(context.Background(), filter, bson.M{"$set": bson.M{"revokedAt": time.Now()}})
+	return err
+}
+
+// RevokeOthers ends every session of the user except the given one.
+func (r *SessionRepository) RevokeOthers(userID string, keep primitive.ObjectID) error {
+	filter := bson.M{"userId": userID, "_id": bson.M{"$ne": keep}, "revokedAt": bson.M{"$exists": false}}
+	_, err := r.collection.UpdateMany(context.Background(), filter, bson.M{"$set": bson.M{"revokedAt": time.Now()}})
+	return err
+}
+
+func (r *SessionRepository) CreateRefreshToken(token *models.RefreshToken) error {
+	token.CreatedAt = time.Now()
+
+	result, err := r.tokenCollection.InsertOne(context.Background(), token)
+	if err != nil {
+		return err
+	}
+
+	token.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *SessionRepository) FindRefreshToken(tokenHash string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	if err := r.tokenCollection.FindOne(context.Background(), bson.M{"tokenHash": tokenHash}).Decode(&token); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// MarkRefreshTokenUsed spends the token, reporting false when it had already
+// been spent, including by a concurrent request.
+func (r *SessionRepository) MarkRefreshTokenUsed(id primitive.ObjectID) (bool, error) {
+	filter := bson.M{"_id": id, "usedAt": bson.M{"$exists": false}}
+	result, err := r.tokenCollection.UpdateOne(context.Background(), filter, bson.M{"$set": bson.M{"usedAt": time.Now()}})
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"financial-api/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type TradeRepository struct {
+	collection *mongo.Collection
+}
+
+func NewTradeRepository(db *mongo.Database) *TradeRepository {
+	return &TradeRepository{
+		collection: db.Collection("trades"),
+	}
+}
+
+func (r *TradeRepository) Create(trade *models.Trade, workspaceID string) error {
+	trade.CreatedAt = time.Now()
+	trade.WorkspaceID = &workspaceID
+
+	result, err := r.collection.InsertOne(context.Background(), trade)
+	if err != nil {
+		return err
+	}
+
+	trade.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// FindByWorkspace returns the trades of a workspace in chronological order,
+// optionally restricted to one ticker.
+func (r *TradeRepository) FindByWorkspace(workspaceID, ticker string) ([]models.Trade, error) {
+	filter := bson.M{"workspaceId": workspaceID}
+	if ticker != "" {
+		filter["ticker"] = ticker
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: 1}, {Key: "createdAt", Value: 1}})
+	cursor, err := r.collection.Find(context.Background(), filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	trades := []models.Trade{}
+	if err := cursor.All(context.Background(), &trades); err != nil {
+		return nil, err
+	}
+
+	return trades, nil
+}
@@ -22,10 +22,10 @@ func NewTransactionRepository(db *mongo.Database) *TransactionRepository {
 	}
 }
 
-func (r *TransactionRepository) Create(transaction *models.Transaction, userID string) error {
+func (r *TransactionRepository) Create(transaction *models.Transaction, workspaceID string) error {
 	transaction.CreatedAt = time.Now()
 	transaction.UpdatedAt = time.Now()
-	transaction.UserID = &userID
+	transaction.WorkspaceID = &workspaceID
 
 	result, err := r.collection.InsertOne(context.Background(), transaction)
 	if err != nil {
@@ -36,8 +36,8 @@ func (r *TransactionRepository) Create(transaction *models.Transaction, userID s
 	return nil
 }
 
-func (r *TransactionRepository) FindPaginated(page, limit int, search, transactionType, userID string) ([]models.Transaction, int64, error) {
-	filter := bson.M{"userId": userID}
+func (r *TransactionRepository) FindPaginated(page, limit int, search, transactionType, workspaceID string) ([]models.Transaction, int64, error) {
+	filter := bson.M{"workspaceId": workspaceID}
 
 	if transactionType != "all" && transactionType != "" {
 		filter["type"] = transactionType
@@ -77,10 +77,10 @@ func (r *TransactionRepository) FindPaginated(page, limit int, search, transacti
 	return transactions, total, nil
 }
 
-func (r *TransactionRepository) GetTotals(userID string) (*models.Totals, error) {
+func (r *TransactionRepository) GetTotals(workspaceID string) (*models.Totals, error) {
 	pipeline := []bson.M{
 		{
-			"$match": bson.M{"userId": userID},
+			"$match": bson.M{"workspaceId": workspaceID},
 		},
 		{
 			"$group": bson.M{
@@ -117,3 +117,75 @@ func (r *TransactionRepository) GetTotals(userID string) (*models.Totals, error)
 	totals.Balance = totals.TotalIncome - totals.TotalExpenses
 	return totals, nil
 }
+
+// FindExpenses returns the expenses dated within [from, to], oldest first.
+func (r *TransactionRepository) FindExpenses(workspaceID, from, to string) ([]models.Transaction, error) {
+	filter := bson.M{
+		"workspaceId": workspaceID,
+		"type":        "expense",
+		"date":        bson.M{"$gte": from, "$lte": to},
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: 1}})
+	cursor, err := r.collection.Find(context.Background(), filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	transactions := []models.Transaction{}
+	if err := cursor.All(context.Background(), &transactions); err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+// SetFlags replaces the anomaly flags of a transaction.
+func (r *TransactionRepository) SetFlags(id primitive.ObjectID, flags []string) error {
+	_, err := r.collection.UpdateOne(context.Background(), bson.M{"_id": id}, bson.M{"$set": bson.M{"flags": flags}})
+	return err
+}
+
+// FindUntagged returns up to limit transactions saved before merchants were
+// tagged, which have no merchantKey at all.
+func (r *TransactionRepository) FindUntagged(limit int) ([]models.Transaction, error) {
+	filter := bson.M{"merchantKey": bson.M{"$exists": false}}
+	opts := options.Find().SetLimit(int64(limit))
+	cursor, err := r.collection.Find(context.Background(), filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	transactions := []models.Transaction{}
+	if err := cursor.All(context.Background(), &transactions); err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+// SetMerchantKey stores the merchant tagged on a transaction. An empty key is
+// stored as well, marking a description without a merchant as tagged.
+func (r *TransactionRepository) SetMerchantKey(transaction *models.Transaction) error {
+	set := bson.M{"merchantKey": transaction.MerchantKey}
+	if transaction.MerchantID != nil {
+		set["merchant"] = transaction.Merchant
+		set["merchantId"] = *transaction.MerchantID
+	}
+
+	_, err := r.collection.UpdateOne(context.Background(), bson.M{"_id": transaction.ID}, bson.M{"$set": set})
+	return err
+}
+
+// SetMerchant points the transactions of the workspace whose description
+// normalizes to any of the keys at the merchant.
+func (r *TransactionRepository) SetMerchant(keys []string, merchant *models.Merchant, workspaceID string) error {
+	merchantID := merchant.ID.Hex()
+	filter := bson.M{"workspaceId": workspaceID, "merchantKey": bson.M{"$in": keys}}
+	update := bson.M{"$set": bson.M{"merchant": merchant.Name, "merchantId": merchantID}}
+
+	_, err := r.collection.UpdateMany(context.Background(), filter, update)
+	return err
+}
@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// WorkspaceCollections hold the financial records, which belong to a
+// workspace through their workspaceId field. Categories, quotes and index
+// values are shared and are not listed.
+var WorkspaceCollections = []string{
+	"transactions",
+	"investments",
+	"investment_movements",
+	"trades",
+	"dividends",
+	"allocation_targets",
+	"net_worth_items",
+	"net_worth_snapshots",
+	"loans",
+	"recurring_items",
+	"insights",
+	"merchants",
+}
+
+// OwnedCollections hold documents that belong to one user through their
+// userId field.
+var OwnedCollections = []string{
+	"sessions",
+	"refresh_tokens",
+	"account_tokens",
+	"security_audit",
+	"api_keys",
+	"oidc_states",
+}
+
+// UserDataRepository works on everything a user or a workspace owns at once.
+type UserDataRepository struct {
+	db *mongo.Database
+}
+
+func NewUserDataRepository(db *mongo.Database) *UserDataRepository {
+	return &UserDataRepository{db: db}
+}
+
+// FindAll returns the documents the user owns in the collection, oldest
+// first.
+func (r *UserDataRepository) FindAll(collection, userID string) ([]bson.M, error) {
+	return r.find(collection, bson.M{"userId": userID})
+}
+
+// FindInWorkspaces returns the documents of the workspaces in the
+// collection, oldest first.
+func (r *UserDataRepository) FindInWorkspaces(collection string, workspaceIDs []string) ([]bson.M, error) {
+	return r.find(collection, bson.M{"workspaceId": bson.M{"$in": workspaceIDs}})
+}
+
+func (r *UserDataRepository) find(collection string, filter bson.M) ([]bson.M, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	cursor, err := r.db.Collection(collection).Find(context.Background(), filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	documents := []bson.M{}
+	if err := cursor.All(context.Background(), &documents); err != nil {
+		return nil, err
+	}
+	return documents, nil
+}
+
+// DeleteAll removes the documents the user owns, the user's memberships and
+// then the user. The user goes last so that a failed deletion can be run
+// again. Workspaces are deleted apart, with DeleteWorkspace.
+func (r *UserDataRepository) DeleteAll(userID string) error {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range OwnedCollections {
+		if _, err := r.db.Collection(name).DeleteMany(context.Background(), bson.M{"userId": userID}); err != nil {
+			return err
+		}
+	}
+	if _, err := r.db.Collection("workspace_members").DeleteMany(context.Background(), bson.M{"userId": userID}); err != nil {
+		return err
+	}
+
+	_, err = r.db.Collection("users").DeleteOne(context.Background(), bson.M{"_id": objectID})
+	return err
+}
+
+// DeleteWorkspace removes the records of the workspace, its members and
+// invitations and then the workspace, last for the same reason as in
+// DeleteAll.
+func (r *UserDataRepository) DeleteWorkspace(workspaceID string) error {
+	objectID, err := primitive.ObjectIDFromHex(workspaceID)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{"workspaceId": workspaceID}
+	for _, name := range append(WorkspaceCollections, "workspace_members", "workspace_invitations") {
+		if _, err := r.db.Collection(name).DeleteMany(context.Background(), filter); err != nil {
+			return err
+		}
+	}
+
+	_, err = r.db.Collection("workspaces").DeleteOne(context.Background(), bson.M{"_id": objectID})
+	return err
+}
@@ -55,4 +55,158 @@ func (r *UserRepository) FindByID(id string) (*models.User, error) {
 		return nil, err
 	}
 	return &user, nil
-}
\ No newline at end of file
+}
+
+func (r *UserRepository) UpdatePassword(id, password string) error {
+	return r.updateFields(id, bson.M{"password": password})
+}
+
+func (r *UserRepository) UpdateName(id, name string) error {
+	return r.updateFields(id, bson.M{"name": name})
+}
+
+// UpdateEmail changes the email, which must then be verified again.
+func (r *UserRepository) UpdateEmail(id, email string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{
+		"$set":   bson.M{"email": email, "updatedAt": time.Now()},
+		"$unset": bson.M{"emailVerifiedAt": ""},
+	}
+	result, err := r.collection.UpdateOne(context.Background(), bson.M{"_id": objectID}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r *UserRepository) MarkEmailVerified(id string) error {
+	return r.updateFields(id, bson.M{"emailVerifiedAt": time.Now()})
+}
+
+func (r *UserRepository) updateFields(id string, fields bson.M) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	fields["updatedAt"] = time.Now()
+	result, err := r.collection.UpdateOne(context.Background(), bson.M{"_id": objectID}, bson.M{"$set": fields})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r *UserRepository) SetPendingTOTPSecret(id, secret string) error {
+	return r.updateFields(id, bson.M{"twoFactor.pendingSecret": secret})
+}
+
+// EnableTwoFactor promotes the pending secret, recording the step of the code
+// that confirmed it.
+func (r *UserRepository) EnableTwoFactor(id, secret string, step int64, recoveryCodes []string) error {
+	return r.updateFields(id, bson.M{"twoFactor": models.TwoFactor{
+		Enabled:       true,
+		Secret:        secret,
+		LastStep:      step,
+		RecoveryCodes: recoveryCodes,
+	}})
+}
+
+func (r *UserRepository) DisableTwoFactor(id string) error {
+	return r.updateFields(id, bson.M{"twoFactor": models.TwoFactor{}})
+}
+
+func (r *UserRepository) SetRecoveryCodes(id string, recoveryCodes []string) error {
+	return r.updateFields(id, bson.M{"twoFactor.recoveryCodes": recoveryCodes})
+}
+
+// UseTOTPStep records the step of an accepted code, reporting false when a
+// code of that step or a later one was already used.
+func (r *UserRepository) UseTOTPStep(id string, step int64) (bool, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, err
+	}
+
+	filter := bson.M{"_id": objectID, "twoFactor.lastStep": bson.M{"$not": bson.M{"$gte": step}}}
+	result, err := r.collection.UpdateOne(context.Background(), filter, bson.M{"$set": bson.M{"twoFactor.lastStep": step}})
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// UseRecoveryCode removes the hashed recovery code, reporting false when the
+// user does not have it.
+func (r *UserRepository) UseRecoveryCode(id, codeHash string) (bool, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, err
+	}
+
+	filter := bson.M{"_id": objectID, "twoFactor.recoveryCodes": codeHash}
+	result, err := r.collection.UpdateOne(context.Background(), filter, bson.M{"$pull": bson.M{"twoFactor.recoveryCodes": codeHash}})
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+func (r *UserRepository) FindByIdentity(provider, subject string) (*models.User, error) {
+	filter := bson.M{"identities": bson.M{"$elemMatch": bson.M{"provider": provider, "subject": subject}}}
+
+	var user models.User
+	if err := r.collection.FindOne(context.Background(), filter).Decode(&user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// AddIdentity links the identity, reporting false when the user already has
+// one at the same provider.
+func (r *UserRepository) AddIdentity(id string, identity models.Identity) (bool, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, err
+	}
+
+	filter := bson.M{"_id": objectID, "identities.provider": bson.M{"$ne": identity.Provider}}
+	update := bson.M{
+		"$push": bson.M{"identities": identity},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	}
+	result, err := r.collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// RemoveIdentity unlinks the provider, reporting false when it was not linked.
+func (r *UserRepository) RemoveIdentity(id, provider string) (bool, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, err
+	}
+
+	filter := bson.M{"_id": objectID, "identities.provider": provider}
+	update := bson.M{
+		"$pull": bson.M{"identities": bson.M{"provider": provider}},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	}
+	result, err := r.collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
@@ -0,0 +1,201 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"financial-api/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// WorkspaceRepository stores workspaces and their members.
+type WorkspaceRepository struct {
+	collection *mongo.Collection
+	members    *mongo.Collection
+}
+
+func NewWorkspaceRepository(db *mongo.Database) *WorkspaceRepository {
+	return &WorkspaceRepository{
+		collection: db.Collection("workspaces"),
+		members:    db.Collection("workspace_members"),
+	}
+}
+
+// Create inserts the workspace with the user as its owner.
+func (r *WorkspaceRepository) Create(workspace *models.Workspace, ownerID string) error {
+	workspace.CreatedAt = time.Now()
+	workspace.UpdatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(context.Background(), workspace)
+	if err != nil {
+		return err
+	}
+	workspace.ID = result.InsertedID.(primitive.ObjectID)
+
+	return r.AddMember(&models.WorkspaceMember{
+		WorkspaceID: workspace.ID.Hex(),
+		UserID:      ownerID,
+		Role:        models.RoleOwner,
+	})
+}
+
+// EnsurePersonal creates the personal workspace of the user, keyed by the
+// user's ID, unless it exists already.
+func (r *WorkspaceRepository) EnsurePersonal(userID, name string) error {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	upsert := options.Update().SetUpsert(true)
+
+	_, err = r.collection.UpdateOne(context.Background(), bson.M{"_id": objectID}, bson.M{
+		"$setOnInsert": bson.M{"name": name, "personal": true, "createdAt": now, "updatedAt": now},
+	}, upsert)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.members.UpdateOne(context.Background(), bson.M{"_id": objectID}, bson.M{
+		"$setOnInsert": bson.M{"workspaceId": userID, "userId": userID, "role": models.RoleOwner, "joinedAt": now},
+	}, upsert)
+	return err
+}
+
+func (r *WorkspaceRepository) FindByID(id string) (*models.Workspace, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, mongo.ErrNoDocuments
+	}
+
+	var workspace models.Workspace
+	if err := r.collection.FindOne(context.Background(), bson.M{"_id": objectID}).Decode(&workspace); err != nil {
+		return nil, err
+	}
+	return &workspace, nil
+}
+
+// FindByIDs returns the workspaces by name, personal ones first.
+func (r *WorkspaceRepository) FindByIDs(ids []string) ([]models.Workspace, error) {
+	objectIDs := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		if objectID, err := primitive.ObjectIDFromHex(id); err == nil {
+			objectIDs = append(objectIDs, objectID)
+		}
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "personal", Value: -1}, {Key: "name", Value: 1}})
+	cursor, err := r.collection.Find(context.Background(), bson.M{"_id": bson.M{"$in": objectIDs}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	workspaces := []models.Workspace{}
+	if err := cursor.All(context.Background(), &workspaces); err != nil {
+		return nil, err
+	}
+	return workspaces, nil
+}
+
+// FindAllIDs returns the ID of every workspace, for the background jobs.
+func (r *WorkspaceRepository) FindAllIDs() ([]string, error) {
+	opts := options.Find().SetProjection(bson.M{"_id": 1})
+	cursor, err := r.collection.Find(context.Background(), bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	var results []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(context.Background(), &results); err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(results))
+	for i, result := range results {
+		ids[i] = result.ID.Hex()
+	}
+	return ids, nil
+}
+
+func (r *WorkspaceRepository) UpdateName(id, name string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{"$set": bson.M{"name": name, "updatedAt": time.Now()}}
+	_, err = r.collection.UpdateOne(context.Background(), bson.M{"_id": objectID}, update)
+	return err
+}
+
+func (r *WorkspaceRepository) AddMember(member *models.WorkspaceMember) error {
+	member.JoinedAt = time.Now()
+
+	result, err := r.members.InsertOne(context.Background(), member)
+	if err != nil {
+		return err
+	}
+
+	member.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *WorkspaceRepository) FindMember(workspaceID, userID string) (*models.WorkspaceMember, error) {
+	var member models.WorkspaceMember
+	filter := bson.M{"workspaceId": workspaceID, "userId": userID}
+	if err := r.members.FindOne(context.Background(), filter).Decode(&member); err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// FindMembers returns the members of the workspace in the order they joined.
+func (r *WorkspaceRepository) FindMembers(workspaceID string) ([]models.WorkspaceMember, error) {
+	return r.findMembers(bson.M{"workspaceId": workspaceID})
+}
+
+// FindMemberships returns the memberships of the user in every workspace.
+func (r *WorkspaceRepository) FindMemberships(userID string) ([]models.WorkspaceMember, error) {
+	return r.findMembers(bson.M{"userId": userID})
+}
+
+func (r *WorkspaceRepository) findMembers(filter bson.M) ([]models.WorkspaceMember, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "joinedAt", Value: 1}})
+	cursor, err := r.members.Find(context.Background(), filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	members := []models.WorkspaceMember{}
+	if err := cursor.All(context.Background(), &members); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+func (r *WorkspaceRepository) UpdateMemberRole(workspaceID, userID, role string) error {
+	filter := bson.M{"workspaceId": workspaceID, "userId": userID}
+	_, err := r.members.UpdateOne(context.Background(), filter, bson.M{"$set": bson.M{"role": role}})
+	return err
+}
+
+func (r *WorkspaceRepository) RemoveMember(workspaceID, userID string) error {
+	_, err := r.members.DeleteOne(context.Background(), bson.M{"workspaceId": workspaceID, "userId": userID})
+	return err
+}
+
+// CountOwners counts the owners of the workspace, which must keep at least
+// one.
+func (r *WorkspaceRepository) CountOwners(workspaceID string) (int64, error) {
+	filter := bson.M{"workspaceId": workspaceID, "role": models.RoleOwner}
+	return r.members.CountDocuments(context.Background(), filter)
+}
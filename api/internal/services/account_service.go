@@ -0,0 +1,388 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"financial-api/internal/logger"
+	"financial-api/internal/mailer"
+	"financial-api/internal/models"
+	"financial-api/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrInvalidAccountToken = errors.New("invalid or expired token")
+	ErrInvalidPassword     = errors.New("current password is incorrect")
+	ErrEmailTaken          = errors.New("email already in use")
+	ErrReauthRequired      = errors.New("sign in again to confirm this change")
+)
+
+const (
+	deletionTTL = 10 * time.Minute
+
+	// reauthWindow is how recent a sign in must be to confirm a sensitive
+	// change on an account without a password.
+	reauthWindow = 10 * time.Minute
+)
+
+// AccountService manages the account itself: the profile, its deletion and
+// the flows confirmed by a token sent by email, password reset and email
+// verification.
+type AccountService struct {
+	userRepo    *repositories.UserRepository
+	userData    *repositories.UserDataRepository
+	exports     *ExportService
+	workspaces  *WorkspaceService
+	tokenRepo   *repositories.AccountTokenRepository
+	sessionRepo *repositories.SessionRepository
+	apiKeyRepo  *repositories.APIKeyRepository
+	security    *SecurityService
+	mailer      mailer.Mailer
+	appURL      string
+	resetTTL    time.Duration
+	verifyTTL   time.Duration
+}
+
+func NewAccountService(
+	userRepo *repositories.UserRepository,
+	userData *repositories.UserDataRepository,
+	exports *ExportService,
+	workspaces *WorkspaceService,
+	tokenRepo *repositories.AccountTokenRepository,
+	sessionRepo *repositories.SessionRepository,
+	apiKeyRepo *repositories.APIKeyRepository,
+	security *SecurityService,
+	sender mailer.Mailer,
+	appURL string,
+	resetTTL, verifyTTL time.Duration,
+) *AccountService {
+	return &AccountService{
+		userRepo:    userRepo,
+		userData:    userData,
+		exports:     exports,
+		workspaces:  workspaces,
+		tokenRepo:   tokenRepo,
+		sessionRepo: sessionRepo,
+		apiKeyRepo:  apiKeyRepo,
+		security:    security,
+		mailer:      sender,
+		appURL:      appURL,
+		resetTTL:    resetTTL,
+		verifyTTL:   verifyTTL,
+	}
+}
+
+// UpdateProfile applies the changes in the request. A new email must be
+// verified again, and a new password logs out every other session and
+// revokes the API keys.
+func (s *AccountService) UpdateProfile(userID, sessionID string, req *models.UpdateProfileRequest, clientIP string) (*models.User, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Email != nil || req.NewPassword != nil {
+		if err := reauthenticate(s.sessionRepo, user, sessionID, req.CurrentPassword); err != nil {
+			return nil, err
+		}
+	}
+
+	// Everything is checked before anything is written, so a rejected
+	// request leaves the profile as it was
+	emailChanged := req.Email != nil && !strings.EqualFold(*req.Email, user.Email)
+	if emailChanged {
+		_, err := s.userRepo.FindByEmail(*req.Email)
+		if err == nil {
+			return nil, ErrEmailTaken
+		}
+		if err != mongo.ErrNoDocuments {
+			return nil, err
+		}
+	}
+
+	var hashedPassword []byte
+	var keep primitive.ObjectID
+	if req.NewPassword != nil {
+		hashedPassword, err = bcrypt.GenerateFromPassword([]byte(*req.NewPassword), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, err
+		}
+		keep, err = primitive.ObjectIDFromHex(sessionID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if req.Name != nil && *req.Name != user.Name {
+		if err := s.userRepo.UpdateName(userID, *req.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	if emailChanged {
+		if err := s.userRepo.UpdateEmail(userID, *req.Email); err != nil {
+			return nil, err
+		}
+		if err := s.security.Audit(models.SecurityEmailChanged, user, clientIP); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.NewPassword != nil {
+		if err := s.userRepo.UpdatePassword(userID, string(hashedPassword)); err != nil {
+			return nil, err
+		}
+
+		// Whoever knew the old password may have signed in or created a key
+		if err := s.sessionRepo.RevokeOthers(userID, keep); err != nil {
+			return nil, err
+		}
+		if err := s.apiKeyRepo.DeleteAll(userID); err != nil {
+			return nil, err
+		}
+		if err := s.security.Audit(models.SecurityPasswordChanged, user, clientIP); err != nil {
+			return nil, err
+		}
+	}
+
+	updated, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The email is changed either way; the link can be sent again
+	if emailChanged {
+		if err := s.SendVerification(updated); err != nil {
+			logger.Logger.Error("Failed to send verification email",
+				zap.Error(err),
+				zap.String("user_id", userID),
+			)
+		}
+	}
+	return updated, nil
+}
+
+// RequestDeletion checks the password and returns the token that confirms
+// the deletion. Users who alone own a shared workspace must hand it over
+// first.
+func (s *AccountService) RequestDeletion(userID, sessionID, password string) (*models.DeletionConfirmation, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := reauthenticate(s.sessionRepo, user, sessionID, password); err != nil {
+		return nil, err
+	}
+	if err := s.workspaces.CheckDeletion(userID); err != nil {
+		return nil, err
+	}
+
+	token, err := issueAccountToken(s.tokenRepo, userID, models.TokenAccountDeletion, deletionTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &models.DeletionConfirmation{
+		ConfirmationToken: token,
+		ExpiresAt:         time.Now().Add(deletionTTL),
+	}, nil
+}
+
+// reauthenticate confirms a sensitive change with the current password. An
+// account created through an identity provider has none, so it must have
+// signed in within reauthWindow instead.
+func reauthenticate(sessionRepo *repositories.SessionRepository, user *models.User, sessionID, password string) error {
+	if user.Password != "" {
+		if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+			return ErrInvalidPassword
+		}
+		return nil
+	}
+
+	session, err := sessionRepo.FindByID(sessionID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return ErrReauthRequired
+		}
+		return err
+	}
+	if session.UserID != user.ID.Hex() || session.RevokedAt != nil || time.Since(session.CreatedAt) > reauthWindow {
+		return ErrReauthRequired
+	}
+	return nil
+}
+
+// DeleteAccount deletes the user, everything the user owns and the
+// workspaces nobody else uses.
+func (s *AccountService) DeleteAccount(userID, confirmationToken string) error {
+	stored, err := s.consume(confirmationToken, models.TokenAccountDeletion)
+	if err != nil {
+		return err
+	}
+	if stored.UserID != userID {
+		return ErrInvalidAccountToken
+	}
+	if err := s.workspaces.DeleteForUser(userID); err != nil {
+		return err
+	}
+	if err := s.exports.DeleteForUser(userID); err != nil {
+		return err
+	}
+	return s.userData.DeleteAll(userID)
+}
+
+// RequestPasswordReset mails a reset link. Unknown emails succeed silently so
+// the endpoint does not reveal which accounts exist.
+func (s *AccountService) RequestPasswordReset(email string) error {
+	user, err := s.userRepo.FindByEmail(email)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil
+		}
+		return err
+	}
+
+	token, err := issueAccountToken(s.tokenRepo, user.ID.Hex(), models.TokenPasswordReset, s.resetTTL)
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "Redefinição de senha",
+		Body: fmt.Sprintf("Olá, %s.\n\nPara criar uma nova senha, acesse o link abaixo em até %s:\n\n%s\n\nSe você não pediu a redefinição, ignore este email.",
+			user.Name, formatTTL(s.resetTTL), tokenLink(s.appURL, "/reset-password", token)),
+	})
+}
+
+// ResetPassword sets the new password, logs out every session, revokes every
+// API key and lifts a lockout. Following the link also proves the user owns
+// the email.
+func (s *AccountService) ResetPassword(token, password, clientIP string) error {
+	stored, err := s.consume(token, models.TokenPasswordReset)
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	if err := s.userRepo.UpdatePassword(stored.UserID, string(hashedPassword)); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.FindByID(stored.UserID)
+	if err != nil {
+		return err
+	}
+	if user.EmailVerifiedAt == nil {
+		if err := s.userRepo.MarkEmailVerified(stored.UserID); err != nil {
+			return err
+		}
+	}
+
+	// Whoever knew the old password may have signed in or created a key
+	if err := s.sessionRepo.RevokeAll(stored.UserID); err != nil {
+		return err
+	}
+	if err := s.apiKeyRepo.DeleteAll(stored.UserID); err != nil {
+		return err
+	}
+	if err := s.security.ClearLockout(user, clientIP); err != nil {
+		return err
+	}
+	return s.security.Audit(models.SecurityPasswordReset, user, clientIP)
+}
+
+// SendVerification mails an email verification link, unless the email is
+// already verified.
+func (s *AccountService) SendVerification(user *models.User) error {
+	if user.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	token, err := issueAccountToken(s.tokenRepo, user.ID.Hex(), models.TokenEmailVerification, s.verifyTTL)
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "Confirme seu email",
+		Body: fmt.Sprintf("Olá, %s.\n\nConfirme seu email acessando o link abaixo em até %s:\n\n%s",
+			user.Name, formatTTL(s.verifyTTL), tokenLink(s.appURL, "/verify-email", token)),
+	})
+}
+
+// ResendVerification mails a new verification link. Unknown emails succeed
+// silently, as in RequestPasswordReset.
+func (s *AccountService) ResendVerification(email string) error {
+	user, err := s.userRepo.FindByEmail(email)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil
+		}
+		return err
+	}
+	return s.SendVerification(user)
+}
+
+func (s *AccountService) VerifyEmail(token string) error {
+	stored, err := s.consume(token, models.TokenEmailVerification)
+	if err != nil {
+		return err
+	}
+	return s.userRepo.MarkEmailVerified(stored.UserID)
+}
+
+func (s *AccountService) consume(token, purpose string) (*models.AccountToken, error) {
+	stored, err := s.tokenRepo.Consume(hashToken(token), purpose)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrInvalidAccountToken
+		}
+		return nil, err
+	}
+	return stored, nil
+}
+
+// issueAccountToken stores a new token for the purpose, replacing the unused
+// ones, and returns it in clear to be mailed.
+func issueAccountToken(repo *repositories.AccountTokenRepository, userID, purpose string, ttl time.Duration) (string, error) {
+	token, err := newToken()
+	if err != nil {
+		return "", err
+	}
+
+	err = repo.Replace(&models.AccountToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(ttl),
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func tokenLink(appURL, path, token string) string {
+	return appURL + path + "?token=" + url.QueryEscape(token)
+}
+
+// formatTTL writes a lifetime in whole hours, in Portuguese.
+func formatTTL(ttl time.Duration) string {
+	hours := int(ttl.Hours())
+	if hours <= 1 {
+		return "1 hora"
+	}
+	return fmt.Sprintf("%d horas", hours)
+}
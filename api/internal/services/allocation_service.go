@@ -0,0 +1,182 @@
+package services
+
+import (
+	"errors"
+	"math"
+
+	"financial-api/internal/models"
+	"financial-api/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	RebalanceContribution = "contribution"
+	RebalanceFull         = "full"
+)
+
+var (
+	ErrTargetsNotFound    = errors.New("allocation targets not set")
+	ErrInvalidTargets     = errors.New("target percentages must add up to 100")
+	ErrInvalidRebalancing = errors.New("mode must be contribution or full, with a non-negative contribution")
+)
+
+// targetSumTolerance absorbs rounding in user-entered percentages.
+const targetSumTolerance = 0.01
+
+type AllocationService struct {
+	repo            *repositories.AllocationRepository
+	aggregationRepo *repositories.AggregationRepository
+}
+
+func NewAllocationService(repo *repositories.AllocationRepository, aggregationRepo *repositories.AggregationRepository) *AllocationService {
+	return &AllocationService{repo: repo, aggregationRepo: aggregationRepo}
+}
+
+func (s *AllocationService) SetTargets(targets *models.AllocationTargets, workspaceID, role string) error {
+	if err := requireRole(role, models.RoleEditor); err != nil {
+		return err
+	}
+
+	var sum float64
+	for _, target := range targets.Targets {
+		sum += target.Percentage
+	}
+	if math.Abs(sum-100) > targetSumTolerance {
+		return ErrInvalidTargets
+	}
+
+	return s.repo.Save(targets, workspaceID)
+}
+
+func (s *AllocationService) GetTargets(workspaceID string) (*models.AllocationTargets, error) {
+	targets, err := s.repo.FindByWorkspace(workspaceID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrTargetsNotFound
+		}
+		return nil, err
+	}
+	return targets, nil
+}
+
+// Rebalance compares the current split with the targets. In contribution mode
+// the new money goes to the groups furthest below target and nothing is sold;
+// in full mode every group outside the tolerance band gets the buy or sell that
+// brings it back to target. Suggestions below the minimum trade size are dropped.
+func (s *AllocationService) Rebalance(workspaceID, mode string, contribution float64) (*models.RebalanceReport, error) {
+	if (mode != RebalanceContribution && mode != RebalanceFull) || contribution < 0 {
+		return nil, ErrInvalidRebalancing
+	}
+
+	targets, err := s.GetTargets(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := s.aggregationRepo.GetInvestmentTypes(workspaceID, targets.GroupBy)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.RebalanceReport{
+		GroupBy:      targets.GroupBy,
+		Mode:         mode,
+		Contribution: contribution,
+		Tolerance:    targets.Tolerance,
+		MinTradeSize: targets.MinTradeSize,
+		Balanced:     true,
+	}
+
+	// One item per target, plus held groups without a target (target 0%)
+	index := map[string]int{}
+	for _, target := range targets.Targets {
+		index[target.Name] = len(report.Items)
+		report.Items = append(report.Items, models.RebalanceItem{Name: target.Name, TargetPercentage: target.Percentage})
+	}
+	for _, group := range current {
+		i, ok := index[group.Name]
+		if !ok {
+			i = len(report.Items)
+			index[group.Name] = i
+			report.Items = append(report.Items, models.RebalanceItem{Name: group.Name})
+		}
+		report.Items[i].CurrentValue = group.Value
+		report.TotalValue += group.Value
+	}
+
+	finalTotal := report.TotalValue
+	if mode == RebalanceContribution {
+		finalTotal += contribution
+	}
+
+	for i := range report.Items {
+		item := &report.Items[i]
+		if report.TotalValue > 0 {
+			item.CurrentPercentage = item.CurrentValue / report.TotalValue * 100
+		}
+		item.Drift = item.CurrentPercentage - item.TargetPercentage
+		item.WithinTolerance = math.Abs(item.Drift) <= targets.Tolerance
+		item.TargetValue = finalTotal * item.TargetPercentage / 100
+		if !item.WithinTolerance {
+			report.Balanced = false
+		}
+	}
+
+	if mode == RebalanceContribution {
+		allocateContribution(report, contribution)
+	} else {
+		for i := range report.Items {
+			item := &report.Items[i]
+			if item.WithinTolerance {
+				continue
+			}
+			if trade := item.TargetValue - item.CurrentValue; math.Abs(trade) >= targets.MinTradeSize {
+				item.Suggested = trade
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// allocateContribution splits new money across the groups below their target
+// value, proportionally to how far below they are. Groups whose share would not
+// reach the minimum trade size are left out and their share goes to the others.
+func allocateContribution(report *models.RebalanceReport, contribution float64) {
+	excluded := map[int]bool{}
+	for {
+		var deficit float64
+		for i, item := range report.Items {
+			if gap := item.TargetValue - item.CurrentValue; gap > 0 && !excluded[i] {
+				deficit += gap
+			}
+		}
+		if deficit <= 0 || contribution <= 0 {
+			report.Unallocated = contribution
+			return
+		}
+
+		amount := math.Min(contribution, deficit)
+		tooSmall := false
+		for i, item := range report.Items {
+			gap := item.TargetValue - item.CurrentValue
+			if gap > 0 && !excluded[i] && amount*gap/deficit < report.MinTradeSize {
+				excluded[i] = true
+				tooSmall = true
+			}
+		}
+		if tooSmall {
+			continue
+		}
+
+		for i := range report.Items {
+			item := &report.Items[i]
+			if gap := item.TargetValue - item.CurrentValue; gap > 0 && !excluded[i] {
+				item.Suggested = amount * gap / deficit
+			}
+		}
+		report.Unallocated = contribution - amount
+		return
+	}
+}
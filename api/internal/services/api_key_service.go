@@ -0,0 +1,102 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"financial-api/internal/models"
+	"financial-api/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrAPIKeyNotFound = errors.New("API key not found")
+	ErrInvalidAPIKey  = errors.New("invalid or expired API key")
+)
+
+const (
+	apiKeyPrefixLength = len(models.APIKeyPrefix) + 8
+	apiKeyTouchPeriod  = time.Minute
+)
+
+// APIKeyService manages the personal access tokens scripts use instead of a
+// login.
+type APIKeyService struct {
+	repo     *repositories.APIKeyRepository
+	userRepo *repositories.UserRepository
+}
+
+func NewAPIKeyService(repo *repositories.APIKeyRepository, userRepo *repositories.UserRepository) *APIKeyService {
+	return &APIKeyService{
+		repo:     repo,
+		userRepo: userRepo,
+	}
+}
+
+// Create issues a key. The key is only ever returned here.
+func (s *APIKeyService) Create(userID string, req *models.CreateAPIKeyRequest) (*models.CreatedAPIKey, error) {
+	secret, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	raw := models.APIKeyPrefix + secret
+
+	key := models.APIKey{
+		UserID:  userID,
+		Name:    req.Name,
+		Prefix:  raw[:apiKeyPrefixLength],
+		KeyHash: hashToken(raw),
+		Scopes:  req.Scopes,
+	}
+	if req.ExpiresInDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, req.ExpiresInDays)
+		key.ExpiresAt = &expiresAt
+	}
+	if err := s.repo.Create(&key); err != nil {
+		return nil, err
+	}
+
+	return &models.CreatedAPIKey{APIKey: key, Key: raw}, nil
+}
+
+func (s *APIKeyService) GetAPIKeys(userID string) ([]models.APIKey, error) {
+	return s.repo.FindByUser(userID)
+}
+
+func (s *APIKeyService) Revoke(id, userID string) error {
+	if err := s.repo.Delete(id, userID); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return ErrAPIKeyNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// Authenticate resolves a key to its owner, recording the use.
+func (s *APIKeyService) Authenticate(raw string) (*models.APIKey, *models.User, error) {
+	key, err := s.repo.FindByHash(hashToken(raw))
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil, ErrInvalidAPIKey
+		}
+		return nil, nil, err
+	}
+	if key.ExpiresAt != nil && time.Now().After(*key.ExpiresAt) {
+		return nil, nil, ErrInvalidAPIKey
+	}
+
+	user, err := s.userRepo.FindByID(key.UserID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil, ErrInvalidAPIKey
+		}
+		return nil, nil, err
+	}
+
+	if err := s.repo.Touch(key.ID, apiKeyTouchPeriod); err != nil {
+		return nil, nil, err
+	}
+	return key, user, nil
+}
@@ -1,32 +1,78 @@
 package services
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
-	"os"
 	"time"
 
+	"financial-api/internal/jwtkeys"
 	"financial-api/internal/models"
 	"financial-api/internal/repositories"
 
 	"github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"golang.org/x/crypto/bcrypt"
 )
 
+var (
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reused, session revoked")
+	ErrSessionRevoked      = errors.New("session revoked")
+	ErrEmailNotVerified    = errors.New("email not verified")
+	ErrInvalidChallenge    = errors.New("invalid or expired two-factor challenge")
+)
+
+const (
+	challengeTTL         = 5 * time.Minute
+	maxChallengeAttempts = 5
+)
+
+// TokenPolicy sets how access tokens are signed and checked and how long
+// tokens last.
+type TokenPolicy struct {
+	Keys       *jwtkeys.KeySet
+	Issuer     string
+	Audience   string
+	AccessTTL  time.Duration
+	RefreshTTL time.Duration
+}
+
 type AuthService struct {
-	userRepo  *repositories.UserRepository
-	jwtSecret []byte
+	userRepo      *repositories.UserRepository
+	sessionRepo   *repositories.SessionRepository
+	tokenRepo     *repositories.AccountTokenRepository
+	apiKeyRepo    *repositories.APIKeyRepository
+	twoFactor     *TwoFactorService
+	security      *SecurityService
+	tokens        TokenPolicy
+	requireVerify bool
 }
 
-func NewAuthService(userRepo *repositories.UserRepository) *AuthService {
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		secret = "default-secret-change-in-production"
-	}
-	
+// NewAuthService builds the service. With requireVerify, accounts must verify
+// their email before they can log in.
+func NewAuthService(
+	userRepo *repositories.UserRepository,
+	sessionRepo *repositories.SessionRepository,
+	tokenRepo *repositories.AccountTokenRepository,
+	apiKeyRepo *repositories.APIKeyRepository,
+	twoFactor *TwoFactorService,
+	security *SecurityService,
+	tokens TokenPolicy,
+	requireVerify bool,
+) *AuthService {
 	return &AuthService{
-		userRepo:  userRepo,
-		jwtSecret: []byte(secret),
+		userRepo:      userRepo,
+		sessionRepo:   sessionRepo,
+		tokenRepo:     tokenRepo,
+		apiKeyRepo:    apiKeyRepo,
+		twoFactor:     twoFactor,
+		security:      security,
+		tokens:        tokens,
+		requireVerify: requireVerify,
 	}
 }
 
@@ -57,23 +103,27 @@ func (s *AuthService) Register(req *models.RegisterRequest) (*models.AuthRespons
 		return nil, err
 	}
 
-	// Generate token
-	token, err := s.generateToken(user.ID.Hex(), user.Email)
-	if err != nil {
-		return nil, err
+	// No session until the email is verified
+	if s.requireVerify {
+		return &models.AuthResponse{User: *user}, nil
 	}
 
-	return &models.AuthResponse{
-		Token: token,
-		User:  *user,
-	}, nil
+	return s.startSession(user)
 }
 
-func (s *AuthService) Login(req *models.LoginRequest) (*models.AuthResponse, error) {
+func (s *AuthService) Login(req *models.LoginRequest, clientIP string) (*models.AuthResponse, error) {
+	// Throttle repeated failures
+	if err := s.security.CheckLogin(req.Email, clientIP); err != nil {
+		return nil, err
+	}
+
 	// Find user
 	user, err := s.userRepo.FindByEmail(req.Email)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
+			if err := s.security.RecordFailure(req.Email, clientIP, nil); err != nil {
+				return nil, err
+			}
 			return nil, errors.New("invalid credentials")
 		}
 		return nil, err
@@ -81,25 +131,91 @@ func (s *AuthService) Login(req *models.LoginRequest) (*models.AuthResponse, err
 
 	// Check password
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
+		if err := s.security.RecordFailure(req.Email, clientIP, user); err != nil {
+			return nil, err
+		}
 		return nil, errors.New("invalid credentials")
 	}
 
-	// Generate token
-	token, err := s.generateToken(user.ID.Hex(), user.Email)
+	if err := s.security.RecordSuccess(req.Email); err != nil {
+		return nil, err
+	}
+
+	if s.requireVerify && user.EmailVerifiedAt == nil {
+		return nil, ErrEmailNotVerified
+	}
+
+	if user.TwoFactor.Enabled {
+		return s.startChallenge(user)
+	}
+
+	return s.startSession(user)
+}
+
+// LoginExternal signs in a user an identity provider authenticated, still
+// asking for the second factor when it is enabled.
+func (s *AuthService) LoginExternal(user *models.User) (*models.AuthResponse, error) {
+	if user.TwoFactor.Enabled {
+		return s.startChallenge(user)
+	}
+
+	return s.startSession(user)
+}
+
+// VerifyTwoFactor is the second login step: a valid code for the challenge
+// issued by Login starts the session. Too many wrong codes void the challenge,
+// and too many across challenges lock the user's second factor.
+func (s *AuthService) VerifyTwoFactor(challengeToken, code, clientIP string) (*models.AuthResponse, error) {
+	challenge, err := s.tokenRepo.Find(hashToken(challengeToken), models.TokenTwoFactorChallenge)
 	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrInvalidChallenge
+		}
 		return nil, err
 	}
 
-	return &models.AuthResponse{
-		Token: token,
-		User:  *user,
-	}, nil
+	user, err := s.userRepo.FindByID(challenge.UserID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrInvalidChallenge
+		}
+		return nil, err
+	}
+
+	if err := s.twoFactor.VerifyThrottled(user, code, clientIP); err != nil {
+		if errors.Is(err, ErrInvalidTwoFactorCode) {
+			attempts, attemptErr := s.tokenRepo.AddAttempt(challenge.ID)
+			if attemptErr != nil {
+				return nil, attemptErr
+			}
+			if attempts >= maxChallengeAttempts {
+				if _, attemptErr := s.tokenRepo.MarkUsed(challenge.ID); attemptErr != nil {
+					return nil, attemptErr
+				}
+			}
+		}
+		return nil, err
+	}
+
+	spent, err := s.tokenRepo.MarkUsed(challenge.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !spent {
+		return nil, ErrInvalidChallenge
+	}
+
+	return s.startSession(user)
 }
 
 func (s *AuthService) ValidateToken(tokenString string) (*models.JWTClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return s.jwtSecret, nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &jwt.MapClaims{}, s.tokens.Keys.Keyfunc,
+		jwt.WithValidMethods(s.tokens.Keys.Methods()),
+		jwt.WithIssuer(s.tokens.Issuer),
+		jwt.WithAudience(s.tokens.Audience),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	)
 
 	if err != nil || !token.Valid {
 		return nil, errors.New("invalid token")
@@ -120,24 +236,207 @@ func (s *AuthService) ValidateToken(tokenString string) (*models.JWTClaims, erro
 		return nil, errors.New("invalid email in token")
 	}
 
+	sessionID, ok := (*claims)["sid"].(string)
+	if !ok {
+		return nil, errors.New("invalid sid in token")
+	}
+
 	return &models.JWTClaims{
-		UserID: userID,
-		Email:  email,
+		UserID:    userID,
+		Email:     email,
+		SessionID: sessionID,
 	}, nil
 }
 
+// CheckSession rejects access tokens whose session was logged out or revoked
+// after a refresh token reuse.
+func (s *AuthService) CheckSession(claims *models.JWTClaims) error {
+	session, err := s.sessionRepo.FindByID(claims.SessionID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return ErrSessionRevoked
+		}
+		return err
+	}
+	if session.RevokedAt != nil || session.UserID != claims.UserID {
+		return ErrSessionRevoked
+	}
+	return nil
+}
+
+// Refresh spends a refresh token and returns a new token pair in the same
+// session. A token that was already spent means it leaked, so the whole
+// session is revoked.
+func (s *AuthService) Refresh(refreshToken string) (*models.AuthResponse, error) {
+	stored, err := s.sessionRepo.FindRefreshToken(hashToken(refreshToken))
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	if stored.UsedAt != nil {
+		return nil, s.revokeReused(stored)
+	}
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	session, err := s.sessionRepo.FindByID(stored.SessionID.Hex())
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+	if session.RevokedAt != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	spent, err := s.sessionRepo.MarkRefreshTokenUsed(stored.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !spent {
+		return nil, s.revokeReused(stored)
+	}
+
+	user, err := s.userRepo.FindByID(stored.UserID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	if err := s.sessionRepo.Extend(session.ID, time.Now().Add(s.tokens.RefreshTTL)); err != nil {
+		return nil, err
+	}
+
+	return s.issueTokens(user, session.ID)
+}
+
+// Logout revokes the session the access token belongs to.
+func (s *AuthService) Logout(sessionID, userID string) error {
+	objectID, err := primitive.ObjectIDFromHex(sessionID)
+	if err != nil {
+		return ErrSessionRevoked
+	}
+	return s.sessionRepo.Revoke(objectID, userID)
+}
+
+// LogoutAll revokes every session and every API key of the user.
+func (s *AuthService) LogoutAll(userID string) error {
+	if err := s.sessionRepo.RevokeAll(userID); err != nil {
+		return err
+	}
+	return s.apiKeyRepo.DeleteAll(userID)
+}
+
 func (s *AuthService) GetUserByID(userID string) (*models.User, error) {
 	return s.userRepo.FindByID(userID)
 }
 
-func (s *AuthService) generateToken(userID, email string) (string, error) {
+func (s *AuthService) startChallenge(user *models.User) (*models.AuthResponse, error) {
+	challengeToken, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.tokenRepo.Replace(&models.AccountToken{
+		UserID:    user.ID.Hex(),
+		Purpose:   models.TokenTwoFactorChallenge,
+		TokenHash: hashToken(challengeToken),
+		ExpiresAt: time.Now().Add(challengeTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.AuthResponse{
+		TwoFactorRequired: true,
+		ChallengeToken:    challengeToken,
+		User:              *user,
+	}, nil
+}
+
+func (s *AuthService) startSession(user *models.User) (*models.AuthResponse, error) {
+	session := &models.Session{
+		UserID:    user.ID.Hex(),
+		ExpiresAt: time.Now().Add(s.tokens.RefreshTTL),
+	}
+	if err := s.sessionRepo.Create(session); err != nil {
+		return nil, err
+	}
+
+	return s.issueTokens(user, session.ID)
+}
+
+func (s *AuthService) issueTokens(user *models.User, sessionID primitive.ObjectID) (*models.AuthResponse, error) {
+	token, err := s.generateToken(user.ID.Hex(), user.Email, sessionID.Hex())
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.sessionRepo.CreateRefreshToken(&models.RefreshToken{
+		SessionID: sessionID,
+		UserID:    user.ID.Hex(),
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: time.Now().Add(s.tokens.RefreshTTL),
+	}); err != nil {
+		return nil, err
+	}
+
+	return &models.AuthResponse{
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(s.tokens.AccessTTL.Seconds()),
+		User:         *user,
+	}, nil
+}
+
+func (s *AuthService) revokeReused(token *models.RefreshToken) error {
+	if err := s.sessionRepo.Revoke(token.SessionID, token.UserID); err != nil {
+		return err
+	}
+	return ErrRefreshTokenReused
+}
+
+// newToken returns 256 random bits, URL-safe.
+func newToken() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *AuthService) generateToken(userID, email, sessionID string) (string, error) {
 	claims := jwt.MapClaims{
 		"user_id": userID,
 		"email":   email,
-		"exp":     time.Now().Add(24 * time.Hour).Unix(),
+		"sid":     sessionID,
+		"iss":     s.tokens.Issuer,
+		"aud":     s.tokens.Audience,
+		"exp":     time.Now().Add(s.tokens.AccessTTL).Unix(),
 		"iat":     time.Now().Unix(),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(s.jwtSecret)
+	return s.tokens.Keys.Sign(claims)
+}
+
+// JWKS returns the public keys that verify access tokens.
+func (s *AuthService) JWKS() jwtkeys.JWKS {
+	return s.tokens.Keys.JWKS()
 }
\ No newline at end of file
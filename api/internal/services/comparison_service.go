@@ -0,0 +1,159 @@
+package services
+
+import (
+	"errors"
+	"financial-api/internal/finance"
+	"financial-api/internal/models"
+	"financial-api/internal/repositories"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	CompareMonthOverMonth = "mom"
+	CompareYearOverYear   = "yoy"
+
+	// Categories listed in each ranking
+	comparisonRankSize = 5
+)
+
+var ErrInvalidComparison = errors.New("compare mom or yoy for a YYYY-MM month, or give both periods as YYYY-MM-DD ranges")
+
+type ComparisonService struct {
+	aggregationRepo *repositories.AggregationRepository
+}
+
+func NewComparisonService(aggregationRepo *repositories.AggregationRepository) *ComparisonService {
+	return &ComparisonService{aggregationRepo: aggregationRepo}
+}
+
+// ComparePeriods resolves the periods to compare: a month against the previous
+// month (mom) or the same month a year earlier (yoy), defaulting to the current
+// month. Explicit ranges are used as given.
+func ComparePeriods(mode, month string, current, previous models.ReportPeriod) (models.ReportPeriod, models.ReportPeriod, error) {
+	if current != (models.ReportPeriod{}) || previous != (models.ReportPeriod{}) {
+		for _, date := range []string{current.From, current.To, previous.From, previous.To} {
+			if _, err := finance.ParseDate(date); err != nil {
+				return current, previous, ErrInvalidComparison
+			}
+		}
+		if current.From > current.To || previous.From > previous.To {
+			return current, previous, ErrInvalidComparison
+		}
+		return current, previous, nil
+	}
+
+	start := monthStart(time.Now().UTC())
+	if month != "" {
+		parsed, err := time.Parse("2006-01", month)
+		if err != nil {
+			return current, previous, ErrInvalidComparison
+		}
+		start = parsed
+	}
+
+	var previousStart time.Time
+	switch mode {
+	case CompareMonthOverMonth:
+		previousStart = start.AddDate(0, -1, 0)
+	case CompareYearOverYear:
+		previousStart = start.AddDate(-1, 0, 0)
+	default:
+		return current, previous, ErrInvalidComparison
+	}
+
+	return monthPeriod(start), monthPeriod(previousStart), nil
+}
+
+func (s *ComparisonService) Compare(workspaceID string, current, previous models.ReportPeriod) (*models.ComparisonReport, error) {
+	currentIncome, currentExpenses, err := s.aggregationRepo.GetPeriodTotals(workspaceID, current.From, current.To)
+	if err != nil {
+		return nil, err
+	}
+	previousIncome, previousExpenses, err := s.aggregationRepo.GetPeriodTotals(workspaceID, previous.From, previous.To)
+	if err != nil {
+		return nil, err
+	}
+
+	currentCategories, err := s.aggregationRepo.GetCategoryTotals(workspaceID, "expense", current.From, current.To)
+	if err != nil {
+		return nil, err
+	}
+	previousCategories, err := s.aggregationRepo.GetCategoryTotals(workspaceID, "expense", previous.From, previous.To)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.ComparisonReport{
+		Current:    current,
+		Previous:   previous,
+		Income:     delta(currentIncome, previousIncome),
+		Expenses:   delta(currentExpenses, previousExpenses),
+		Balance:    delta(currentIncome-currentExpenses, previousIncome-previousExpenses),
+		Categories: []models.CategoryDelta{},
+		Increases:  []models.CategoryDelta{},
+		Decreases:  []models.CategoryDelta{},
+	}
+
+	index := map[string]int{}
+	for _, category := range currentCategories {
+		index[category.Name] = len(report.Categories)
+		report.Categories = append(report.Categories, models.CategoryDelta{
+			Name:  category.Name,
+			Color: category.Color,
+			Delta: models.Delta{Current: category.Value},
+		})
+	}
+	for _, category := range previousCategories {
+		i, ok := index[category.Name]
+		if !ok {
+			i = len(report.Categories)
+			index[category.Name] = i
+			report.Categories = append(report.Categories, models.CategoryDelta{Name: category.Name, Color: category.Color})
+		}
+		report.Categories[i].Previous = category.Value
+	}
+
+	for i := range report.Categories {
+		category := &report.Categories[i]
+		category.Delta = delta(category.Current, category.Previous)
+		switch {
+		case category.Change > 0:
+			report.Increases = append(report.Increases, *category)
+		case category.Change < 0:
+			report.Decreases = append(report.Decreases, *category)
+		}
+	}
+
+	sort.SliceStable(report.Increases, func(i, j int) bool {
+		return report.Increases[i].Change > report.Increases[j].Change
+	})
+	sort.SliceStable(report.Decreases, func(i, j int) bool {
+		return report.Decreases[i].Change < report.Decreases[j].Change
+	})
+	if len(report.Increases) > comparisonRankSize {
+		report.Increases = report.Increases[:comparisonRankSize]
+	}
+	if len(report.Decreases) > comparisonRankSize {
+		report.Decreases = report.Decreases[:comparisonRankSize]
+	}
+
+	return report, nil
+}
+
+func delta(current, previous float64) models.Delta {
+	d := models.Delta{Current: current, Previous: previous, Change: current - previous}
+	if previous != 0 {
+		percent := math.Round((current-previous)/math.Abs(previous)*10000) / 100
+		d.ChangePercent = &percent
+	}
+	return d
+}
+
+func monthPeriod(start time.Time) models.ReportPeriod {
+	return models.ReportPeriod{
+		From: start.Format(finance.DateLayout),
+		To:   start.AddDate(0, 1, -1).Format(finance.DateLayout),
+	}
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"financial-api/internal/finance"
+)
+
+// readCSVRecords reads every record of a CSV document, detecting whether it is
+// separated by "," or by ";" as in the files exported by Brazilian sources.
+func readCSVRecords(r io.Reader) ([][]string, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(strings.NewReader(string(content)))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if strings.Count(string(content), ";") > strings.Count(string(content), ",")/2 {
+		reader.Comma = ';'
+	}
+
+	return reader.ReadAll()
+}
+
+// csvFiles lists the CSV files of a directory.
+func csvFiles(dir string) ([]string, error) {
+	return filepath.Glob(filepath.Join(dir, "*.csv"))
+}
+
+// fileStem returns the upper-cased file name without its extension.
+func fileStem(path string) string {
+	return strings.ToUpper(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)))
+}
+
+func parseImportDate(value string) (time.Time, error) {
+	for _, layout := range []string{finance.DateLayout, "02/01/2006", "01/2006", "2006-01"} {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
+
+// parseDecimal accepts both "1234.56" and the Brazilian "1234,56".
+func parseDecimal(value string) (float64, error) {
+	raw := strings.TrimSpace(value)
+	if !strings.Contains(raw, ".") {
+		raw = strings.ReplaceAll(raw, ",", ".")
+	}
+	number, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", value)
+	}
+	return number, nil
+}
@@ -10,6 +10,7 @@ type DashboardService struct {
 	investmentRepo    *repositories.InvestmentRepository
 	categoryRepo      *repositories.CategoryRepository
 	aggregationRepo   *repositories.AggregationRepository
+	investmentService *InvestmentService
 }
 
 func NewDashboardService(
@@ -17,24 +18,32 @@ func NewDashboardService(
 	investmentRepo *repositories.InvestmentRepository,
 	categoryRepo *repositories.CategoryRepository,
 	aggregationRepo *repositories.AggregationRepository,
+	investmentService *InvestmentService,
 ) *DashboardService {
 	return &DashboardService{
-		transactionRepo: transactionRepo,
-		investmentRepo:  investmentRepo,
-		categoryRepo:    categoryRepo,
-		aggregationRepo: aggregationRepo,
+		transactionRepo:   transactionRepo,
+		investmentRepo:    investmentRepo,
+		categoryRepo:      categoryRepo,
+		aggregationRepo:   aggregationRepo,
+		investmentService: investmentService,
 	}
 }
 
-func (s *DashboardService) GetSummary(userID string) (*models.DashboardSummary, error) {
+func (s *DashboardService) GetSummary(workspaceID string) (*models.DashboardSummary, error) {
 	// Get transaction totals
-	transactionTotals, err := s.transactionRepo.GetTotals(userID)
+	transactionTotals, err := s.transactionRepo.GetTotals(workspaceID)
 	if err != nil {
 		return nil, err
 	}
 
 	// Get investment totals
-	totalInvestments, totalMonthlyReturn, averageRate, err := s.investmentRepo.GetTotals(userID)
+	totalInvestments, totalMonthlyReturn, averageRate, err := s.investmentRepo.GetTotals(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get gross and net values after redemption taxes
+	totalGrossValue, totalTaxDue, totalNetValue, err := s.investmentService.GetValuationTotals(workspaceID)
 	if err != nil {
 		return nil, err
 	}
@@ -53,6 +62,9 @@ func (s *DashboardService) GetSummary(userID string) (*models.DashboardSummary,
 		TotalInvestments:    totalInvestments,
 		TotalMonthlyReturn:  totalMonthlyReturn,
 		AverageRate:         averageRate,
+		TotalGrossValue:     totalGrossValue,
+		TotalTaxDue:         totalTaxDue,
+		TotalNetValue:       totalNetValue,
 	}
 
 	return &models.DashboardSummary{
@@ -61,25 +73,25 @@ func (s *DashboardService) GetSummary(userID string) (*models.DashboardSummary,
 	}, nil
 }
 
-func (s *DashboardService) GetOverview(userID string) (*models.OverviewData, error) {
+func (s *DashboardService) GetOverview(workspaceID, groupBy string) (*models.OverviewData, error) {
 	// Get basic totals
-	summary, err := s.GetSummary(userID)
+	summary, err := s.GetSummary(workspaceID)
 	if err != nil {
 		return nil, err
 	}
 
 	// Get aggregated data
-	monthlyData, err := s.aggregationRepo.GetMonthlyData(userID)
+	monthlyData, err := s.aggregationRepo.GetMonthlyData(workspaceID)
 	if err != nil {
 		monthlyData = []models.MonthlyItem{} // Fallback to empty
 	}
 
-	expenseCategories, err := s.aggregationRepo.GetExpenseCategories(userID)
+	expenseCategories, err := s.aggregationRepo.GetExpenseCategories(workspaceID)
 	if err != nil {
 		expenseCategories = []models.CategoryItem{} // Fallback to empty
 	}
 
-	investmentTypes, err := s.aggregationRepo.GetInvestmentTypes(userID)
+	investmentTypes, err := s.aggregationRepo.GetInvestmentTypes(workspaceID, groupBy)
 	if err != nil {
 		investmentTypes = []models.InvestmentType{} // Fallback to empty
 	}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"financial-api/internal/finance"
+	"financial-api/internal/models"
+	"financial-api/internal/repositories"
+)
+
+var ErrInvalidWithholding = errors.New("withholding tax exceeds gross amount")
+
+// jcpWithholdingRate is the IR withheld at source on JCP, in percent.
+const jcpWithholdingRate = 15
+
+type DividendService struct {
+	repo            *repositories.DividendRepository
+	positionService *PositionService
+}
+
+func NewDividendService(repo *repositories.DividendRepository, positionService *PositionService) *DividendService {
+	return &DividendService{repo: repo, positionService: positionService}
+}
+
+// CreateDividend records a distribution paid by a ticker the workspace has
+// traded. JCP without an explicit withholding is taxed at the source rate.
+func (s *DividendService) CreateDividend(dividend *models.Dividend, withholding *float64, workspaceID, role string) error {
+	if err := requireRole(role, models.RoleEditor); err != nil {
+		return err
+	}
+
+	if _, err := finance.ParseDate(dividend.PayDate); err != nil {
+		return ErrInvalidDate
+	}
+	dividend.Ticker = normalizeTicker(dividend.Ticker)
+
+	if _, err := s.positionService.GetPosition(workspaceID, dividend.Ticker); err != nil {
+		return err
+	}
+
+	switch {
+	case withholding != nil:
+		dividend.WithholdingTax = *withholding
+	case dividend.Kind == models.DividendKindJCP:
+		dividend.WithholdingTax = dividend.GrossAmount * jcpWithholdingRate / 100
+	}
+	if dividend.WithholdingTax > dividend.GrossAmount {
+		return ErrInvalidWithholding
+	}
+	dividend.NetAmount = dividend.GrossAmount - dividend.WithholdingTax
+
+	return s.repo.Create(dividend, workspaceID)
+}
+
+func (s *DividendService) GetDividends(workspaceID, ticker, from, to string) ([]models.Dividend, error) {
+	return s.repo.FindByWorkspace(workspaceID, normalizeTicker(ticker), from, to)
+}
+
+// GetReport summarizes the 12 calendar months of dividends ending on asOf's
+// month, with yields over the current market value of each position and of
+// the portfolio.
+func (s *DividendService) GetReport(workspaceID, date string) (*models.DividendReport, error) {
+	asOf := time.Now().UTC()
+	if date != "" {
+		parsed, err := finance.ParseDate(date)
+		if err != nil {
+			return nil, ErrInvalidDate
+		}
+		asOf = parsed
+	}
+	// Start on the first day of the month 11 months back so every dividend in
+	// the window lands in one of the 12 monthly buckets
+	from := time.Date(asOf.Year(), asOf.Month()-11, 1, 0, 0, 0, 0, time.UTC)
+
+	dividends, err := s.repo.FindByWorkspace(workspaceID, "", from.Format(finance.DateLayout), asOf.Format(finance.DateLayout))
+	if err != nil {
+		return nil, err
+	}
+
+	positions, err := s.positionService.GetPositions(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	marketValues := map[string]float64{}
+	var portfolioValue float64
+	for _, position := range positions {
+		marketValues[position.Ticker] = position.MarketValue
+		portfolioValue += position.MarketValue
+	}
+
+	report := &models.DividendReport{
+		From:   from.Format(finance.DateLayout),
+		To:     asOf.Format(finance.DateLayout),
+		Assets: []models.AssetDividendYield{},
+		Months: make([]models.MonthlyDividend, 12),
+	}
+
+	// One bucket per month of the window, oldest first
+	monthIndex := map[string]int{}
+	for i := range report.Months {
+		month := time.Date(from.Year(), from.Month()+time.Month(i), 1, 0, 0, 0, 0, time.UTC).Format("2006-01")
+		report.Months[i].Month = month
+		monthIndex[month] = i
+	}
+
+	assetIndex := map[string]int{}
+	for _, dividend := range dividends {
+		report.TotalGross += dividend.GrossAmount
+		report.TotalNet += dividend.NetAmount
+
+		if i, ok := monthIndex[dividend.PayDate[:7]]; ok {
+			report.Months[i].Gross += dividend.GrossAmount
+			report.Months[i].Net += dividend.NetAmount
+		}
+
+		i, ok := assetIndex[dividend.Ticker]
+		if !ok {
+			i = len(report.Assets)
+			assetIndex[dividend.Ticker] = i
+			report.Assets = append(report.Assets, models.AssetDividendYield{
+				Ticker:      dividend.Ticker,
+				MarketValue: marketValues[dividend.Ticker],
+			})
+		}
+		report.Assets[i].Gross += dividend.GrossAmount
+		report.Assets[i].Net += dividend.NetAmount
+	}
+
+	for i := range report.Assets {
+		report.Assets[i].Yield = yieldPercent(report.Assets[i].Gross, report.Assets[i].MarketValue)
+	}
+	for i := range report.Months {
+		report.Months[i].Yield = yieldPercent(report.Months[i].Gross, portfolioValue)
+	}
+	report.PortfolioYield = yieldPercent(report.TotalGross, portfolioValue)
+
+	return report, nil
+}
+
+// cashFlows returns the dividends received as money taken out of the portfolio.
+func (s *DividendService) cashFlows(workspaceID string) ([]finance.CashFlow, error) {
+	dividends, err := s.repo.FindByWorkspace(workspaceID, "", "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	flows := make([]finance.CashFlow, 0, len(dividends))
+	for _, dividend := range dividends {
+		date, err := finance.ParseDate(dividend.PayDate)
+		if err != nil {
+			continue
+		}
+		flows = append(flows, finance.CashFlow{Date: date, Amount: -dividend.NetAmount})
+	}
+
+	return flows, nil
+}
+
+func yieldPercent(income, value float64) float64 {
+	if value <= 0 {
+		return 0
+	}
+	return income / value * 100
+}
@@ -0,0 +1,466 @@
+package services
+
+import (
+	"archive/zip"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"financial-api/internal/logger"
+	"financial-api/internal/models"
+	"financial-api/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrExportNotFound    = errors.New("export not found")
+	ErrInvalidExportLink = errors.New("invalid or expired download link")
+)
+
+// Owned collections left out of the archive: they only hold credentials
+var exportExcluded = map[string]bool{
+	"refresh_tokens": true,
+	"account_tokens": true,
+	"api_keys":       true,
+	"oidc_states":    true,
+}
+
+// ExportService builds, in the background, a ZIP archive with every record
+// a user owns, and those of the user's workspaces, as JSON and CSV, for data
+// portability under the LGPD.
+type ExportService struct {
+	repo         *repositories.ExportRepository
+	userRepo     *repositories.UserRepository
+	categoryRepo *repositories.CategoryRepository
+	workspaces   *WorkspaceService
+	userData     *repositories.UserDataRepository
+	dir          string
+	signingKey   []byte
+	retention    time.Duration
+	linkTTL      time.Duration
+	started      time.Time
+}
+
+func NewExportService(
+	repo *repositories.ExportRepository,
+	userRepo *repositories.UserRepository,
+	categoryRepo *repositories.CategoryRepository,
+	workspaces *WorkspaceService,
+	userData *repositories.UserDataRepository,
+	dir string,
+	signingKey string,
+	retention, linkTTL time.Duration,
+) *ExportService {
+	return &ExportService{
+		repo:         repo,
+		userRepo:     userRepo,
+		categoryRepo: categoryRepo,
+		workspaces:   workspaces,
+		userData:     userData,
+		dir:          dir,
+		signingKey:   []byte(signingKey),
+		retention:    retention,
+		linkTTL:      linkTTL,
+		started:      time.Now(),
+	}
+}
+
+// Request starts an export, or returns the one of the user still running.
+func (s *ExportService) Request(userID string) (*models.ExportJob, error) {
+	active, err := s.repo.FindActive(userID)
+	if err == nil {
+		return active, nil
+	}
+	if err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+
+	job := &models.ExportJob{
+		UserID:    userID,
+		Status:    models.ExportPending,
+		ExpiresAt: time.Now().Add(s.retention),
+	}
+	if err := s.repo.Create(job); err != nil {
+		return nil, err
+	}
+
+	go s.run(*job)
+	return job, nil
+}
+
+func (s *ExportService) GetExports(userID string) ([]models.ExportJob, error) {
+	jobs, err := s.repo.FindByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range jobs {
+		s.sign(&jobs[i])
+	}
+	return jobs, nil
+}
+
+// GetExport returns the job with a fresh download link once it is ready.
+func (s *ExportService) GetExport(id, userID string) (*models.ExportJob, error) {
+	job, err := s.repo.FindByID(id, userID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrExportNotFound
+		}
+		return nil, err
+	}
+	s.sign(job)
+	return job, nil
+}
+
+// Download checks a signed link and returns the ready job it points at.
+func (s *ExportService) Download(id, expires, signature string) (*models.ExportJob, error) {
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return nil, ErrInvalidExportLink
+	}
+	expected := s.signature(id, expiresAt)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return nil, ErrInvalidExportLink
+	}
+
+	job, err := s.repo.FindByID(id, "")
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrInvalidExportLink
+		}
+		return nil, err
+	}
+	if job.Status != models.ExportReady || time.Now().After(job.ExpiresAt) {
+		return nil, ErrInvalidExportLink
+	}
+	return job, nil
+}
+
+// CleanupExpired deletes the archives and jobs past their expiry and fails
+// the jobs a restart interrupted. It returns how many jobs it deleted.
+func (s *ExportService) CleanupExpired() (int, error) {
+	interrupted, err := s.repo.FindInterrupted(s.started)
+	if err != nil {
+		return 0, err
+	}
+	for i := range interrupted {
+		s.fail(&interrupted[i], errors.New("interrupted by a restart"))
+	}
+
+	expired, err := s.repo.FindExpired()
+	if err != nil {
+		return 0, err
+	}
+	return len(expired), s.remove(expired)
+}
+
+// DeleteForUser deletes every archive and job of the user.
+func (s *ExportService) DeleteForUser(userID string) error {
+	jobs, err := s.repo.FindByUser(userID)
+	if err != nil {
+		return err
+	}
+	return s.remove(jobs)
+}
+
+func (s *ExportService) remove(jobs []models.ExportJob) error {
+	for _, job := range jobs {
+		if job.Path != "" {
+			if err := os.Remove(job.Path); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+		if err := s.repo.Delete(job.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *ExportService) run(job models.ExportJob) {
+	job.Status = models.ExportRunning
+	if err := s.repo.Update(&job); err != nil {
+		s.fail(&job, err)
+		return
+	}
+
+	path, size, err := s.build(job.ID.Hex(), job.UserID)
+	if err != nil {
+		s.fail(&job, err)
+		return
+	}
+
+	now := time.Now()
+	job.Status = models.ExportReady
+	job.Path = path
+	job.Size = size
+	job.CompletedAt = &now
+	if err := s.repo.Update(&job); err != nil {
+		logger.Logger.Error("Failed to save export", zap.String("export_id", job.ID.Hex()), zap.Error(err))
+	}
+}
+
+func (s *ExportService) fail(job *models.ExportJob, cause error) {
+	logger.Logger.Error("Export failed", zap.String("export_id", job.ID.Hex()), zap.Error(cause))
+
+	now := time.Now()
+	job.Status = models.ExportFailed
+	job.Error = cause.Error()
+	job.CompletedAt = &now
+	if err := s.repo.Update(job); err != nil {
+		logger.Logger.Error("Failed to save export", zap.String("export_id", job.ID.Hex()), zap.Error(err))
+	}
+}
+
+// build writes the archive next to its final name and renames it when
+// complete, returning its path and size.
+func (s *ExportService) build(id, userID string) (string, int64, error) {
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return "", 0, err
+	}
+	path := filepath.Join(s.dir, id+".zip")
+	tmp := path + ".tmp"
+
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return "", 0, err
+	}
+	defer os.Remove(tmp)
+
+	if err := s.write(file, userID); err != nil {
+		file.Close()
+		return "", 0, err
+	}
+	if err := file.Close(); err != nil {
+		return "", 0, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return "", 0, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", 0, err
+	}
+	return path, info.Size(), nil
+}
+
+func (s *ExportService) write(w io.Writer, userID string) error {
+	archive := zip.NewWriter(w)
+	manifest := models.ExportManifest{UserID: userID, GeneratedAt: time.Now()}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	if err := writeJSON(archive, "profile.json", user); err != nil {
+		return err
+	}
+	manifest.Files = append(manifest.Files, models.ExportManifestFile{Name: "profile.json", Format: "json", Records: 1})
+
+	categories, err := s.categoryRepo.FindAll()
+	if err != nil {
+		return err
+	}
+	if err := writeJSON(archive, "categories.json", categories); err != nil {
+		return err
+	}
+	manifest.Files = append(manifest.Files, models.ExportManifestFile{Name: "categories.json", Format: "json", Records: len(categories)})
+
+	workspaces, err := s.workspaces.GetWorkspaces(userID)
+	if err != nil {
+		return err
+	}
+	workspaceIDs := make([]string, len(workspaces))
+	for i, workspace := range workspaces {
+		workspaceIDs[i] = workspace.ID.Hex()
+	}
+	if err := writeJSON(archive, "workspaces.json", workspaces); err != nil {
+		return err
+	}
+	manifest.Files = append(manifest.Files, models.ExportManifestFile{Name: "workspaces.json", Format: "json", Records: len(workspaces)})
+
+	for _, collection := range repositories.WorkspaceCollections {
+		documents, err := s.userData.FindInWorkspaces(collection, workspaceIDs)
+		if err != nil {
+			return err
+		}
+		if err := writeCollection(archive, &manifest, collection, documents); err != nil {
+			return err
+		}
+	}
+	for _, collection := range repositories.OwnedCollections {
+		if exportExcluded[collection] {
+			continue
+		}
+
+		documents, err := s.userData.FindAll(collection, userID)
+		if err != nil {
+			return err
+		}
+		if err := writeCollection(archive, &manifest, collection, documents); err != nil {
+			return err
+		}
+	}
+
+	if err := writeJSON(archive, "manifest.json", manifest); err != nil {
+		return err
+	}
+	return archive.Close()
+}
+
+// writeCollection adds the documents of a collection to the archive as JSON
+// and CSV.
+func writeCollection(archive *zip.Writer, manifest *models.ExportManifest, collection string, documents []bson.M) error {
+	records := make([]map[string]interface{}, len(documents))
+	for i, document := range documents {
+		records[i] = plainDocument(document)
+	}
+
+	if err := writeJSON(archive, collection+".json", records); err != nil {
+		return err
+	}
+	if err := writeCSV(archive, collection+".csv", records); err != nil {
+		return err
+	}
+	manifest.Files = append(manifest.Files,
+		models.ExportManifestFile{Name: collection + ".json", Format: "json", Records: len(records)},
+		models.ExportManifestFile{Name: collection + ".csv", Format: "csv", Records: len(records)},
+	)
+	return nil
+}
+
+// sign fills in the download link of a ready job. The link expires after
+// linkTTL, or with the archive if that comes first.
+func (s *ExportService) sign(job *models.ExportJob) {
+	if job.Status != models.ExportReady {
+		return
+	}
+
+	expiresAt := time.Now().Add(s.linkTTL)
+	if job.ExpiresAt.Before(expiresAt) {
+		expiresAt = job.ExpiresAt
+	}
+	id := job.ID.Hex()
+	job.DownloadURL = fmt.Sprintf("/api/exports/%s/download?expires=%d&signature=%s", id, expiresAt.Unix(), s.signature(id, expiresAt.Unix()))
+	job.LinkExpiresAt = &expiresAt
+}
+
+func (s *ExportService) signature(id string, expiresAt int64) string {
+	mac := hmac.New(sha256.New, s.signingKey)
+	fmt.Fprintf(mac, "%s.%d", id, expiresAt)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func writeJSON(archive *zip.Writer, name string, value interface{}) error {
+	w, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+// writeCSV writes one row per record with a column per field, _id first and
+// the rest sorted. Nested values are written as JSON.
+func writeCSV(archive *zip.Writer, name string, records []map[string]interface{}) error {
+	w, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+
+	seen := map[string]bool{}
+	columns := []string{}
+	for _, record := range records {
+		for key := range record {
+			if !seen[key] && key != "_id" {
+				seen[key] = true
+				columns = append(columns, key)
+			}
+		}
+	}
+	sort.Strings(columns)
+	columns = append([]string{"_id"}, columns...)
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(columns); err != nil {
+		return err
+	}
+	for _, record := range records {
+		row := make([]string, len(columns))
+		for i, column := range columns {
+			row[i] = csvValue(record[column])
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func csvValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case map[string]interface{}, []interface{}:
+		encoded, _ := json.Marshal(v)
+		return string(encoded)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// plainDocument converts the BSON types of a document to plain Go values that
+// encode cleanly as JSON.
+func plainDocument(document bson.M) map[string]interface{} {
+	plain := make(map[string]interface{}, len(document))
+	for key, value := range document {
+		plain[key] = plainValue(value)
+	}
+	return plain
+}
+
+func plainValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case bson.M:
+		return plainDocument(v)
+	case bson.D:
+		return plainDocument(v.Map())
+	case bson.A:
+		values := make([]interface{}, len(v))
+		for i, item := range v {
+			values[i] = plainValue(item)
+		}
+		return values
+	case primitive.ObjectID:
+		return v.Hex()
+	case primitive.DateTime:
+		return v.Time().UTC()
+	case primitive.Decimal128:
+		return v.String()
+	default:
+		return v
+	}
+}
@@ -0,0 +1,235 @@
+package services
+
+import (
+	"errors"
+	"financial-api/internal/finance"
+	"financial-api/internal/models"
+	"financial-api/internal/repositories"
+	"fmt"
+	"sort"
+	"time"
+)
+
+var ErrInvalidForecast = errors.New("months must be between 1 and 24 and interval daily or monthly")
+
+// trendMonths is how many of the latest complete months the spending trend
+// averages.
+const trendMonths = 12
+
+type ForecastService struct {
+	transactionRepo   *repositories.TransactionRepository
+	aggregationRepo   *repositories.AggregationRepository
+	recurringService  *RecurringService
+	loanService       *LoanService
+	investmentService *InvestmentService
+}
+
+func NewForecastService(
+	transactionRepo *repositories.TransactionRepository,
+	aggregationRepo *repositories.AggregationRepository,
+	recurringService *RecurringService,
+	loanService *LoanService,
+	investmentService *InvestmentService,
+) *ForecastService {
+	return &ForecastService{
+		transactionRepo:   transactionRepo,
+		aggregationRepo:   aggregationRepo,
+		recurringService:  recurringService,
+		loanService:       loanService,
+		investmentService: investmentService,
+	}
+}
+
+// GetForecast projects the balance over the next months from today's balance,
+// the recurring items, the loan installments not posted yet and the fixed
+// income maturities. With trend on, the average variable spending of the past
+// months is spread over each day as well.
+func (s *ForecastService) GetForecast(workspaceID string, months int, interval string, trend bool) (*models.ForecastReport, error) {
+	if months < 1 || months > 24 || (interval != IntervalDaily && interval != IntervalMonthly) {
+		return nil, ErrInvalidForecast
+	}
+
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	from := today.AddDate(0, 0, 1)
+	to := finance.AddMonths(today, months)
+
+	totals, err := s.transactionRepo.GetTotals(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	events, recurringExpenses, err := s.scheduledEvents(workspaceID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.ForecastReport{
+		StartDate:    from.Format(finance.DateLayout),
+		EndDate:      to.Format(finance.DateLayout),
+		Interval:     interval,
+		StartBalance: totals.Balance,
+		Events:       events,
+	}
+
+	if trend {
+		report.TrendMonthly, err = s.variableSpending(workspaceID, today, recurringExpenses)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	flows := map[string]float64{}
+	for _, event := range events {
+		flows[event.Date] += event.Amount
+	}
+
+	balance := totals.Balance
+	report.LowestBalance = balance
+	report.LowestBalanceDate = today.Format(finance.DateLayout)
+	report.Points = []models.ForecastPoint{}
+
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		date := day.Format(finance.DateLayout)
+		flow := flows[date]
+		var spending float64
+		if report.TrendMonthly > 0 {
+			spending = report.TrendMonthly / float64(finance.AddMonths(monthStart(day), 1).AddDate(0, 0, -1).Day())
+		}
+		balance += flow - spending
+
+		if balance < report.LowestBalance {
+			report.LowestBalance = balance
+			report.LowestBalanceDate = date
+		}
+		if balance < 0 && report.FirstNegativeDate == nil {
+			report.FirstNegativeDate = &date
+		}
+
+		pointDate := date
+		if interval == IntervalMonthly {
+			pointDate = date[:7]
+		}
+		if n := len(report.Points); n == 0 || report.Points[n-1].Date != pointDate {
+			report.Points = append(report.Points, models.ForecastPoint{Date: pointDate})
+		}
+		point := &report.Points[len(report.Points)-1]
+		if flow > 0 {
+			point.Inflows += flow
+		} else {
+			point.Outflows -= flow
+		}
+		point.Outflows += spending
+		point.Balance = balance
+	}
+
+	report.EndBalance = balance
+	return report, nil
+}
+
+// scheduledEvents lists the known flows in [from, to], sorted by date, and the
+// monthly equivalent of the recurring expenses.
+func (s *ForecastService) scheduledEvents(workspaceID string, from, to time.Time) ([]models.ForecastEvent, float64, error) {
+	events := []models.ForecastEvent{}
+	var recurringExpenses float64
+
+	items, err := s.recurringService.GetItems(workspaceID)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range items {
+		item := &items[i]
+		amount := item.Amount
+		if item.Type == "expense" {
+			amount = -amount
+			recurringExpenses += monthlyEquivalent(item)
+		}
+		for _, date := range Occurrences(item, from, to) {
+			events = append(events, models.ForecastEvent{
+				Date:        date.Format(finance.DateLayout),
+				Source:      models.ForecastRecurring,
+				Description: item.Description,
+				Amount:      amount,
+			})
+		}
+	}
+
+	loans, err := s.loanService.GetLoans(workspaceID)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range loans {
+		schedule, err := Schedule(&loans[i])
+		if err != nil {
+			continue
+		}
+		for _, installment := range schedule.Installments {
+			if installment.Posted || installment.Date < from.Format(finance.DateLayout) || installment.Date > to.Format(finance.DateLayout) {
+				continue
+			}
+			events = append(events, models.ForecastEvent{
+				Date:        installment.Date,
+				Source:      models.ForecastLoan,
+				Description: fmt.Sprintf("%s %d/%d", loans[i].Name, installment.Number, schedule.Term),
+				Amount:      -(installment.Payment + installment.Extra),
+			})
+		}
+	}
+
+	liquidity, err := s.investmentService.GetLiquidity(workspaceID, int(to.Sub(from).Hours()/24)+1)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, maturity := range liquidity.Maturities {
+		if maturity.MaturityDate < from.Format(finance.DateLayout) {
+			continue
+		}
+		events = append(events, models.ForecastEvent{
+			Date:        maturity.MaturityDate,
+			Source:      models.ForecastMaturity,
+			Description: maturity.Name,
+			Amount:      maturity.ProjectedNetValue,
+		})
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Date < events[j].Date
+	})
+
+	return events, recurringExpenses, nil
+}
+
+// variableSpending estimates the monthly spending not covered by recurring
+// items as the average expenses of the latest complete months minus the
+// recurring expenses.
+func (s *ForecastService) variableSpending(workspaceID string, today time.Time, recurringExpenses float64) (float64, error) {
+	before := monthStart(today).Format(finance.DateLayout)
+	monthly, err := s.aggregationRepo.GetRecentMonthlyExpenses(workspaceID, before, trendMonths)
+	if err != nil {
+		return 0, err
+	}
+	if len(monthly) == 0 {
+		return 0, nil
+	}
+
+	var total float64
+	for _, expenses := range monthly {
+		total += expenses
+	}
+
+	if spending := total/float64(len(monthly)) - recurringExpenses; spending > 0 {
+		return spending, nil
+	}
+	return 0, nil
+}
+
+func monthlyEquivalent(item *models.RecurringItem) float64 {
+	switch item.Frequency {
+	case models.FrequencyWeekly:
+		return item.Amount * 52 / 12
+	case models.FrequencyYearly:
+		return item.Amount / 12
+	default:
+		return item.Amount
+	}
+}
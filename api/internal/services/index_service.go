@@ -0,0 +1,143 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"financial-api/internal/finance"
+	"financial-api/internal/models"
+	"financial-api/internal/repositories"
+)
+
+var supportedIndexes = map[string]bool{
+	models.IndexCDI:   true,
+	models.IndexSelic: true,
+	models.IndexIPCA:  true,
+}
+
+type IndexService struct {
+	repo *repositories.IndexRepository
+}
+
+func NewIndexService(repo *repositories.IndexRepository) *IndexService {
+	return &IndexService{repo: repo}
+}
+
+// ImportCSV loads index values from CSV. Rows are "index,date,value", or
+// "date,value" when index is given (the layout exported by the BCB). Both ","
+// and ";" separators, decimal commas and dd/mm/yyyy dates are accepted.
+func (s *IndexService) ImportCSV(r io.Reader, index string) (*models.ImportResult, error) {
+	index = strings.ToUpper(strings.TrimSpace(index))
+	if index != "" && !supportedIndexes[index] {
+		return nil, fmt.Errorf("unsupported index %q", index)
+	}
+
+	records, err := readCSVRecords(r)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.ImportResult{}
+	values := []models.IndexValue{}
+	for i, record := range records {
+		value, err := parseIndexRecord(record, index)
+		if err != nil {
+			// A header row is expected, anything else is reported
+			if i > 0 {
+				result.Errors = append(result.Errors, fmt.Sprintf("line %d: %v", i+1, err))
+			}
+			result.Skipped++
+			continue
+		}
+		values = append(values, *value)
+	}
+
+	if err := s.repo.Upsert(values); err != nil {
+		return nil, err
+	}
+
+	result.Imported = len(values)
+	return result, nil
+}
+
+// ImportDir imports every CSV file in dir. A file named after an index (cdi.csv,
+// ipca.csv) holds only that index; other files carry the index on each row.
+func (s *IndexService) ImportDir(dir string) (*models.ImportResult, error) {
+	files, err := csvFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	total := &models.ImportResult{}
+	for _, path := range files {
+		index := fileStem(path)
+		if !supportedIndexes[index] {
+			index = ""
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		result, err := s.ImportCSV(file, index)
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+		}
+
+		total.Imported += result.Imported
+		total.Skipped += result.Skipped
+		for _, e := range result.Errors {
+			total.Errors = append(total.Errors, filepath.Base(path)+": "+e)
+		}
+	}
+
+	return total, nil
+}
+
+func (s *IndexService) GetValues(index, from, to string) ([]models.IndexValue, error) {
+	index = strings.ToUpper(index)
+	if !supportedIndexes[index] {
+		return nil, fmt.Errorf("unsupported index %q", index)
+	}
+	return s.repo.FindRange(index, from, to)
+}
+
+func parseIndexRecord(record []string, index string) (*models.IndexValue, error) {
+	if index == "" {
+		if len(record) < 3 {
+			return nil, fmt.Errorf("expected index, date and value")
+		}
+		index = strings.ToUpper(strings.TrimSpace(record[0]))
+		record = record[1:]
+		if !supportedIndexes[index] {
+			return nil, fmt.Errorf("unsupported index %q", index)
+		}
+	}
+	if len(record) < 2 {
+		return nil, fmt.Errorf("expected date and value")
+	}
+
+	date, err := parseImportDate(strings.TrimSpace(record[0]))
+	if err != nil {
+		return nil, err
+	}
+	if models.IsMonthlyIndex(index) {
+		date = time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	value, err := parseDecimal(record[1])
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.IndexValue{
+		Index: index,
+		Date:  date.Format(finance.DateLayout),
+		Value: value,
+	}, nil
+}
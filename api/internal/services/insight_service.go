@@ -0,0 +1,368 @@
+package services
+
+import (
+	"errors"
+	"financial-api/internal/finance"
+	"financial-api/internal/models"
+	"financial-api/internal/repositories"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// Baselines look at the expenses of the trailing months
+	baselineMonths = 6
+	// Fewer past transactions, or months, than this are not a baseline
+	minBaselineSamples = 5
+	minBaselineMonths  = 3
+	// Robust z-score above which an amount is unusual
+	anomalyThreshold = 3.5
+	// The sweep re-checks the transactions of the last days against the
+	// baselines built since they were created
+	sweepRecheckDays = 30
+)
+
+var ErrInsightNotFound = errors.New("insight not found")
+
+type InsightService struct {
+	repo            *repositories.InsightRepository
+	transactionRepo *repositories.TransactionRepository
+	categoryRepo    *repositories.CategoryRepository
+	workspaceRepo   *repositories.WorkspaceRepository
+}
+
+func NewInsightService(
+	repo *repositories.InsightRepository,
+	transactionRepo *repositories.TransactionRepository,
+	categoryRepo *repositories.CategoryRepository,
+	workspaceRepo *repositories.WorkspaceRepository,
+) *InsightService {
+	return &InsightService{
+		repo:            repo,
+		transactionRepo: transactionRepo,
+		categoryRepo:    categoryRepo,
+		workspaceRepo:   workspaceRepo,
+	}
+}
+
+func (s *InsightService) GetInsights(workspaceID string, includeDismissed bool, limit int) ([]models.Insight, error) {
+	return s.repo.FindByWorkspace(workspaceID, includeDismissed, limit)
+}
+
+func (s *InsightService) Dismiss(id, workspaceID, role string) error {
+	if err := requireRole(role, models.RoleEditor); err != nil {
+		return err
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrInsightNotFound
+	}
+
+	found, err := s.repo.Dismiss(objectID, workspaceID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrInsightNotFound
+	}
+	return nil
+}
+
+// CheckTransaction flags an expense that is unusually large for its category
+// or merchant compared with the trailing months, and adds it to the feed.
+func (s *InsightService) CheckTransaction(transaction *models.Transaction, workspaceID string) error {
+	if transaction.Type != "expense" {
+		return nil
+	}
+	date, err := finance.ParseDate(transaction.Date)
+	if err != nil {
+		return nil
+	}
+
+	history, err := s.transactionRepo.FindExpenses(workspaceID, finance.AddMonths(date, -baselineMonths).Format(finance.DateLayout), transaction.Date)
+	if err != nil {
+		return err
+	}
+	names, err := s.categoryNames()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.applyCheck(transaction, history, names, workspaceID)
+	return err
+}
+
+// Sweep sweeps the workspace on behalf of a member with the given role.
+func (s *InsightService) Sweep(workspaceID, role string) (*models.SweepResult, error) {
+	if err := requireRole(role, models.RoleEditor); err != nil {
+		return nil, err
+	}
+	return s.sweep(workspaceID)
+}
+
+// sweep re-checks the recent expenses of the workspace and looks for categories
+// whose spending this month or last month spikes above their baseline.
+func (s *InsightService) sweep(workspaceID string) (*models.SweepResult, error) {
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	from := finance.AddMonths(monthStart(today), -(baselineMonths + 1))
+
+	expenses, err := s.transactionRepo.FindExpenses(workspaceID, from.Format(finance.DateLayout), today.Format(finance.DateLayout))
+	if err != nil {
+		return nil, err
+	}
+	names, err := s.categoryNames()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.SweepResult{}
+	recheckFrom := today.AddDate(0, 0, -sweepRecheckDays).Format(finance.DateLayout)
+	for i := range expenses {
+		if expenses[i].Date < recheckFrom {
+			continue
+		}
+		result.Transactions++
+		flagged, err := s.applyCheck(&expenses[i], expenses, names, workspaceID)
+		if err != nil {
+			return nil, err
+		}
+		if flagged {
+			result.Flagged++
+		}
+	}
+
+	spikes := categorySpikes(expenses, names, today)
+	for i := range spikes {
+		if err := s.repo.Upsert(&spikes[i], workspaceID); err != nil {
+			return nil, err
+		}
+	}
+	result.Spikes = len(spikes)
+
+	return result, nil
+}
+
+// SweepAll sweeps every workspace. A failure for one workspace does not stop the
+// others; the number of workspaces swept and the last error are returned.
+func (s *InsightService) SweepAll() (int, error) {
+	workspaceIDs, err := s.workspaceRepo.FindAllIDs()
+	if err != nil {
+		return 0, err
+	}
+
+	swept := 0
+	var lastErr error
+	for _, workspaceID := range workspaceIDs {
+		if _, err := s.sweep(workspaceID); err != nil {
+			lastErr = err
+			continue
+		}
+		swept++
+	}
+
+	return swept, lastErr
+}
+
+// applyCheck scores the transaction against the history, stores its flags
+// when they changed and records an insight when it is flagged.
+func (s *InsightService) applyCheck(transaction *models.Transaction, history []models.Transaction, names map[string]string, workspaceID string) (bool, error) {
+	flags, insight := checkTransaction(transaction, history, names)
+
+	if !sameFlags(transaction.Flags, flags) {
+		if err := s.transactionRepo.SetFlags(transaction.ID, flags); err != nil {
+			return false, err
+		}
+	}
+	transaction.Flags = flags
+
+	if insight == nil {
+		return false, nil
+	}
+	return true, s.repo.Upsert(insight, workspaceID)
+}
+
+func (s *InsightService) categoryNames() (map[string]string, error) {
+	categories, err := s.categoryRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[string]string{}
+	for _, category := range categories {
+		names[category.ID.Hex()] = category.Name
+	}
+	return names, nil
+}
+
+// checkTransaction compares an expense with the earlier expenses of the
+// baseline window in the same category and from the same merchant.
+func checkTransaction(transaction *models.Transaction, history []models.Transaction, names map[string]string) ([]string, *models.Insight) {
+	date, err := finance.ParseDate(transaction.Date)
+	if err != nil {
+		return []string{}, nil
+	}
+	windowStart := finance.AddMonths(date, -baselineMonths).Format(finance.DateLayout)
+
+	category := categoryKey(transaction)
+	merchant := transactionMerchant(transaction)
+
+	var byCategory, byMerchant []float64
+	for i := range history {
+		past := &history[i]
+		if past.ID == transaction.ID || past.Date > transaction.Date || past.Date < windowStart {
+			continue
+		}
+		if categoryKey(past) == category {
+			byCategory = append(byCategory, past.Amount)
+		}
+		if merchant != "" && strings.EqualFold(transactionMerchant(past), merchant) {
+			byMerchant = append(byMerchant, past.Amount)
+		}
+	}
+
+	flags := []string{}
+	var insight *models.Insight
+	for _, check := range []struct {
+		flag   string
+		sample []float64
+	}{
+		{models.FlagUnusualForCategory, byCategory},
+		{models.FlagUnusualForMerchant, byMerchant},
+	} {
+		if len(check.sample) < minBaselineSamples {
+			continue
+		}
+		score, ok := finance.RobustZScore(transaction.Amount, check.sample)
+		if !ok || score <= anomalyThreshold {
+			continue
+		}
+		flags = append(flags, check.flag)
+
+		if insight == nil || score > insight.Score {
+			baseline := finance.Median(check.sample)
+			against := categoryName(category, names)
+			if check.flag == models.FlagUnusualForMerchant {
+				against = merchant
+			}
+
+			id := transaction.ID.Hex()
+			insight = &models.Insight{
+				Key:           "transaction:" + id,
+				Kind:          models.InsightLargeTransaction,
+				Message:       fmt.Sprintf("%s: %.2f is unusually large for %s, usually around %.2f", transaction.Description, transaction.Amount, against, baseline),
+				Date:          transaction.Date,
+				TransactionID: &id,
+				Category:      categoryName(category, names),
+				Merchant:      merchant,
+				Amount:        transaction.Amount,
+				Baseline:      baseline,
+				Score:         score,
+			}
+		}
+	}
+
+	return flags, insight
+}
+
+// categorySpikes compares the spending of each category this month and last
+// month with its monthly totals over the months before.
+func categorySpikes(expenses []models.Transaction, names map[string]string, today time.Time) []models.Insight {
+	totals := map[string]map[string]float64{}
+	firstMonth := ""
+	for _, expense := range expenses {
+		month := expense.Date[:7]
+		if firstMonth == "" || month < firstMonth {
+			firstMonth = month
+		}
+		category := categoryKey(&expense)
+		if totals[category] == nil {
+			totals[category] = map[string]float64{}
+		}
+		totals[category][month] += expense.Amount
+	}
+
+	categories := make([]string, 0, len(totals))
+	for category := range totals {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	spikes := []models.Insight{}
+	current := monthStart(today)
+	for _, month := range []time.Time{current, current.AddDate(0, -1, 0)} {
+		key := month.Format("2006-01")
+		for _, category := range categories {
+			total := totals[category][key]
+			if total <= 0 {
+				continue
+			}
+
+			// Months the user already had expenses count, even with nothing in
+			// this category
+			sample := []float64{}
+			for n := 1; n <= baselineMonths; n++ {
+				past := month.AddDate(0, -n, 0).Format("2006-01")
+				if past < firstMonth {
+					break
+				}
+				sample = append(sample, totals[category][past])
+			}
+			if len(sample) < minBaselineMonths {
+				continue
+			}
+
+			score, ok := finance.RobustZScore(total, sample)
+			if !ok || score <= anomalyThreshold {
+				continue
+			}
+
+			name := categoryName(category, names)
+			baseline := finance.Median(sample)
+			spikes = append(spikes, models.Insight{
+				Key:      "category:" + category + ":" + key,
+				Kind:     models.InsightCategorySpike,
+				Message:  fmt.Sprintf("Spending on %s in %s is %.2f, usually around %.2f a month", name, key, total, baseline),
+				Date:     month.Format(finance.DateLayout),
+				Category: name,
+				Month:    key,
+				Amount:   total,
+				Baseline: baseline,
+				Score:    score,
+			})
+		}
+	}
+
+	return spikes
+}
+
+func categoryKey(transaction *models.Transaction) string {
+	if transaction.CategoryID == nil {
+		return ""
+	}
+	return *transaction.CategoryID
+}
+
+func categoryName(key string, names map[string]string) string {
+	if name, ok := names[key]; ok {
+		return name
+	}
+	return "Outros"
+}
+
+func sameFlags(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -1,29 +1,91 @@
 package services
 
 import (
+	"errors"
+	"financial-api/internal/finance"
 	"financial-api/internal/models"
 	"financial-api/internal/repositories"
 	"math"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrInvestmentNotFound  = errors.New("investment not found")
+	ErrInsufficientBalance = errors.New("movement exceeds current position")
+	ErrInvalidDate         = errors.New("invalid date, expected YYYY-MM-DD")
+	ErrInvalidMaturity     = errors.New("maturity date must be a YYYY-MM-DD date after the investment date")
 )
 
 type InvestmentService struct {
-	repo *repositories.InvestmentRepository
+	repo         *repositories.InvestmentRepository
+	movementRepo *repositories.MovementRepository
+	indexRepo    *repositories.IndexRepository
 }
 
-func NewInvestmentService(repo *repositories.InvestmentRepository) *InvestmentService {
-	return &InvestmentService{repo: repo}
+func NewInvestmentService(
+	repo *repositories.InvestmentRepository,
+	movementRepo *repositories.MovementRepository,
+	indexRepo *repositories.IndexRepository,
+) *InvestmentService {
+	return &InvestmentService{repo: repo, movementRepo: movementRepo, indexRepo: indexRepo}
 }
 
-func (s *InvestmentService) CreateInvestment(investment *models.Investment, userID string) error {
-	return s.repo.Create(investment, userID)
+func (s *InvestmentService) CreateInvestment(investment *models.Investment, workspaceID, role string) error {
+	if err := requireRole(role, models.RoleEditor); err != nil {
+		return err
+	}
+
+	start, err := finance.ParseDate(investment.Date)
+	if err != nil {
+		return ErrInvalidDate
+	}
+	if investment.Indexer != nil && investment.IndexPercent == 0 && investment.Spread == 0 {
+		investment.IndexPercent = 100
+	}
+	if investment.MaturityDate != nil {
+		if maturity, err := finance.ParseDate(*investment.MaturityDate); err != nil || !maturity.After(start) {
+			return ErrInvalidMaturity
+		}
+	}
+	investment.Liquidity = investmentLiquidity(investment)
+
+	if err := s.repo.Create(investment, workspaceID); err != nil {
+		return err
+	}
+
+	// The initial amount is the first contribution of the ledger
+	initial := &models.InvestmentMovement{
+		InvestmentID: investment.ID,
+		Type:         models.MovementContribution,
+		Amount:       investment.Amount,
+		Date:         investment.Date,
+	}
+	if err := s.movementRepo.Create(initial, workspaceID); err != nil {
+		return err
+	}
+
+	investment.Position = investment.Amount
+	return nil
 }
 
-func (s *InvestmentService) GetInvestmentsPaginated(page, limit int, search, userID string) (*models.PaginatedResponse, error) {
-	investments, total, err := s.repo.FindPaginated(page, limit, search, userID)
+func (s *InvestmentService) GetInvestmentsPaginated(page, limit int, search, workspaceID string) (*models.PaginatedResponse, error) {
+	investments, total, err := s.repo.FindPaginated(page, limit, search, workspaceID)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := s.fillPositions(investments, workspaceID); err != nil {
+		return nil, err
+	}
+
+	if err := s.fillValuations(investments, workspaceID, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+
 	totalPages := int(math.Ceil(float64(total) / float64(limit)))
 
 	return &models.PaginatedResponse{
@@ -36,3 +98,637 @@ func (s *InvestmentService) GetInvestmentsPaginated(page, limit int, search, use
 		},
 	}, nil
 }
+
+func (s *InvestmentService) GetInvestment(id, workspaceID string) (*models.Investment, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvestmentNotFound
+	}
+
+	investment, err := s.repo.FindByID(objectID, workspaceID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrInvestmentNotFound
+		}
+		return nil, err
+	}
+
+	return investment, nil
+}
+
+func (s *InvestmentService) GetLedger(investmentID, workspaceID string) (*models.InvestmentLedger, error) {
+	investment, err := s.GetInvestment(investmentID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	movements, err := s.movementRepo.FindByInvestment(investment.ID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	position := investment.Amount
+	if len(movements) > 0 {
+		position = 0
+		for i := range movements {
+			position += movements[i].SignedAmount()
+		}
+	}
+	investment.Position = position
+
+	return &models.InvestmentLedger{
+		Investment: *investment,
+		Movements:  movements,
+		Position:   position,
+	}, nil
+}
+
+func (s *InvestmentService) AddMovement(investmentID string, movement *models.InvestmentMovement, workspaceID, role string) (*models.InvestmentLedger, error) {
+	if err := requireRole(role, models.RoleEditor); err != nil {
+		return nil, err
+	}
+
+	ledger, err := s.GetLedger(investmentID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Legacy investments have no ledger yet: seed it with the original amount
+	seed := len(ledger.Movements) == 0
+	if seed {
+		ledger.Movements = []models.InvestmentMovement{{
+			InvestmentID: ledger.Investment.ID,
+			Type:         models.MovementContribution,
+			Amount:       ledger.Investment.Amount,
+			Date:         ledger.Investment.Date,
+		}}
+	}
+
+	// A backdated withdrawal must leave every later withdrawal covered too, so
+	// the whole ledger is replayed with the movement in place
+	movement.InvestmentID = ledger.Investment.ID
+	at := sort.Search(len(ledger.Movements), func(i int) bool { return ledger.Movements[i].Date > movement.Date })
+	replayed := make([]models.InvestmentMovement, 0, len(ledger.Movements)+1)
+	replayed = append(replayed, ledger.Movements[:at]...)
+	replayed = append(replayed, *movement)
+	replayed = append(replayed, ledger.Movements[at:]...)
+	if overdrawn(replayed) {
+		return nil, ErrInsufficientBalance
+	}
+
+	if seed {
+		// The seed is the only other movement, on whichever side it landed
+		initial := &replayed[0]
+		if at == 0 {
+			initial = &replayed[1]
+		}
+		if err := s.movementRepo.Create(initial, workspaceID); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.movementRepo.Create(&replayed[at], workspaceID); err != nil {
+		return nil, err
+	}
+	*movement = replayed[at]
+
+	ledger.Movements = replayed
+	ledger.Position += movement.SignedAmount()
+	ledger.Investment.Position = ledger.Position
+	return ledger, nil
+}
+
+// overdrawn reports whether movements, in date order, ever withdraw more than
+// the balance at that point.
+func overdrawn(movements []models.InvestmentMovement) bool {
+	var balance float64
+	for _, movement := range movements {
+		balance += movement.SignedAmount()
+		if balance < 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *InvestmentService) fillPositions(investments []models.Investment, workspaceID string) error {
+	if len(investments) == 0 {
+		return nil
+	}
+
+	ids := make([]primitive.ObjectID, len(investments))
+	for i := range investments {
+		ids[i] = investments[i].ID
+	}
+
+	positions, err := s.movementRepo.GetPositions(ids, workspaceID)
+	if err != nil {
+		return err
+	}
+
+	for i := range investments {
+		if position, ok := positions[investments[i].ID]; ok {
+			investments[i].Position = position
+		} else {
+			investments[i].Position = investments[i].Amount
+		}
+	}
+	return nil
+}
+
+// GetValuation computes the gross value of an investment on a date by accruing
+// each capital movement of its ledger from the day it happened.
+func (s *InvestmentService) GetValuation(investmentID, date, workspaceID string) (*models.InvestmentValuation, error) {
+	asOf := time.Now().UTC()
+	if date != "" {
+		parsed, err := finance.ParseDate(date)
+		if err != nil {
+			return nil, ErrInvalidDate
+		}
+		asOf = parsed
+	}
+
+	ledger, err := s.GetLedger(investmentID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.valuate(&ledger.Investment, ledger.Movements, asOf)
+}
+
+func (s *InvestmentService) valuate(investment *models.Investment, movements []models.InvestmentMovement, asOf time.Time) (*models.InvestmentValuation, error) {
+	start, err := finance.ParseDate(investment.Date)
+	if err != nil {
+		return nil, ErrInvalidDate
+	}
+
+	var values []models.IndexValue
+	if investment.Indexer != nil {
+		values, err = s.indexRepo.FindRange(*investment.Indexer, monthStart(start).Format(finance.DateLayout), asOf.Format(finance.DateLayout))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return valuateLots(investment, movements, values, asOf)
+}
+
+// lot is a contribution still held, identified by the day it was applied.
+type lot struct {
+	date      time.Time
+	principal float64
+}
+
+// valuateLots accrues every contribution still held from the day it was
+// applied and taxes its earnings by its own holding period. Outflows consume
+// the oldest lots first.
+func valuateLots(investment *models.Investment, movements []models.InvestmentMovement, values []models.IndexValue, asOf time.Time) (*models.InvestmentValuation, error) {
+	start, err := finance.ParseDate(investment.Date)
+	if err != nil {
+		return nil, ErrInvalidDate
+	}
+
+	lots := heldLots(investment, movements, values, asOf)
+
+	investmentType := ""
+	if investment.Type != nil {
+		investmentType = *investment.Type
+	}
+
+	valuation := &models.InvestmentValuation{
+		InvestmentID: investment.ID.Hex(),
+		Date:         asOf.Format(finance.DateLayout),
+		BusinessDays: finance.BusinessDaysBetween(start, asOf),
+		Factor:       accrualFactor(investment, values, start, asOf),
+	}
+	if len(values) > 0 {
+		valuation.LastIndexDate = values[len(values)-1].Date
+	}
+
+	for _, l := range lots {
+		if l.principal <= 0 {
+			continue
+		}
+
+		gross := l.principal * accrualFactor(investment, values, l.date, asOf)
+		days := int(asOf.Sub(l.date).Hours() / 24)
+		taxes := finance.RedemptionTax(gross-l.principal, days, investmentType)
+
+		valuation.Invested += l.principal
+		valuation.GrossValue += gross
+		valuation.IOF += taxes.IOF
+		valuation.IncomeTax += taxes.IncomeTax
+	}
+
+	valuation.Earnings = valuation.GrossValue - valuation.Invested
+	valuation.TaxDue = valuation.IOF + valuation.IncomeTax
+	valuation.NetValue = valuation.GrossValue - valuation.TaxDue
+	return valuation, nil
+}
+
+// heldLots replays the ledger up to the given date and returns the
+// contributions still held. An outflow is taken from the gross value of the
+// oldest lots, so it reduces their principal only by the redeemed fraction.
+func heldLots(investment *models.Investment, movements []models.InvestmentMovement, values []models.IndexValue, asOf time.Time) []lot {
+	// Legacy investments have no ledger: their amount is the only contribution
+	if len(movements) == 0 {
+		movements = []models.InvestmentMovement{{
+			Type:   models.MovementContribution,
+			Amount: investment.Amount,
+			Date:   investment.Date,
+		}}
+	}
+
+	lots := []lot{}
+	for i := range movements {
+		movement := &movements[i]
+		date, err := finance.ParseDate(movement.Date)
+		if err != nil || date.After(asOf) {
+			continue
+		}
+
+		switch {
+		case movement.Type == models.MovementContribution:
+			lots = append(lots, lot{date: date, principal: movement.Amount})
+		case movement.SignedAmount() < 0:
+			remaining := movement.Amount
+			for j := range lots {
+				if remaining <= 0 {
+					break
+				}
+				if lots[j].principal <= 0 {
+					continue
+				}
+				gross := lots[j].principal * accrualFactor(investment, values, lots[j].date, date)
+				if gross <= 0 {
+					continue
+				}
+				taken := math.Min(gross, remaining)
+				lots[j].principal -= lots[j].principal * taken / gross
+				remaining -= taken
+			}
+		}
+		// Credited income is left out: the accrual already models the yield
+	}
+
+	return lots
+}
+
+// GetValuationTotals returns the gross value, the tax due and the net value of
+// every investment of the workspace as of today.
+func (s *InvestmentService) GetValuationTotals(workspaceID string) (float64, float64, float64, error) {
+	investments, err := s.repo.FindAll(workspaceID)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	if err := s.fillValuations(investments, workspaceID, time.Now().UTC()); err != nil {
+		return 0, 0, 0, err
+	}
+
+	var gross, tax, net float64
+	for i := range investments {
+		if investments[i].Valuation == nil {
+			continue
+		}
+		gross += investments[i].Valuation.GrossValue
+		tax += investments[i].Valuation.TaxDue
+		net += investments[i].Valuation.NetValue
+	}
+
+	return gross, tax, net, nil
+}
+
+// valuationData holds what valuating a set of investments needs, loaded once.
+type valuationData struct {
+	movements map[primitive.ObjectID][]models.InvestmentMovement
+	values    map[string][]models.IndexValue
+}
+
+func (d *valuationData) valuate(investment *models.Investment, asOf time.Time) (*models.InvestmentValuation, error) {
+	var indexValues []models.IndexValue
+	if investment.Indexer != nil {
+		indexValues = d.values[*investment.Indexer]
+	}
+	return valuateLots(investment, d.movements[investment.ID], indexValues, asOf)
+}
+
+// loadValuationData loads the ledgers of the investments and the index history
+// they need up to the given date.
+func (s *InvestmentService) loadValuationData(investments []models.Investment, workspaceID string, until time.Time) (*valuationData, error) {
+	ids := make([]primitive.ObjectID, len(investments))
+	earliest := map[string]time.Time{}
+	for i := range investments {
+		ids[i] = investments[i].ID
+		if investments[i].Indexer == nil {
+			continue
+		}
+		start, err := finance.ParseDate(investments[i].Date)
+		if err != nil {
+			continue
+		}
+		if current, ok := earliest[*investments[i].Indexer]; !ok || start.Before(current) {
+			earliest[*investments[i].Indexer] = start
+		}
+	}
+
+	movements, err := s.movementRepo.FindByInvestments(ids, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string][]models.IndexValue{}
+	for indexer, start := range earliest {
+		values[indexer], err = s.indexRepo.FindRange(indexer, monthStart(start).Format(finance.DateLayout), until.Format(finance.DateLayout))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &valuationData{movements: movements, values: values}, nil
+}
+
+// fillValuations valuates the investments in bulk.
+func (s *InvestmentService) fillValuations(investments []models.Investment, workspaceID string, asOf time.Time) error {
+	if len(investments) == 0 {
+		return nil
+	}
+
+	data, err := s.loadValuationData(investments, workspaceID, asOf)
+	if err != nil {
+		return err
+	}
+
+	for i := range investments {
+		// Investments with unparseable dates are listed without a valuation
+		valuation, err := data.valuate(&investments[i], asOf)
+		if err != nil {
+			continue
+		}
+		investments[i].Valuation = valuation
+	}
+
+	return nil
+}
+
+// valueSeries returns the gross value of the whole fixed income portfolio on
+// each of the given dates.
+func (s *InvestmentService) valueSeries(workspaceID string, dates []time.Time) ([]float64, error) {
+	series := make([]float64, len(dates))
+	if len(dates) == 0 {
+		return series, nil
+	}
+
+	investments, err := s.repo.FindAll(workspaceID)
+	if err != nil || len(investments) == 0 {
+		return series, err
+	}
+
+	until := dates[0]
+	for _, date := range dates {
+		if date.After(until) {
+			until = date
+		}
+	}
+
+	data, err := s.loadValuationData(investments, workspaceID, until)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, date := range dates {
+		for j := range investments {
+			valuation, err := data.valuate(&investments[j], date)
+			if err != nil {
+				continue
+			}
+			series[i] += valuation.GrossValue
+		}
+	}
+
+	return series, nil
+}
+
+// cashFlows returns the money put in (positive) and taken out (negative) of the
+// fixed income portfolio. Income, fees and taxes stay inside the portfolio.
+func (s *InvestmentService) cashFlows(workspaceID string) ([]finance.CashFlow, error) {
+	investments, err := s.repo.FindAll(workspaceID)
+	if err != nil || len(investments) == 0 {
+		return nil, err
+	}
+
+	ids := make([]primitive.ObjectID, len(investments))
+	for i := range investments {
+		ids[i] = investments[i].ID
+	}
+
+	movements, err := s.movementRepo.FindByInvestments(ids, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	flows := []finance.CashFlow{}
+	for i := range investments {
+		ledger := movements[investments[i].ID]
+		if len(ledger) == 0 {
+			// Legacy investments have no ledger: their amount is the only contribution
+			ledger = []models.InvestmentMovement{{
+				Type:   models.MovementContribution,
+				Amount: investments[i].Amount,
+				Date:   investments[i].Date,
+			}}
+		}
+
+		for _, movement := range ledger {
+			date, err := finance.ParseDate(movement.Date)
+			if err != nil {
+				continue
+			}
+			switch movement.Type {
+			case models.MovementContribution:
+				flows = append(flows, finance.CashFlow{Date: date, Amount: movement.Amount})
+			case models.MovementWithdrawal:
+				flows = append(flows, finance.CashFlow{Date: date, Amount: -movement.Amount})
+			}
+		}
+	}
+
+	return flows, nil
+}
+
+// GetLiquidity builds the liquidity ladder of the fixed income portfolio and
+// lists the investments maturing within the given number of days.
+func (s *InvestmentService) GetLiquidity(workspaceID string, days int) (*models.LiquidityReport, error) {
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	report := &models.LiquidityReport{
+		Date:       today.Format(finance.DateLayout),
+		Buckets:    make([]models.LiquidityBucket, len(models.LiquidityHorizons)),
+		Maturities: []models.MaturityItem{},
+	}
+	for i, horizon := range models.LiquidityHorizons {
+		report.Buckets[i].Days = horizon
+	}
+
+	investments, err := s.repo.FindAll(workspaceID)
+	if err != nil || len(investments) == 0 {
+		return report, err
+	}
+
+	data, err := s.loadValuationData(investments, workspaceID, today)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range investments {
+		investment := &investments[i]
+		valuation, err := data.valuate(investment, today)
+		if err != nil || valuation.GrossValue <= 0 {
+			continue
+		}
+		report.TotalValue += valuation.NetValue
+
+		available := 0
+		maturity, hasMaturity := maturityDate(investment)
+		daysToMaturity := int(maturity.Sub(today).Hours() / 24)
+		switch investmentLiquidity(investment) {
+		case models.LiquidityD30:
+			available = 30
+			if hasMaturity && daysToMaturity < available {
+				available = daysToMaturity
+			}
+		case models.LiquidityAtMaturity:
+			available = daysToMaturity
+		}
+		if available < 0 {
+			available = 0
+		}
+
+		for j := range report.Buckets {
+			if available <= report.Buckets[j].Days {
+				report.Buckets[j].Amount += valuation.NetValue
+			}
+		}
+
+		if !hasMaturity || daysToMaturity < 0 || daysToMaturity > days {
+			continue
+		}
+
+		var indexValues []models.IndexValue
+		if investment.Indexer != nil {
+			indexValues = data.values[*investment.Indexer]
+		}
+		projection := projectLots(investment, data.movements[investment.ID], indexValues, today, maturity)
+
+		report.Maturities = append(report.Maturities, models.MaturityItem{
+			InvestmentID:        investment.ID.Hex(),
+			Name:                investment.Name,
+			Type:                investment.Type,
+			MaturityDate:        *investment.MaturityDate,
+			DaysToMaturity:      daysToMaturity,
+			Liquidity:           investmentLiquidity(investment),
+			CurrentValue:        valuation.NetValue,
+			ProjectedGrossValue: projection.GrossValue,
+			ProjectedNetValue:   projection.NetValue,
+		})
+	}
+
+	for i := range report.Buckets {
+		if report.TotalValue > 0 {
+			report.Buckets[i].Percentage = report.Buckets[i].Amount / report.TotalValue * 100
+		}
+	}
+
+	sort.Slice(report.Maturities, func(i, j int) bool {
+		return report.Maturities[i].MaturityDate < report.Maturities[j].MaturityDate
+	})
+
+	return report, nil
+}
+
+// projectLots values the lots held today at the maturity date. Accrual up to
+// today follows the investment's own rate or index; from today on there is no
+// index history, so indexed investments assume the last published index value
+// holds until maturity. Taxes are those due on redemption at maturity.
+func projectLots(investment *models.Investment, movements []models.InvestmentMovement, values []models.IndexValue, today, maturity time.Time) *models.InvestmentValuation {
+	investmentType := ""
+	if investment.Type != nil {
+		investmentType = *investment.Type
+	}
+
+	projection := &models.InvestmentValuation{
+		InvestmentID: investment.ID.Hex(),
+		Date:         maturity.Format(finance.DateLayout),
+	}
+
+	for _, l := range heldLots(investment, movements, values, today) {
+		if l.principal <= 0 {
+			continue
+		}
+
+		gross := l.principal * accrualFactor(investment, values, l.date, today) * projectedFactor(investment, values, today, maturity)
+		days := int(maturity.Sub(l.date).Hours() / 24)
+		taxes := finance.RedemptionTax(gross-l.principal, days, investmentType)
+
+		projection.Invested += l.principal
+		projection.GrossValue += gross
+		projection.IOF += taxes.IOF
+		projection.IncomeTax += taxes.IncomeTax
+	}
+
+	projection.Earnings = projection.GrossValue - projection.Invested
+	projection.TaxDue = projection.IOF + projection.IncomeTax
+	projection.NetValue = projection.GrossValue - projection.TaxDue
+	return projection
+}
+
+// investmentLiquidity defaults investments created without a liquidity to
+// redemption at maturity when they have one, and to daily liquidity otherwise.
+func investmentLiquidity(investment *models.Investment) string {
+	if investment.Liquidity != "" {
+		return investment.Liquidity
+	}
+	if investment.MaturityDate != nil {
+		return models.LiquidityAtMaturity
+	}
+	return models.LiquidityDaily
+}
+
+func maturityDate(investment *models.Investment) (time.Time, bool) {
+	if investment.MaturityDate == nil {
+		return time.Time{}, false
+	}
+	maturity, err := finance.ParseDate(*investment.MaturityDate)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return maturity, true
+}
+
+func accrualFactor(investment *models.Investment, values []models.IndexValue, from, to time.Time) float64 {
+	if investment.Indexer == nil {
+		return finance.FixedRateFactor(investment.Rate, from, to)
+	}
+	return finance.AccrualFactor(indexedRate(investment), values, from, to)
+}
+
+// projectedFactor is the accrual expected over [from, to) past the index
+// history.
+func projectedFactor(investment *models.Investment, values []models.IndexValue, from, to time.Time) float64 {
+	if investment.Indexer == nil {
+		return finance.FixedRateFactor(investment.Rate, from, to)
+	}
+	return finance.ProjectedFactor(indexedRate(investment), values, from, to)
+}
+
+func indexedRate(investment *models.Investment) finance.IndexedRate {
+	return finance.IndexedRate{
+		Indexer:      *investment.Indexer,
+		IndexPercent: investment.IndexPercent,
+		Spread:       investment.Spread,
+	}
+}
+
+func monthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,206 @@
+package services
+
+import (
+	"errors"
+	"financial-api/internal/finance"
+	"financial-api/internal/models"
+	"financial-api/internal/repositories"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrLoanNotFound        = errors.New("loan not found")
+	ErrInvalidExtraPayment = errors.New("extra payment must be attached to an installment within the term")
+)
+
+type LoanService struct {
+	repo            *repositories.LoanRepository
+	transactionRepo *repositories.TransactionRepository
+}
+
+func NewLoanService(repo *repositories.LoanRepository, transactionRepo *repositories.TransactionRepository) *LoanService {
+	return &LoanService{repo: repo, transactionRepo: transactionRepo}
+}
+
+func (s *LoanService) CreateLoan(loan *models.Loan, workspaceID, role string) error {
+	if err := requireRole(role, models.RoleEditor); err != nil {
+		return err
+	}
+
+	if _, err := finance.ParseDate(loan.StartDate); err != nil {
+		return ErrInvalidDate
+	}
+	loan.ExtraPayments = []models.ExtraPayment{}
+	loan.PostedInstallments = []int{}
+
+	return s.repo.Create(loan, workspaceID)
+}
+
+func (s *LoanService) GetLoans(workspaceID string) ([]models.Loan, error) {
+	return s.repo.FindByWorkspace(workspaceID)
+}
+
+func (s *LoanService) GetLoan(id, workspaceID string) (*models.Loan, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrLoanNotFound
+	}
+
+	loan, err := s.repo.FindByID(objectID, workspaceID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrLoanNotFound
+		}
+		return nil, err
+	}
+
+	return loan, nil
+}
+
+func (s *LoanService) DeleteLoan(id, workspaceID, role string) error {
+	if err := requireRole(role, models.RoleEditor); err != nil {
+		return err
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrLoanNotFound
+	}
+
+	deleted, err := s.repo.Delete(objectID, workspaceID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrLoanNotFound
+	}
+	return nil
+}
+
+func (s *LoanService) GetSchedule(id, workspaceID string) (*models.LoanSchedule, error) {
+	loan, err := s.GetLoan(id, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	return Schedule(loan)
+}
+
+// Schedule builds the amortization table of a loan, saved or not.
+func Schedule(loan *models.Loan) (*models.LoanSchedule, error) {
+	start, err := finance.ParseDate(loan.StartDate)
+	if err != nil {
+		return nil, ErrInvalidDate
+	}
+
+	posted := map[int]bool{}
+	for _, number := range loan.PostedInstallments {
+		posted[number] = true
+	}
+
+	schedule := &models.LoanSchedule{
+		Loan:         *loan,
+		Installments: finance.Amortize(loan.System, loan.Principal, loan.Rate, loan.Term, start, loan.ExtraPayments),
+	}
+	schedule.Term = len(schedule.Installments)
+	for i := range schedule.Installments {
+		installment := &schedule.Installments[i]
+		installment.Posted = posted[installment.Number]
+		schedule.TotalPaid += installment.Payment + installment.Extra
+		schedule.TotalInterest += installment.Interest
+	}
+
+	return schedule, nil
+}
+
+func (s *LoanService) AddExtraPayment(id string, extra models.ExtraPayment, workspaceID, role string) (*models.LoanSchedule, error) {
+	if err := requireRole(role, models.RoleEditor); err != nil {
+		return nil, err
+	}
+
+	loan, err := s.GetLoan(id, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule, err := Schedule(loan)
+	if err != nil {
+		return nil, err
+	}
+	if extra.Installment > schedule.Term {
+		return nil, ErrInvalidExtraPayment
+	}
+
+	loan, err = s.repo.AddExtraPayment(loan.ID, extra, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	return Schedule(loan)
+}
+
+// PostInstallments records every installment due up to the given date that
+// was not posted yet as two expense transactions: the interest and the
+// principal, extra payments included.
+func (s *LoanService) PostInstallments(id, until, workspaceID, role string) (*models.LoanPostResult, error) {
+	if err := requireRole(role, models.RoleEditor); err != nil {
+		return nil, err
+	}
+
+	untilDate := time.Now().UTC()
+	if until != "" {
+		parsed, err := finance.ParseDate(until)
+		if err != nil {
+			return nil, ErrInvalidDate
+		}
+		untilDate = parsed
+	}
+
+	schedule, err := s.GetSchedule(id, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	loan := &schedule.Loan
+
+	result := &models.LoanPostResult{Posted: []int{}}
+	for _, installment := range schedule.Installments {
+		if installment.Posted || installment.Date > untilDate.Format(finance.DateLayout) {
+			continue
+		}
+
+		parts := []struct {
+			label  string
+			amount float64
+		}{
+			{"juros", installment.Interest},
+			{"amortização", installment.Principal + installment.Extra},
+		}
+		for _, part := range parts {
+			if part.amount <= 0 {
+				continue
+			}
+			transaction := &models.Transaction{
+				Type:        "expense",
+				Description: fmt.Sprintf("%s - %s %d/%d", loan.Name, part.label, installment.Number, schedule.Term),
+				Amount:      part.amount,
+				Date:        installment.Date,
+				CategoryID:  loan.CategoryID,
+			}
+			if err := s.transactionRepo.Create(transaction, workspaceID); err != nil {
+				return nil, err
+			}
+			result.Transactions++
+		}
+		result.Posted = append(result.Posted, installment.Number)
+	}
+
+	if len(result.Posted) > 0 {
+		if err := s.repo.MarkPosted(loan.ID, result.Posted, workspaceID); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,242 @@
+package services
+
+import (
+	"errors"
+	"financial-api/internal/finance"
+	"financial-api/internal/models"
+	"financial-api/internal/repositories"
+	"strings"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrMerchantNotFound    = errors.New("merchant not found")
+	ErrInvalidTopMerchants = errors.New("limit must be between 1 and 100 and dates YYYY-MM-DD")
+)
+
+// backfillBatch is how many untagged transactions BackfillMerchants loads at
+// a time.
+const backfillBatch = 500
+
+// Payment processors and wallets that prefix the merchant, as in PAG*IFOOD
+var processorPrefixes = map[string]bool{
+	"PAG": true, "PAGSEGURO": true, "PAGSEG": true, "MP": true, "MERCADOPAGO": true, "MERCPAGO": true,
+	"PICPAY": true, "PP": true, "PAYPAL": true, "EBANX": true, "EBW": true, "IZ": true, "SQ": true,
+	"STONE": true, "CIELO": true, "GETNET": true, "SUMUP": true, "DL": true, "EC": true, "HNA": true,
+}
+
+// Words that introduce a store number or branch
+var storeWords = map[string]bool{
+	"LOJA": true, "LJ": true, "FILIAL": true, "UNIDADE": true, "UNID": true,
+}
+
+// Cities and country codes that card statements append after the merchant
+var locationSuffixes = []string{
+	"SAO PAULO", "RIO DE JANEIRO", "BELO HORIZONTE", "CURITIBA", "PORTO ALEGRE", "SALVADOR", "RECIFE",
+	"FORTALEZA", "BRASILIA", "CAMPINAS", "OSASCO", "BARUERI", "GUARULHOS", "SANTOS", "GOIANIA", "MANAUS",
+	"BELEM", "FLORIANOPOLIS", "VITORIA", "NITEROI", "SAO BERNARDO DO CAMPO", "SANTO ANDRE", "RIBEIRAO PRETO",
+	"SP", "RJ", "MG", "PR", "RS", "SC", "BA", "PE", "CE", "DF", "GO", "ES", "AM", "PA", "BR", "BRA", "BRASIL",
+}
+
+var accentReplacer = strings.NewReplacer(
+	"Á", "A", "À", "A", "Â", "A", "Ã", "A", "Ä", "A",
+	"É", "E", "Ê", "E", "È", "E", "Ë", "E",
+	"Í", "I", "Î", "I", "Ì", "I", "Ï", "I",
+	"Ó", "O", "Ô", "O", "Õ", "O", "Ò", "O", "Ö", "O",
+	"Ú", "U", "Û", "U", "Ù", "U", "Ü", "U",
+	"Ç", "C", "Ñ", "N",
+)
+
+type MerchantService struct {
+	repo            *repositories.MerchantRepository
+	transactionRepo *repositories.TransactionRepository
+	aggregationRepo *repositories.AggregationRepository
+}
+
+func NewMerchantService(
+	repo *repositories.MerchantRepository,
+	transactionRepo *repositories.TransactionRepository,
+	aggregationRepo *repositories.AggregationRepository,
+) *MerchantService {
+	return &MerchantService{repo: repo, transactionRepo: transactionRepo, aggregationRepo: aggregationRepo}
+}
+
+// Tag sets the merchant of a transaction from its raw description, creating
+// the merchant the first time it is seen. The description is kept as is.
+func (s *MerchantService) Tag(transaction *models.Transaction, workspaceID string) error {
+	key := NormalizeMerchant(transaction.Description)
+	if key == "" {
+		return nil
+	}
+
+	merchant, err := s.repo.FindOrCreate(key, merchantDisplayName(key), workspaceID)
+	if err != nil {
+		return err
+	}
+
+	merchantID := merchant.ID.Hex()
+	transaction.Merchant = merchant.Name
+	transaction.MerchantID = &merchantID
+	transaction.MerchantKey = key
+	return nil
+}
+
+// BackfillMerchants tags the transactions saved before merchants were
+// tracked, so renames and aliases reach them too, and returns how many it
+// tagged. Tagged transactions are skipped, so it is safe to run on every start.
+func (s *MerchantService) BackfillMerchants() (int, error) {
+	tagged := 0
+	for {
+		transactions, err := s.transactionRepo.FindUntagged(backfillBatch)
+		if err != nil || len(transactions) == 0 {
+			return tagged, err
+		}
+
+		for i := range transactions {
+			transaction := &transactions[i]
+			if transaction.WorkspaceID != nil {
+				if err := s.Tag(transaction, *transaction.WorkspaceID); err != nil {
+					return tagged, err
+				}
+			}
+			if err := s.transactionRepo.SetMerchantKey(transaction); err != nil {
+				return tagged, err
+			}
+			tagged++
+		}
+	}
+}
+
+func (s *MerchantService) GetMerchants(workspaceID string) ([]models.Merchant, error) {
+	return s.repo.FindByWorkspace(workspaceID)
+}
+
+// UpdateMerchant renames a merchant and sets its aliases. Aliases are raw or
+// normalized descriptions; the transactions and merchants under them are
+// merged into this merchant.
+func (s *MerchantService) UpdateMerchant(id string, name string, aliases []string, workspaceID, role string) (*models.Merchant, error) {
+	if err := requireRole(role, models.RoleEditor); err != nil {
+		return nil, err
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrMerchantNotFound
+	}
+
+	merchant := &models.Merchant{ID: objectID, Name: name, Aliases: []string{}}
+	seen := map[string]bool{}
+	for _, alias := range aliases {
+		if key := NormalizeMerchant(alias); key != "" && !seen[key] {
+			seen[key] = true
+			merchant.Aliases = append(merchant.Aliases, key)
+		}
+	}
+
+	if err := s.repo.Update(merchant, workspaceID); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrMerchantNotFound
+		}
+		return nil, err
+	}
+
+	if len(merchant.Aliases) > 0 {
+		if err := s.repo.DeleteByKeys(merchant.Aliases, merchant.ID, workspaceID); err != nil {
+			return nil, err
+		}
+	}
+	keys := append([]string{merchant.Key}, merchant.Aliases...)
+	if err := s.transactionRepo.SetMerchant(keys, merchant, workspaceID); err != nil {
+		return nil, err
+	}
+
+	return merchant, nil
+}
+
+func (s *MerchantService) GetTopMerchants(workspaceID, from, to string, limit int) ([]models.MerchantItem, error) {
+	if limit < 1 || limit > 100 {
+		return nil, ErrInvalidTopMerchants
+	}
+	for _, date := range []string{from, to} {
+		if date == "" {
+			continue
+		}
+		if _, err := finance.ParseDate(date); err != nil {
+			return nil, ErrInvalidTopMerchants
+		}
+	}
+
+	return s.aggregationRepo.GetTopMerchants(workspaceID, from, to, limit)
+}
+
+// NormalizeMerchant reduces a raw card or bank description to the merchant:
+// "PAG*IFOOD 1234 SAO PAULO" and "IFOOD *PEDIDO" both become "IFOOD". It drops
+// payment processor prefixes, whatever follows the merchant's own asterisk,
+// store numbers and trailing cities or states.
+func NormalizeMerchant(description string) string {
+	text := accentReplacer.Replace(strings.ToUpper(strings.TrimSpace(description)))
+
+	if star := strings.Index(text, "*"); star >= 0 {
+		prefix := strings.TrimSpace(text[:star])
+		if processorPrefixes[prefix] {
+			text = text[star+1:]
+			if next := strings.Index(text, "*"); next >= 0 {
+				text = text[:next]
+			}
+		} else {
+			text = prefix
+		}
+	}
+
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '.' && r != '&'
+	})
+
+	kept := []string{}
+	for _, word := range words {
+		word = strings.Trim(word, ".")
+		if word == "" || storeWords[word] || strings.IndexFunc(word, unicode.IsDigit) >= 0 {
+			continue
+		}
+		kept = append(kept, word)
+	}
+
+	// Strip trailing locations, but never the whole name
+	for trimmed := true; trimmed && len(kept) > 1; {
+		trimmed = false
+		joined := strings.Join(kept, " ")
+		for _, location := range locationSuffixes {
+			if strings.HasSuffix(joined, " "+location) {
+				kept = strings.Fields(strings.TrimSuffix(joined, " "+location))
+				trimmed = true
+				break
+			}
+		}
+	}
+
+	return strings.Join(kept, " ")
+}
+
+// merchantDisplayName title-cases a normalized merchant key.
+func merchantDisplayName(key string) string {
+	words := strings.Fields(strings.ToLower(key))
+	for i, word := range words {
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		words[i] = string(runes)
+	}
+	return strings.Join(words, " ")
+}
+
+// transactionMerchant is the merchant a transaction belongs to. Transactions
+// created before merchants were tracked fall back to their normalized
+// description.
+func transactionMerchant(transaction *models.Transaction) string {
+	if transaction.Merchant != "" {
+		return transaction.Merchant
+	}
+	return merchantDisplayName(NormalizeMerchant(transaction.Description))
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"errors"
+	"financial-api/internal/finance"
+	"financial-api/internal/models"
+	"financial-api/internal/repositories"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Series intervals
+const (
+	IntervalDaily   = "daily"
+	IntervalMonthly = "monthly"
+)
+
+var (
+	ErrNetWorthItemNotFound = errors.New("net worth item not found")
+	ErrInvalidInterval      = errors.New("interval must be daily or monthly")
+)
+
+type NetWorthService struct {
+	repo              *repositories.NetWorthRepository
+	workspaceRepo     *repositories.WorkspaceRepository
+	transactionRepo   *repositories.TransactionRepository
+	investmentService *InvestmentService
+	positionService   *PositionService
+}
+
+func NewNetWorthService(
+	repo *repositories.NetWorthRepository,
+	workspaceRepo *repositories.WorkspaceRepository,
+	transactionRepo *repositories.TransactionRepository,
+	investmentService *InvestmentService,
+	positionService *PositionService,
+) *NetWorthService {
+	return &NetWorthService{
+		repo:              repo,
+		workspaceRepo:     workspaceRepo,
+		transactionRepo:   transactionRepo,
+		investmentService: investmentService,
+		positionService:   positionService,
+	}
+}
+
+func (s *NetWorthService) CreateItem(item *models.NetWorthItem, workspaceID, role string) error {
+	if err := requireRole(role, models.RoleEditor); err != nil {
+		return err
+	}
+
+	return s.repo.CreateItem(item, workspaceID)
+}
+
+func (s *NetWorthService) GetItems(workspaceID string) ([]models.NetWorthItem, error) {
+	return s.repo.FindItems(workspaceID)
+}
+
+func (s *NetWorthService) UpdateItem(id string, item *models.NetWorthItem, workspaceID, role string) error {
+	if err := requireRole(role, models.RoleEditor); err != nil {
+		return err
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrNetWorthItemNotFound
+	}
+
+	item.ID = objectID
+	if err := s.repo.UpdateItem(item, workspaceID); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return ErrNetWorthItemNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *NetWorthService) DeleteItem(id, workspaceID, role string) error {
+	if err := requireRole(role, models.RoleEditor); err != nil {
+		return err
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrNetWorthItemNotFound
+	}
+
+	deleted, err := s.repo.DeleteItem(objectID, workspaceID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrNetWorthItemNotFound
+	}
+	return nil
+}
+
+// TakeSnapshot takes today's snapshot on behalf of a member with the given
+// role.
+func (s *NetWorthService) TakeSnapshot(workspaceID, role string) (*models.NetWorthSnapshot, error) {
+	if err := requireRole(role, models.RoleEditor); err != nil {
+		return nil, err
+	}
+	return s.takeSnapshot(workspaceID)
+}
+
+// takeSnapshot records today's balance sheet of the workspace: the transaction
+// balance, the fixed income portfolio net of redemption taxes, the variable
+// income portfolio at its latest quotes and the manual assets and liabilities.
+func (s *NetWorthService) takeSnapshot(workspaceID string) (*models.NetWorthSnapshot, error) {
+	totals, err := s.transactionRepo.GetTotals(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, _, investments, err := s.investmentService.GetValuationTotals(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	positions, err := s.positionService.GetPositions(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.repo.FindItems(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &models.NetWorthSnapshot{
+		Date:        time.Now().UTC().Format(finance.DateLayout),
+		Cash:        totals.Balance,
+		Investments: investments,
+	}
+	for _, position := range positions {
+		snapshot.Positions += position.MarketValue
+	}
+	for _, item := range items {
+		if item.Kind == models.NetWorthLiability {
+			snapshot.Liabilities += item.Value
+		} else {
+			snapshot.Assets += item.Value
+		}
+	}
+	snapshot.NetWorth = snapshot.Cash + snapshot.Investments + snapshot.Positions + snapshot.Assets - snapshot.Liabilities
+
+	if err := s.repo.UpsertSnapshot(snapshot, workspaceID); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// SnapshotAll takes today's snapshot of every workspace. A failure for one
+// workspace does not stop the others; the number of snapshots taken and the last
+// error are returned.
+func (s *NetWorthService) SnapshotAll() (int, error) {
+	workspaceIDs, err := s.workspaceRepo.FindAllIDs()
+	if err != nil {
+		return 0, err
+	}
+
+	taken := 0
+	var lastErr error
+	for _, workspaceID := range workspaceIDs {
+		if _, err := s.takeSnapshot(workspaceID); err != nil {
+			lastErr = err
+			continue
+		}
+		taken++
+	}
+
+	return taken, lastErr
+}
+
+// GetHistory returns the net worth series in [from, to]. The monthly series
+// keeps the last snapshot of each month.
+func (s *NetWorthService) GetHistory(workspaceID, from, to, interval string) ([]models.NetWorthSnapshot, error) {
+	if interval != IntervalDaily && interval != IntervalMonthly {
+		return nil, ErrInvalidInterval
+	}
+	for _, date := range []string{from, to} {
+		if date == "" {
+			continue
+		}
+		if _, err := finance.ParseDate(date); err != nil {
+			return nil, ErrInvalidDate
+		}
+	}
+
+	snapshots, err := s.repo.FindSnapshots(workspaceID, from, to)
+	if err != nil || interval == IntervalDaily {
+		return snapshots, err
+	}
+
+	monthly := []models.NetWorthSnapshot{}
+	for _, snapshot := range snapshots {
+		if n := len(monthly); n > 0 && monthly[n-1].Date[:7] == snapshot.Date[:7] {
+			monthly[n-1] = snapshot
+			continue
+		}
+		monthly = append(monthly, snapshot)
+	}
+
+	return monthly, nil
+}
@@ -0,0 +1,277 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"financial-api/internal/logger"
+	"financial-api/internal/models"
+	"financial-api/internal/oidc"
+	"financial-api/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrUnknownProvider          = errors.New("unknown identity provider")
+	ErrInvalidOIDCState         = errors.New("invalid or expired sign in state")
+	ErrOIDCLoginFailed          = errors.New("sign in with the identity provider failed")
+	ErrProviderEmailNotVerified = errors.New("the identity provider has not verified this email")
+	ErrIdentityLinked           = errors.New("this identity is linked to another account")
+	ErrProviderAlreadyLinked    = errors.New("another identity at this provider is already linked")
+	ErrIdentityNotLinked        = errors.New("identity provider not linked")
+	ErrLastSignInMethod         = errors.New("set a password before unlinking the last sign in method")
+	ErrAccountEmailNotVerified  = errors.New("an account with this email exists but has not verified it; sign in with its password to link the provider")
+)
+
+const oidcStateTTL = 10 * time.Minute
+
+// OIDCService signs users in through OpenID Connect providers. Identities
+// are linked to existing accounts by verified email, or create the account.
+type OIDCService struct {
+	providers map[string]*oidc.Provider
+	stateRepo *repositories.OIDCStateRepository
+	userRepo  *repositories.UserRepository
+	auth      *AuthService
+	security  *SecurityService
+}
+
+func NewOIDCService(
+	providers []*oidc.Provider,
+	stateRepo *repositories.OIDCStateRepository,
+	userRepo *repositories.UserRepository,
+	auth *AuthService,
+	security *SecurityService,
+) *OIDCService {
+	byName := make(map[string]*oidc.Provider, len(providers))
+	for _, provider := range providers {
+		byName[provider.Name()] = provider
+	}
+
+	return &OIDCService{
+		providers: byName,
+		stateRepo: stateRepo,
+		userRepo:  userRepo,
+		auth:      auth,
+		security:  security,
+	}
+}
+
+// Providers lists the names of the configured providers.
+func (s *OIDCService) Providers() []string {
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Authorize starts a sign in at the provider. With a userID the flow links
+// the provider to that user instead of signing in.
+func (s *OIDCService) Authorize(providerName, userID string) (*models.OIDCAuthorization, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+
+	secrets := make([]string, 3)
+	for i := range secrets {
+		secret, err := newToken()
+		if err != nil {
+			return nil, err
+		}
+		secrets[i] = secret
+	}
+	state, nonce, verifier := secrets[0], secrets[1], secrets[2]
+
+	authorizationURL, err := provider.AuthCodeURL(state, nonce, verifier)
+	if err != nil {
+		logger.Logger.Error("Failed to reach identity provider", zap.String("provider", providerName), zap.Error(err))
+		return nil, ErrOIDCLoginFailed
+	}
+
+	err = s.stateRepo.Create(&models.OIDCState{
+		StateHash: hashToken(state),
+		Provider:  providerName,
+		UserID:    userID,
+		Verifier:  verifier,
+		Nonce:     nonce,
+		ExpiresAt: time.Now().Add(oidcStateTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.OIDCAuthorization{AuthorizationURL: authorizationURL, State: state}, nil
+}
+
+// Login finishes a sign in. A new identity is linked to the account with its
+// email, or creates one, only if the provider verified the email. Accounts
+// that have not verified their own email must link the provider themselves.
+func (s *OIDCService) Login(req *models.OIDCCallbackRequest, clientIP string) (*models.AuthResponse, error) {
+	state, identity, err := s.exchange(req, "")
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByIdentity(state.Provider, identity.Subject)
+	if err == nil {
+		return s.auth.LoginExternal(user)
+	}
+	if err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+
+	if !identity.EmailVerified || identity.Email == "" {
+		return nil, ErrProviderEmailNotVerified
+	}
+
+	// An account that never proved it owns its email may have been
+	// registered by someone else ahead of the real owner, so linking it
+	// would hand them the owner's sign in.
+	user, err = s.userRepo.FindByEmail(identity.Email)
+	if err == nil && user.EmailVerifiedAt == nil {
+		return nil, ErrAccountEmailNotVerified
+	}
+	if err == mongo.ErrNoDocuments {
+		user, err = s.createUser(identity)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.link(user, state.Provider, identity, clientIP); err != nil {
+		return nil, err
+	}
+
+	if user, err = s.userRepo.FindByID(user.ID.Hex()); err != nil {
+		return nil, err
+	}
+	return s.auth.LoginExternal(user)
+}
+
+// Link finishes linking a provider to the signed in user who started it.
+func (s *OIDCService) Link(req *models.OIDCCallbackRequest, userID, clientIP string) (*models.User, error) {
+	state, identity, err := s.exchange(req, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := s.userRepo.FindByIdentity(state.Provider, identity.Subject)
+	if err == nil {
+		if owner.ID.Hex() != userID {
+			return nil, ErrIdentityLinked
+		}
+		return owner, nil
+	}
+	if err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.link(user, state.Provider, identity, clientIP); err != nil {
+		return nil, err
+	}
+	return s.userRepo.FindByID(userID)
+}
+
+// Unlink removes a provider from the user, who must keep a way to sign in.
+func (s *OIDCService) Unlink(providerName, userID, clientIP string) (*models.User, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	linked := false
+	for _, identity := range user.Identities {
+		linked = linked || identity.Provider == providerName
+	}
+	if !linked {
+		return nil, ErrIdentityNotLinked
+	}
+	if user.Password == "" && len(user.Identities) == 1 {
+		return nil, ErrLastSignInMethod
+	}
+
+	if removed, err := s.userRepo.RemoveIdentity(userID, providerName); err != nil {
+		return nil, err
+	} else if !removed {
+		return nil, ErrIdentityNotLinked
+	}
+	if err := s.security.Audit(models.SecurityIdentityUnlinked, user, clientIP); err != nil {
+		return nil, err
+	}
+
+	return s.userRepo.FindByID(userID)
+}
+
+// exchange spends the state, which must have been issued for userID, and
+// redeems the code at its provider.
+func (s *OIDCService) exchange(req *models.OIDCCallbackRequest, userID string) (*models.OIDCState, *oidc.Identity, error) {
+	state, err := s.stateRepo.Consume(hashToken(req.State))
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil, ErrInvalidOIDCState
+		}
+		return nil, nil, err
+	}
+	if state.UserID != userID {
+		return nil, nil, ErrInvalidOIDCState
+	}
+
+	provider, ok := s.providers[state.Provider]
+	if !ok {
+		return nil, nil, ErrUnknownProvider
+	}
+
+	identity, err := provider.Exchange(req.Code, state.Verifier, state.Nonce)
+	if err != nil {
+		logger.Logger.Warn("Identity provider sign in failed", zap.String("provider", state.Provider), zap.Error(err))
+		return nil, nil, ErrOIDCLoginFailed
+	}
+	return state, identity, nil
+}
+
+func (s *OIDCService) link(user *models.User, provider string, identity *oidc.Identity, clientIP string) error {
+	linked, err := s.userRepo.AddIdentity(user.ID.Hex(), models.Identity{
+		Provider: provider,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+		LinkedAt: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	if !linked {
+		return ErrProviderAlreadyLinked
+	}
+
+	return s.security.Audit(models.SecurityIdentityLinked, user, clientIP)
+}
+
+// createUser opens an account without a password. Its owner signs in through
+// the provider, or sets a password with a password reset.
+func (s *OIDCService) createUser(identity *oidc.Identity) (*models.User, error) {
+	name := identity.Name
+	if len(name) < 2 {
+		name = strings.SplitN(identity.Email, "@", 2)[0]
+	}
+
+	now := time.Now()
+	user := &models.User{
+		Email:           identity.Email,
+		Name:            name,
+		EmailVerifiedAt: &now,
+	}
+	if err := s.userRepo.Create(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
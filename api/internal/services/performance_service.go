@@ -0,0 +1,247 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"financial-api/internal/finance"
+	"financial-api/internal/models"
+	"financial-api/internal/repositories"
+)
+
+var ErrInvalidPeriod = errors.New("period must be one of 1m, 3m, 6m, 12m, ytd, all")
+
+type PerformanceService struct {
+	investmentService *InvestmentService
+	positionService   *PositionService
+	dividendService   *DividendService
+	indexRepo         *repositories.IndexRepository
+	quoteRepo         *repositories.QuoteRepository
+}
+
+func NewPerformanceService(
+	investmentService *InvestmentService,
+	positionService *PositionService,
+	dividendService *DividendService,
+	indexRepo *repositories.IndexRepository,
+	quoteRepo *repositories.QuoteRepository,
+) *PerformanceService {
+	return &PerformanceService{
+		investmentService: investmentService,
+		positionService:   positionService,
+		dividendService:   dividendService,
+		indexRepo:         indexRepo,
+		quoteRepo:         quoteRepo,
+	}
+}
+
+// GetPerformance computes the time-weighted and money-weighted returns of the
+// whole portfolio (fixed and variable income) over a period and compares them
+// with the CDI and IBOVESPA over the same window. Explicit from/to dates
+// override the period.
+func (s *PerformanceService) GetPerformance(workspaceID, period, from, to string) (*models.PerformanceReport, error) {
+	flows, err := s.cashFlows(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end, err := resolvePeriod(period, from, to, flows)
+	if err != nil {
+		return nil, err
+	}
+
+	// Flows inside the window, merged per day
+	byDay := map[time.Time]float64{}
+	for _, flow := range flows {
+		if flow.Date.After(start) && !flow.Date.After(end) {
+			byDay[flow.Date] += flow.Amount
+		}
+	}
+	days := make([]time.Time, 0, len(byDay))
+	for day := range byDay {
+		days = append(days, day)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+
+	dates := append([]time.Time{start}, days...)
+	dates = append(dates, end)
+	values, err := s.valueSeries(workspaceID, dates)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.PerformanceReport{
+		Period:     period,
+		From:       start.Format(finance.DateLayout),
+		To:         end.Format(finance.DateLayout),
+		StartValue: values[0],
+		EndValue:   values[len(values)-1],
+		Benchmarks: []models.BenchmarkReturn{},
+	}
+
+	// Chain the holding period returns between flows: each sub-period ends
+	// just before the flow of its last day.
+	growth := 1.0
+	previous := values[0]
+	for i, day := range days {
+		flow := byDay[day]
+		report.NetContribution += flow
+		before := values[i+1] - flow
+		if previous > 0 {
+			growth *= before / previous
+		}
+		previous = values[i+1]
+	}
+	if previous > 0 {
+		growth *= report.EndValue / previous
+	}
+	report.TWR = (growth - 1) * 100
+	report.TWRAnnualized = finance.Annualize(growth-1, int(end.Sub(start).Hours()/24)) * 100
+
+	// XIRR from the investor's side: the starting value is paid in, the end value received
+	xirrFlows := []finance.CashFlow{{Date: start, Amount: -report.StartValue}}
+	for _, day := range days {
+		xirrFlows = append(xirrFlows, finance.CashFlow{Date: day, Amount: -byDay[day]})
+	}
+	xirrFlows = append(xirrFlows, finance.CashFlow{Date: end, Amount: report.EndValue})
+	if rate, err := finance.XIRR(xirrFlows); err == nil {
+		percent := rate * 100
+		report.XIRR = &percent
+	}
+
+	for _, name := range []string{models.BenchmarkCDI, models.BenchmarkIbovespa} {
+		benchmark, err := s.benchmarkReturn(name, start, end)
+		if err != nil {
+			return nil, err
+		}
+		if benchmark.Available {
+			benchmark.Excess = report.TWR - benchmark.Return
+		}
+		report.Benchmarks = append(report.Benchmarks, *benchmark)
+	}
+
+	return report, nil
+}
+
+func (s *PerformanceService) cashFlows(workspaceID string) ([]finance.CashFlow, error) {
+	flows, err := s.investmentService.cashFlows(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	tradeFlows, err := s.positionService.cashFlows(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	dividendFlows, err := s.dividendService.cashFlows(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	flows = append(flows, tradeFlows...)
+	return append(flows, dividendFlows...), nil
+}
+
+func (s *PerformanceService) valueSeries(workspaceID string, dates []time.Time) ([]float64, error) {
+	fixedIncome, err := s.investmentService.valueSeries(workspaceID, dates)
+	if err != nil {
+		return nil, err
+	}
+
+	variableIncome, err := s.positionService.valueSeries(workspaceID, dates)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range fixedIncome {
+		fixedIncome[i] += variableIncome[i]
+	}
+	return fixedIncome, nil
+}
+
+// benchmarkReturn returns the CDI accrued over the window from the index table,
+// or the IBOVESPA variation from the closes imported under the IBOV ticker.
+func (s *PerformanceService) benchmarkReturn(name string, start, end time.Time) (*models.BenchmarkReturn, error) {
+	benchmark := &models.BenchmarkReturn{Name: name}
+
+	if name == models.BenchmarkCDI {
+		values, err := s.indexRepo.FindRange(models.IndexCDI, start.Format(finance.DateLayout), end.Format(finance.DateLayout))
+		if err != nil {
+			return nil, err
+		}
+		if len(values) > 0 {
+			factor := finance.AccrualFactor(finance.IndexedRate{Indexer: models.IndexCDI}, values, start, end)
+			benchmark.Return = (factor - 1) * 100
+			benchmark.Available = true
+		}
+		return benchmark, nil
+	}
+
+	quotes, err := s.quoteRepo.FindRange(name, "", end.Format(finance.DateLayout))
+	if err != nil {
+		return nil, err
+	}
+
+	var first, last *models.Quote
+	startDay := start.Format(finance.DateLayout)
+	for i := range quotes {
+		if quotes[i].Date <= startDay {
+			first = &quotes[i]
+		}
+		last = &quotes[i]
+	}
+	if first != nil && last != nil && first.Close > 0 {
+		benchmark.Return = (last.Close/first.Close - 1) * 100
+		benchmark.Available = true
+	}
+
+	return benchmark, nil
+}
+
+// resolvePeriod turns a named period or explicit dates into a window ending today
+// by default. "all" starts on the first cash flow.
+func resolvePeriod(period, from, to string, flows []finance.CashFlow) (time.Time, time.Time, error) {
+	now := time.Now().UTC()
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if to != "" {
+		parsed, err := finance.ParseDate(to)
+		if err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidDate
+		}
+		end = parsed
+	}
+
+	if from != "" {
+		start, err := finance.ParseDate(from)
+		if err != nil || !start.Before(end) {
+			return time.Time{}, time.Time{}, ErrInvalidDate
+		}
+		return start, end, nil
+	}
+
+	switch period {
+	case "1m":
+		return end.AddDate(0, -1, 0), end, nil
+	case "3m":
+		return end.AddDate(0, -3, 0), end, nil
+	case "6m":
+		return end.AddDate(0, -6, 0), end, nil
+	case "", "12m":
+		return end.AddDate(-1, 0, 0), end, nil
+	case "ytd":
+		return time.Date(end.Year(), time.January, 1, 0, 0, 0, 0, time.UTC), end, nil
+	case "all":
+		start := end
+		for _, flow := range flows {
+			if flow.Date.Before(start) {
+				start = flow.Date
+			}
+		}
+		// The first flow itself must fall inside the window
+		return start.AddDate(0, 0, -1), end, nil
+	default:
+		return time.Time{}, time.Time{}, ErrInvalidPeriod
+	}
+}
@@ -0,0 +1,259 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"financial-api/internal/finance"
+	"financial-api/internal/models"
+	"financial-api/internal/repositories"
+)
+
+var (
+	ErrInsufficientQuantity = errors.New("sell quantity exceeds position")
+	ErrPositionNotFound     = errors.New("position not found")
+)
+
+// quantityEpsilon absorbs float residue when a position is fully sold.
+const quantityEpsilon = 1e-9
+
+type PositionService struct {
+	tradeRepo *repositories.TradeRepository
+	quoteRepo *repositories.QuoteRepository
+}
+
+func NewPositionService(tradeRepo *repositories.TradeRepository, quoteRepo *repositories.QuoteRepository) *PositionService {
+	return &PositionService{tradeRepo: tradeRepo, quoteRepo: quoteRepo}
+}
+
+func (s *PositionService) CreateTrade(trade *models.Trade, workspaceID, role string) error {
+	if err := requireRole(role, models.RoleEditor); err != nil {
+		return err
+	}
+
+	if _, err := finance.ParseDate(trade.Date); err != nil {
+		return ErrInvalidDate
+	}
+	trade.Ticker = normalizeTicker(trade.Ticker)
+
+	if trade.Side == models.TradeSell {
+		trades, err := s.tradeRepo.FindByWorkspace(workspaceID, trade.Ticker)
+		if err != nil {
+			return err
+		}
+
+		// A backdated sell must leave every later sell covered too, so the
+		// whole history is replayed with the sell in place
+		at := sort.Search(len(trades), func(i int) bool { return trades[i].Date > trade.Date })
+		replayed := make([]models.Trade, 0, len(trades)+1)
+		replayed = append(replayed, trades[:at]...)
+		replayed = append(replayed, *trade)
+		replayed = append(replayed, trades[at:]...)
+		if oversold(replayed) {
+			return ErrInsufficientQuantity
+		}
+	}
+
+	return s.tradeRepo.Create(trade, workspaceID)
+}
+
+func (s *PositionService) GetTrades(workspaceID, ticker string) ([]models.Trade, error) {
+	return s.tradeRepo.FindByWorkspace(workspaceID, normalizeTicker(ticker))
+}
+
+// GetPositions returns every ticker the workspace has traded, valued at its
+// latest quote. Closed positions are kept for their realized result.
+func (s *PositionService) GetPositions(workspaceID string) ([]models.Position, error) {
+	trades, err := s.tradeRepo.FindByWorkspace(workspaceID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	built := buildPositions(trades)
+	tickers := make([]string, 0, len(built))
+	for ticker := range built {
+		tickers = append(tickers, ticker)
+	}
+	sort.Strings(tickers)
+
+	quotes := map[string]models.Quote{}
+	if len(tickers) > 0 {
+		quotes, err = s.quoteRepo.FindLatest(tickers)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	positions := make([]models.Position, 0, len(tickers))
+	for _, ticker := range tickers {
+		position := built[ticker]
+		if quote, ok := quotes[ticker]; ok {
+			position.LastPrice = quote.Close
+			position.PriceDate = quote.Date
+		}
+		position.MarketValue = position.Quantity * position.LastPrice
+		position.UnrealizedPnL = position.MarketValue - position.TotalCost
+		positions = append(positions, *position)
+	}
+
+	return positions, nil
+}
+
+func (s *PositionService) GetPosition(workspaceID, ticker string) (*models.Position, error) {
+	positions, err := s.GetPositions(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	ticker = normalizeTicker(ticker)
+	for i := range positions {
+		if positions[i].Ticker == ticker {
+			return &positions[i], nil
+		}
+	}
+	return nil, ErrPositionNotFound
+}
+
+// valueSeries returns the market value of the variable income portfolio on each
+// of the given dates, using the last quote on or before each date.
+func (s *PositionService) valueSeries(workspaceID string, dates []time.Time) ([]float64, error) {
+	series := make([]float64, len(dates))
+	trades, err := s.tradeRepo.FindByWorkspace(workspaceID, "")
+	if err != nil || len(trades) == 0 || len(dates) == 0 {
+		return series, err
+	}
+
+	until := dates[0]
+	for _, date := range dates {
+		if date.After(until) {
+			until = date
+		}
+	}
+
+	quotes := map[string][]models.Quote{}
+	for _, trade := range trades {
+		if _, ok := quotes[trade.Ticker]; ok {
+			continue
+		}
+		quotes[trade.Ticker], err = s.quoteRepo.FindRange(trade.Ticker, "", until.Format(finance.DateLayout))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i, date := range dates {
+		day := date.Format(finance.DateLayout)
+		held := []models.Trade{}
+		for _, trade := range trades {
+			if trade.Date <= day {
+				held = append(held, trade)
+			}
+		}
+
+		for ticker, position := range buildPositions(held) {
+			price := position.LastPrice
+			for _, quote := range quotes[ticker] {
+				if quote.Date > day {
+					break
+				}
+				price = quote.Close
+			}
+			series[i] += position.Quantity * price
+		}
+	}
+
+	return series, nil
+}
+
+// cashFlows returns the money spent on buys (positive) and received from sells
+// (negative), fees included.
+func (s *PositionService) cashFlows(workspaceID string) ([]finance.CashFlow, error) {
+	trades, err := s.tradeRepo.FindByWorkspace(workspaceID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	flows := make([]finance.CashFlow, 0, len(trades))
+	for _, trade := range trades {
+		date, err := finance.ParseDate(trade.Date)
+		if err != nil {
+			continue
+		}
+		amount := trade.Quantity*trade.Price + trade.Fees
+		if trade.Side == models.TradeSell {
+			amount = -(trade.Quantity*trade.Price - trade.Fees)
+		}
+		flows = append(flows, finance.CashFlow{Date: date, Amount: amount})
+	}
+
+	return flows, nil
+}
+
+// buildPositions replays trades in order with the average cost method: buys
+// (and their fees) raise the average cost, sells realize the difference
+// between the sale price net of fees and the average cost.
+func buildPositions(trades []models.Trade) map[string]*models.Position {
+	positions := map[string]*models.Position{}
+	for _, trade := range trades {
+		position, ok := positions[trade.Ticker]
+		if !ok {
+			position = &models.Position{Ticker: trade.Ticker, Sales: []models.SaleResult{}}
+			positions[trade.Ticker] = position
+		}
+		position.AssetClass = trade.AssetClass
+		// Without a quote the position is valued at the last traded price
+		position.LastPrice = trade.Price
+
+		switch trade.Side {
+		case models.TradeBuy:
+			position.TotalCost += trade.Quantity*trade.Price + trade.Fees
+			position.Quantity += trade.Quantity
+			position.AverageCost = position.TotalCost / position.Quantity
+		case models.TradeSell:
+			realized := trade.Quantity*(trade.Price-position.AverageCost) - trade.Fees
+			position.RealizedPnL += realized
+			position.Sales = append(position.Sales, models.SaleResult{
+				TradeID:     trade.ID.Hex(),
+				Date:        trade.Date,
+				Quantity:    trade.Quantity,
+				Price:       trade.Price,
+				AverageCost: position.AverageCost,
+				RealizedPnL: realized,
+			})
+
+			position.Quantity -= trade.Quantity
+			if position.Quantity <= quantityEpsilon {
+				position.Quantity = 0
+				position.TotalCost = 0
+				position.AverageCost = 0
+			} else {
+				position.TotalCost = position.Quantity * position.AverageCost
+			}
+		}
+	}
+	return positions
+}
+
+// oversold reports whether trades, in date order, ever sell more of a ticker
+// than is held at that point.
+func oversold(trades []models.Trade) bool {
+	held := map[string]float64{}
+	for _, trade := range trades {
+		switch trade.Side {
+		case models.TradeBuy:
+			held[trade.Ticker] += trade.Quantity
+		case models.TradeSell:
+			held[trade.Ticker] -= trade.Quantity
+			if held[trade.Ticker] < -quantityEpsilon {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func normalizeTicker(ticker string) string {
+	return strings.ToUpper(strings.TrimSpace(ticker))
+}
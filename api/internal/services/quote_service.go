@@ -0,0 +1,129 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"financial-api/internal/finance"
+	"financial-api/internal/models"
+	"financial-api/internal/repositories"
+)
+
+type QuoteService struct {
+	repo *repositories.QuoteRepository
+}
+
+func NewQuoteService(repo *repositories.QuoteRepository) *QuoteService {
+	return &QuoteService{repo: repo}
+}
+
+// ImportCSV loads closing prices from CSV. Rows are "ticker,date,close", or
+// "date,close" when ticker is given.
+func (s *QuoteService) ImportCSV(r io.Reader, ticker string) (*models.ImportResult, error) {
+	ticker = normalizeTicker(ticker)
+
+	records, err := readCSVRecords(r)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.ImportResult{}
+	quotes := []models.Quote{}
+	for i, record := range records {
+		quote, err := parseQuoteRecord(record, ticker)
+		if err != nil {
+			// A header row is expected, anything else is reported
+			if i > 0 {
+				result.Errors = append(result.Errors, fmt.Sprintf("line %d: %v", i+1, err))
+			}
+			result.Skipped++
+			continue
+		}
+		quotes = append(quotes, *quote)
+	}
+
+	if err := s.repo.Upsert(quotes); err != nil {
+		return nil, err
+	}
+
+	result.Imported = len(quotes)
+	return result, nil
+}
+
+// ImportDir imports every CSV file in dir. Files with three columns carry the
+// ticker on each row; two-column files are named after their ticker (petr4.csv).
+func (s *QuoteService) ImportDir(dir string) (*models.ImportResult, error) {
+	files, err := csvFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	total := &models.ImportResult{}
+	for _, path := range files {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		result, err := s.ImportCSV(file, "")
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+		}
+
+		// Nothing matched the three-column layout: retry with the file name as ticker
+		if result.Imported == 0 {
+			if file, err = os.Open(path); err != nil {
+				return nil, err
+			}
+			result, err = s.ImportCSV(file, fileStem(path))
+			file.Close()
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+			}
+		}
+
+		total.Imported += result.Imported
+		total.Skipped += result.Skipped
+		for _, e := range result.Errors {
+			total.Errors = append(total.Errors, filepath.Base(path)+": "+e)
+		}
+	}
+
+	return total, nil
+}
+
+func (s *QuoteService) GetQuotes(ticker, from, to string) ([]models.Quote, error) {
+	return s.repo.FindRange(normalizeTicker(ticker), from, to)
+}
+
+func parseQuoteRecord(record []string, ticker string) (*models.Quote, error) {
+	if ticker == "" {
+		if len(record) < 3 {
+			return nil, fmt.Errorf("expected ticker, date and close")
+		}
+		ticker = normalizeTicker(record[0])
+		record = record[1:]
+	}
+	if len(record) < 2 {
+		return nil, fmt.Errorf("expected date and close")
+	}
+
+	date, err := parseImportDate(strings.TrimSpace(record[0]))
+	if err != nil {
+		return nil, err
+	}
+
+	price, err := parseDecimal(record[1])
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Quote{
+		Ticker: ticker,
+		Date:   date.Format(finance.DateLayout),
+		Close:  price,
+	}, nil
+}
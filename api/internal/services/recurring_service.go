@@ -0,0 +1,132 @@
+package services
+
+import (
+	"errors"
+	"financial-api/internal/finance"
+	"financial-api/internal/models"
+	"financial-api/internal/repositories"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrRecurringItemNotFound = errors.New("recurring item not found")
+	ErrInvalidRecurrence     = errors.New("recurrence dates must be YYYY-MM-DD with the end after the start")
+)
+
+type RecurringService struct {
+	repo *repositories.RecurringRepository
+}
+
+func NewRecurringService(repo *repositories.RecurringRepository) *RecurringService {
+	return &RecurringService{repo: repo}
+}
+
+func (s *RecurringService) CreateItem(item *models.RecurringItem, workspaceID, role string) error {
+	if err := requireRole(role, models.RoleEditor); err != nil {
+		return err
+	}
+
+	if err := validateRecurrence(item); err != nil {
+		return err
+	}
+	return s.repo.Create(item, workspaceID)
+}
+
+func (s *RecurringService) GetItems(workspaceID string) ([]models.RecurringItem, error) {
+	return s.repo.FindByWorkspace(workspaceID)
+}
+
+func (s *RecurringService) UpdateItem(id string, item *models.RecurringItem, workspaceID, role string) error {
+	if err := requireRole(role, models.RoleEditor); err != nil {
+		return err
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrRecurringItemNotFound
+	}
+	if err := validateRecurrence(item); err != nil {
+		return err
+	}
+
+	item.ID = objectID
+	if err := s.repo.Update(item, workspaceID); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return ErrRecurringItemNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *RecurringService) DeleteItem(id, workspaceID, role string) error {
+	if err := requireRole(role, models.RoleEditor); err != nil {
+		return err
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrRecurringItemNotFound
+	}
+
+	deleted, err := s.repo.Delete(objectID, workspaceID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrRecurringItemNotFound
+	}
+	return nil
+}
+
+func validateRecurrence(item *models.RecurringItem) error {
+	start, err := finance.ParseDate(item.StartDate)
+	if err != nil {
+		return ErrInvalidRecurrence
+	}
+	if item.EndDate != nil {
+		end, err := finance.ParseDate(*item.EndDate)
+		if err != nil || end.Before(start) {
+			return ErrInvalidRecurrence
+		}
+	}
+	return nil
+}
+
+// Occurrences returns the dates the item falls on within [from, to]. Monthly
+// and yearly items keep the day of the start date, or the last day of shorter
+// months.
+func Occurrences(item *models.RecurringItem, from, to time.Time) []time.Time {
+	start, err := finance.ParseDate(item.StartDate)
+	if err != nil {
+		return nil
+	}
+	if item.EndDate != nil {
+		if end, err := finance.ParseDate(*item.EndDate); err == nil && end.Before(to) {
+			to = end
+		}
+	}
+
+	dates := []time.Time{}
+	for n := 0; ; n++ {
+		var date time.Time
+		switch item.Frequency {
+		case models.FrequencyWeekly:
+			date = start.AddDate(0, 0, 7*n)
+		case models.FrequencyYearly:
+			date = finance.AddMonths(start, 12*n)
+		default:
+			date = finance.AddMonths(start, n)
+		}
+
+		if date.After(to) {
+			return dates
+		}
+		if !date.Before(from) {
+			dates = append(dates, date)
+		}
+	}
+}
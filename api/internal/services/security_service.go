@@ -0,0 +1,266 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"financial-api/internal/mailer"
+	"financial-api/internal/models"
+	"financial-api/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	// Failures of an email before each attempt waits, doubling from one second
+	delayAfterFailures = 3
+	maxLoginDelay      = 30 * time.Second
+
+	unlockTTL = 24 * time.Hour
+)
+
+// ThrottleError rejects a login attempt made too soon after the last failures
+// or while the email or IP is locked.
+type ThrottleError struct {
+	Locked     bool
+	RetryAfter time.Duration
+}
+
+func (e *ThrottleError) Error() string {
+	if e.Locked {
+		return "too many failed attempts, temporarily locked"
+	}
+	return "too many failed attempts, try again later"
+}
+
+// LoginPolicy sets when emails and IPs are locked and for how long.
+type LoginPolicy struct {
+	MaxFailures   int
+	IPMaxFailures int
+	Lockout       time.Duration
+}
+
+// SecurityService throttles failed logins per email and per IP, and wrong
+// second step codes per user, and keeps the security audit log.
+type SecurityService struct {
+	repo      *repositories.SecurityRepository
+	userRepo  *repositories.UserRepository
+	tokenRepo *repositories.AccountTokenRepository
+	mailer    mailer.Mailer
+	appURL    string
+	policy    LoginPolicy
+}
+
+func NewSecurityService(
+	repo *repositories.SecurityRepository,
+	userRepo *repositories.UserRepository,
+	tokenRepo *repositories.AccountTokenRepository,
+	sender mailer.Mailer,
+	appURL string,
+	policy LoginPolicy,
+) *SecurityService {
+	return &SecurityService{
+		repo:      repo,
+		userRepo:  userRepo,
+		tokenRepo: tokenRepo,
+		mailer:    sender,
+		appURL:    appURL,
+		policy:    policy,
+	}
+}
+
+// CheckLogin returns a ThrottleError when the email or the IP may not try to
+// log in yet.
+func (s *SecurityService) CheckLogin(email, ip string) error {
+	now := time.Now()
+	for _, key := range []string{emailKey(email), ipKey(ip)} {
+		throttle, err := s.repo.FindThrottle(key)
+		if err != nil {
+			return err
+		}
+		if throttle == nil {
+			continue
+		}
+		if throttle.LockedUntil != nil && throttle.LockedUntil.After(now) {
+			return &ThrottleError{Locked: true, RetryAfter: throttle.LockedUntil.Sub(now)}
+		}
+		if key == emailKey(email) {
+			if wait := throttle.LastFailureAt.Add(loginDelay(throttle.Failures)).Sub(now); wait > 0 {
+				return &ThrottleError{RetryAfter: wait}
+			}
+		}
+	}
+	return nil
+}
+
+// RecordFailure counts a failed login and locks the email or the IP at their
+// threshold. The user, nil for unknown emails, is mailed an unlock link.
+func (s *SecurityService) RecordFailure(email, ip string, user *models.User) error {
+	throttle, err := s.repo.AddFailure(emailKey(email), s.policy.Lockout)
+	if err != nil {
+		return err
+	}
+	if throttle.Failures == s.policy.MaxFailures {
+		if err := s.repo.Lock(emailKey(email), time.Now().Add(s.policy.Lockout)); err != nil {
+			return err
+		}
+		event := &models.SecurityEvent{Event: models.SecurityAccountLocked, Email: email, IP: ip}
+		if user != nil {
+			userID := user.ID.Hex()
+			event.UserID = &userID
+		}
+		if err := s.repo.AddEvent(event); err != nil {
+			return err
+		}
+		if user != nil {
+			if err := s.sendUnlock(user); err != nil {
+				return err
+			}
+		}
+	}
+
+	throttle, err = s.repo.AddFailure(ipKey(ip), s.policy.Lockout)
+	if err != nil {
+		return err
+	}
+	if throttle.Failures == s.policy.IPMaxFailures {
+		if err := s.repo.Lock(ipKey(ip), time.Now().Add(s.policy.Lockout)); err != nil {
+			return err
+		}
+		if err := s.repo.AddEvent(&models.SecurityEvent{Event: models.SecurityIPLocked, IP: ip}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RecordSuccess forgets the failures of the email. Those of the IP expire on
+// their own, so one valid account cannot clear them.
+func (s *SecurityService) RecordSuccess(email string) error {
+	return s.repo.ClearThrottle(emailKey(email))
+}
+
+// CheckTwoFactor returns a ThrottleError while the second factor of the user
+// is locked.
+func (s *SecurityService) CheckTwoFactor(user *models.User) error {
+	throttle, err := s.repo.FindThrottle(twoFactorKey(user.ID.Hex()))
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if throttle != nil && throttle.LockedUntil != nil && throttle.LockedUntil.After(now) {
+		return &ThrottleError{Locked: true, RetryAfter: throttle.LockedUntil.Sub(now)}
+	}
+	return nil
+}
+
+// RecordTwoFactorFailure counts a wrong second step code of the user across
+// all their challenges, since each correct password issues a new one, and
+// locks the second factor at the threshold. Logging in with the password does
+// not clear the count.
+func (s *SecurityService) RecordTwoFactorFailure(user *models.User, ip string) error {
+	key := twoFactorKey(user.ID.Hex())
+	throttle, err := s.repo.AddFailure(key, s.policy.Lockout)
+	if err != nil {
+		return err
+	}
+	if throttle.Failures != s.policy.MaxFailures {
+		return nil
+	}
+	if err := s.repo.Lock(key, time.Now().Add(s.policy.Lockout)); err != nil {
+		return err
+	}
+	return s.Audit(models.SecurityTwoFactorLocked, user, ip)
+}
+
+// RecordTwoFactorSuccess forgets the wrong codes of the user.
+func (s *SecurityService) RecordTwoFactorSuccess(user *models.User) error {
+	return s.repo.ClearThrottle(twoFactorKey(user.ID.Hex()))
+}
+
+// Unlock clears the lockout of the account whose unlock link was followed.
+func (s *SecurityService) Unlock(token, ip string) error {
+	stored, err := s.tokenRepo.Consume(hashToken(token), models.TokenAccountUnlock)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return ErrInvalidAccountToken
+		}
+		return err
+	}
+
+	user, err := s.userRepo.FindByID(stored.UserID)
+	if err != nil {
+		return err
+	}
+	return s.ClearLockout(user, ip)
+}
+
+// ClearLockout forgets the failures of the user's email, as after a password
+// reset, and records the unlock when the account was locked.
+func (s *SecurityService) ClearLockout(user *models.User, ip string) error {
+	throttle, err := s.repo.FindThrottle(emailKey(user.Email))
+	if err != nil {
+		return err
+	}
+	if throttle == nil {
+		return nil
+	}
+	if err := s.repo.ClearThrottle(emailKey(user.Email)); err != nil {
+		return err
+	}
+	if throttle.LockedUntil == nil {
+		return nil
+	}
+	return s.Audit(models.SecurityAccountUnlocked, user, ip)
+}
+
+// Audit records an event of the user.
+func (s *SecurityService) Audit(event string, user *models.User, ip string) error {
+	userID := user.ID.Hex()
+	return s.repo.AddEvent(&models.SecurityEvent{Event: event, UserID: &userID, Email: user.Email, IP: ip})
+}
+
+func (s *SecurityService) GetEvents(userID string, limit int) ([]models.SecurityEvent, error) {
+	return s.repo.FindEvents(userID, limit)
+}
+
+func (s *SecurityService) sendUnlock(user *models.User) error {
+	token, err := issueAccountToken(s.tokenRepo, user.ID.Hex(), models.TokenAccountUnlock, unlockTTL)
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "Conta bloqueada temporariamente",
+		Body: fmt.Sprintf("Olá, %s.\n\nSua conta foi bloqueada por %d minutos após várias tentativas de login com senha errada. Se foi você, desbloqueie agora pelo link abaixo:\n\n%s\n\nSe não foi você, redefina sua senha.",
+			user.Name, int(s.policy.Lockout.Minutes()), tokenLink(s.appURL, "/unlock-account", token)),
+	})
+}
+
+// loginDelay is the wait after the given number of failures: none up to
+// delayAfterFailures, then one second doubling up to maxLoginDelay.
+func loginDelay(failures int) time.Duration {
+	if failures < delayAfterFailures {
+		return 0
+	}
+	delay := time.Duration(math.Pow(2, float64(failures-delayAfterFailures))) * time.Second
+	if delay > maxLoginDelay {
+		return maxLoginDelay
+	}
+	return delay
+}
+
+func emailKey(email string) string {
+	return "email:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
+
+func twoFactorKey(userID string) string {
+	return "2fa:" + userID
+}
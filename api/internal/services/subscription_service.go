@@ -0,0 +1,240 @@
+package services
+
+import (
+	"errors"
+	"financial-api/internal/finance"
+	"financial-api/internal/models"
+	"financial-api/internal/repositories"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// Months of history scanned, enough to see a yearly charge twice
+	subscriptionHistoryMonths = 18
+	// Share of the intervals between charges that must fit the frequency
+	subscriptionRegularity = 0.75
+	// Charges of a subscription, except the latest, stay within this fraction
+	// of their median
+	subscriptionAmountTolerance = 0.25
+)
+
+var ErrSubscriptionNotFound = errors.New("subscription not detected for this merchant")
+
+// subscriptionFrequency is the range of days between charges of a frequency
+// and the charges needed to detect it.
+type subscriptionFrequency struct {
+	name       string
+	minDays    int
+	maxDays    int
+	minCharges int
+	perYear    float64
+}
+
+var subscriptionFrequencies = []subscriptionFrequency{
+	{models.FrequencyWeekly, 5, 9, 4, 52},
+	{models.FrequencyMonthly, 26, 35, 3, 12},
+	{models.FrequencyYearly, 350, 380, 2, 1},
+}
+
+type SubscriptionService struct {
+	transactionRepo  *repositories.TransactionRepository
+	recurringService *RecurringService
+}
+
+func NewSubscriptionService(transactionRepo *repositories.TransactionRepository, recurringService *RecurringService) *SubscriptionService {
+	return &SubscriptionService{transactionRepo: transactionRepo, recurringService: recurringService}
+}
+
+// GetSubscriptions scans the expenses of the workspace for charges repeating at
+// a weekly, monthly or yearly interval with a similar amount.
+func (s *SubscriptionService) GetSubscriptions(workspaceID string) ([]models.Subscription, error) {
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	from := finance.AddMonths(today, -subscriptionHistoryMonths)
+
+	expenses, err := s.transactionRepo.FindExpenses(workspaceID, from.Format(finance.DateLayout), today.Format(finance.DateLayout))
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.recurringService.GetItems(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptions := detectSubscriptions(expenses, today)
+	for i := range subscriptions {
+		for j := range items {
+			if items[j].Type == "expense" && items[j].Frequency == subscriptions[i].Frequency && strings.EqualFold(merchantDisplayName(NormalizeMerchant(items[j].Description)), subscriptions[i].Merchant) {
+				id := items[j].ID.Hex()
+				subscriptions[i].RecurringItemID = &id
+				break
+			}
+		}
+	}
+
+	return subscriptions, nil
+}
+
+// Confirm turns a detected subscription into a recurring expense starting at
+// its next expected charge. Confirming it again returns the existing item.
+func (s *SubscriptionService) Confirm(merchant, workspaceID, role string) (*models.RecurringItem, error) {
+	if err := requireRole(role, models.RoleEditor); err != nil {
+		return nil, err
+	}
+
+	subscriptions, err := s.GetSubscriptions(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, subscription := range subscriptions {
+		if !strings.EqualFold(subscription.Merchant, merchant) && !strings.EqualFold(subscription.Merchant, merchantDisplayName(NormalizeMerchant(merchant))) {
+			continue
+		}
+
+		if subscription.RecurringItemID != nil {
+			items, err := s.recurringService.GetItems(workspaceID)
+			if err != nil {
+				return nil, err
+			}
+			for i := range items {
+				if items[i].ID.Hex() == *subscription.RecurringItemID {
+					return &items[i], nil
+				}
+			}
+		}
+
+		item := &models.RecurringItem{
+			Type:        "expense",
+			Description: subscription.Description,
+			Amount:      subscription.Amount,
+			Frequency:   subscription.Frequency,
+			StartDate:   subscription.NextCharge,
+			CategoryID:  subscription.CategoryID,
+		}
+		if err := s.recurringService.CreateItem(item, workspaceID, role); err != nil {
+			return nil, err
+		}
+		return item, nil
+	}
+
+	return nil, ErrSubscriptionNotFound
+}
+
+// detectSubscriptions groups the expenses by merchant and keeps the groups
+// whose charges repeat at a regular interval with a stable amount. The most
+// expensive subscriptions come first.
+func detectSubscriptions(expenses []models.Transaction, today time.Time) []models.Subscription {
+	groups := map[string][]models.Transaction{}
+	for _, expense := range expenses {
+		if merchant := transactionMerchant(&expense); merchant != "" {
+			key := strings.ToLower(merchant)
+			groups[key] = append(groups[key], expense)
+		}
+	}
+
+	subscriptions := []models.Subscription{}
+	for _, charges := range groups {
+		sort.SliceStable(charges, func(i, j int) bool {
+			return charges[i].Date < charges[j].Date
+		})
+
+		merchant := transactionMerchant(&charges[len(charges)-1])
+		if subscription, ok := detectSubscription(merchant, charges, today); ok {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+
+	sort.Slice(subscriptions, func(i, j int) bool {
+		if subscriptions[i].AnnualCost != subscriptions[j].AnnualCost {
+			return subscriptions[i].AnnualCost > subscriptions[j].AnnualCost
+		}
+		return subscriptions[i].Merchant < subscriptions[j].Merchant
+	})
+
+	return subscriptions
+}
+
+func detectSubscription(merchant string, charges []models.Transaction, today time.Time) (models.Subscription, bool) {
+	dates := make([]time.Time, 0, len(charges))
+	amounts := make([]float64, 0, len(charges))
+	for _, charge := range charges {
+		date, err := finance.ParseDate(charge.Date)
+		if err != nil {
+			continue
+		}
+		dates = append(dates, date)
+		amounts = append(amounts, charge.Amount)
+	}
+	if len(dates) < 2 {
+		return models.Subscription{}, false
+	}
+
+	// The latest charge may differ by any amount: it is how a price increase
+	// shows up
+	earlier := amounts[:len(amounts)-1]
+	median := finance.Median(earlier)
+	for _, amount := range earlier {
+		if math.Abs(amount-median) > median*subscriptionAmountTolerance {
+			return models.Subscription{}, false
+		}
+	}
+
+	intervals := make([]float64, len(dates)-1)
+	for i := 1; i < len(dates); i++ {
+		intervals[i-1] = dates[i].Sub(dates[i-1]).Hours() / 24
+	}
+
+	for _, frequency := range subscriptionFrequencies {
+		if len(dates) < frequency.minCharges {
+			continue
+		}
+
+		regular := 0
+		for _, days := range intervals {
+			if days >= float64(frequency.minDays) && days <= float64(frequency.maxDays) {
+				regular++
+			}
+		}
+		if float64(regular) < subscriptionRegularity*float64(len(intervals)) {
+			continue
+		}
+
+		last := len(charges) - 1
+		lastDate := dates[len(dates)-1]
+		var next time.Time
+		switch frequency.name {
+		case models.FrequencyWeekly:
+			next = lastDate.AddDate(0, 0, 7)
+		case models.FrequencyYearly:
+			next = finance.AddMonths(lastDate, 12)
+		default:
+			next = finance.AddMonths(lastDate, 1)
+		}
+
+		amount := amounts[len(amounts)-1]
+		previous := amounts[len(amounts)-2]
+		return models.Subscription{
+			Merchant:       merchant,
+			Description:    charges[last].Description,
+			Frequency:      frequency.name,
+			Amount:         amount,
+			PreviousAmount: previous,
+			PriceIncrease:  amount > previous,
+			Charges:        len(dates),
+			FirstCharge:    dates[0].Format(finance.DateLayout),
+			LastCharge:     lastDate.Format(finance.DateLayout),
+			NextCharge:     next.Format(finance.DateLayout),
+			AnnualCost:     math.Round(amount*frequency.perYear*100) / 100,
+			// A subscription missing two charges in a row is considered cancelled
+			Active:     !today.After(lastDate.AddDate(0, 0, 2*frequency.maxDays)),
+			CategoryID: charges[last].CategoryID,
+		}, true
+	}
+
+	return models.Subscription{}, false
+}
@@ -1,25 +1,51 @@
 package services
 
 import (
+	"financial-api/internal/logger"
 	"financial-api/internal/models"
 	"financial-api/internal/repositories"
 	"math"
+
+	"go.uber.org/zap"
 )
 
 type TransactionService struct {
-	repo *repositories.TransactionRepository
+	repo            *repositories.TransactionRepository
+	insightService  *InsightService
+	merchantService *MerchantService
 }
 
-func NewTransactionService(repo *repositories.TransactionRepository) *TransactionService {
-	return &TransactionService{repo: repo}
+func NewTransactionService(repo *repositories.TransactionRepository, insightService *InsightService, merchantService *MerchantService) *TransactionService {
+	return &TransactionService{repo: repo, insightService: insightService, merchantService: merchantService}
 }
 
-func (s *TransactionService) CreateTransaction(transaction *models.Transaction, userID string) error {
-	return s.repo.Create(transaction, userID)
+func (s *TransactionService) CreateTransaction(transaction *models.Transaction, workspaceID, role string) error {
+	if err := requireRole(role, models.RoleEditor); err != nil {
+		return err
+	}
+
+	if err := s.merchantService.Tag(transaction, workspaceID); err != nil {
+		return err
+	}
+
+	if err := s.repo.Create(transaction, workspaceID); err != nil {
+		return err
+	}
+
+	// The transaction is saved either way; a failed check is redone by the
+	// next insight sweep
+	if err := s.insightService.CheckTransaction(transaction, workspaceID); err != nil {
+		logger.Logger.Warn("Failed to check transaction for insights",
+			zap.Error(err),
+			zap.String("transaction_id", transaction.ID.Hex()),
+			zap.String("workspace_id", workspaceID),
+		)
+	}
+	return nil
 }
 
-func (s *TransactionService) GetTransactionsPaginated(page, limit int, search, transactionType, userID string) (*models.PaginatedResponse, error) {
-	transactions, total, err := s.repo.FindPaginated(page, limit, search, transactionType, userID)
+func (s *TransactionService) GetTransactionsPaginated(page, limit int, search, transactionType, workspaceID string) (*models.PaginatedResponse, error) {
+	transactions, total, err := s.repo.FindPaginated(page, limit, search, transactionType, workspaceID)
 	if err != nil {
 		return nil, err
 	}
@@ -37,6 +63,6 @@ func (s *TransactionService) GetTransactionsPaginated(page, limit int, search, t
 	}, nil
 }
 
-func (s *TransactionService) GetTotals(userID string) (*models.Totals, error) {
-	return s.repo.GetTotals(userID)
+func (s *TransactionService) GetTotals(workspaceID string) (*models.Totals, error) {
+	return s.repo.GetTotals(workspaceID)
 }
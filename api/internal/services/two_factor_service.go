@@ -0,0 +1,204 @@
+package services
+
+import (
+	"crypto/rand"
+	"errors"
+	"strings"
+	"time"
+
+	"financial-api/internal/models"
+	"financial-api/internal/repositories"
+	"financial-api/internal/totp"
+)
+
+const (
+	recoveryCodeCount = 10
+
+	// Lowercase base32 without the easily confused 0, 1, l and o
+	recoveryCodeAlphabet = "abcdefghijkmnpqrstuvwxyz23456789"
+)
+
+var (
+	ErrTwoFactorEnabled     = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorDisabled    = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorNotEnrolled = errors.New("start the enrollment first")
+	ErrInvalidTwoFactorCode = errors.New("invalid two-factor code")
+)
+
+type TwoFactorService struct {
+	userRepo    *repositories.UserRepository
+	sessionRepo *repositories.SessionRepository
+	security    *SecurityService
+	issuer      string
+}
+
+func NewTwoFactorService(
+	userRepo *repositories.UserRepository,
+	sessionRepo *repositories.SessionRepository,
+	security *SecurityService,
+	issuer string,
+) *TwoFactorService {
+	return &TwoFactorService{userRepo: userRepo, sessionRepo: sessionRepo, security: security, issuer: issuer}
+}
+
+// Enroll starts a new enrollment. The secret only takes effect once Confirm
+// receives a code generated from it.
+func (s *TwoFactorService) Enroll(userID string) (*models.TwoFactorEnrollment, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactor.Enabled {
+		return nil, ErrTwoFactorEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.SetPendingTOTPSecret(userID, secret); err != nil {
+		return nil, err
+	}
+
+	return &models.TwoFactorEnrollment{
+		Secret:     secret,
+		OtpauthURI: totp.URI(s.issuer, user.Email, secret),
+	}, nil
+}
+
+// Confirm enables two-factor authentication with the first code and returns
+// the recovery codes, which are only shown this once.
+func (s *TwoFactorService) Confirm(userID, code string) ([]string, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactor.Enabled {
+		return nil, ErrTwoFactorEnabled
+	}
+	if user.TwoFactor.PendingSecret == "" {
+		return nil, ErrTwoFactorNotEnrolled
+	}
+
+	step, ok := totp.Validate(user.TwoFactor.PendingSecret, strings.TrimSpace(code), time.Now())
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.EnableTwoFactor(userID, user.TwoFactor.PendingSecret, step, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Disable turns two-factor authentication off after the current password
+// and a valid code.
+func (s *TwoFactorService) Disable(userID, sessionID, password, code, clientIP string) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	if err := reauthenticate(s.sessionRepo, user, sessionID, password); err != nil {
+		return err
+	}
+	if err := s.VerifyThrottled(user, code, clientIP); err != nil {
+		return err
+	}
+	return s.userRepo.DisableTwoFactor(userID)
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes after a valid code.
+func (s *TwoFactorService) RegenerateRecoveryCodes(userID, code, clientIP string) ([]string, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.VerifyThrottled(user, code, clientIP); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.SetRecoveryCodes(userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// VerifyThrottled verifies a code like Verify, counting wrong codes toward
+// the lockout of the user's second factor. Every check of a code the user
+// enters goes through it, so a stolen session cannot guess codes either.
+func (s *TwoFactorService) VerifyThrottled(user *models.User, code, clientIP string) error {
+	if err := s.security.CheckTwoFactor(user); err != nil {
+		return err
+	}
+
+	if err := s.Verify(user, code); err != nil {
+		if errors.Is(err, ErrInvalidTwoFactorCode) {
+			if recordErr := s.security.RecordTwoFactorFailure(user, clientIP); recordErr != nil {
+				return recordErr
+			}
+		}
+		return err
+	}
+
+	return s.security.RecordTwoFactorSuccess(user)
+}
+
+// Verify accepts a TOTP code not used before or an unused recovery code,
+// spending it.
+func (s *TwoFactorService) Verify(user *models.User, code string) error {
+	if !user.TwoFactor.Enabled {
+		return ErrTwoFactorDisabled
+	}
+
+	code = strings.TrimSpace(code)
+	if step, ok := totp.Validate(user.TwoFactor.Secret, code, time.Now()); ok {
+		fresh, err := s.userRepo.UseTOTPStep(user.ID.Hex(), step)
+		if err != nil {
+			return err
+		}
+		if !fresh {
+			return ErrInvalidTwoFactorCode
+		}
+		return nil
+	}
+
+	used, err := s.userRepo.UseRecoveryCode(user.ID.Hex(), hashToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+// generateRecoveryCodes returns the codes as shown to the user, xxxxx-xxxxx,
+// and their hashes.
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		random := make([]byte, 10)
+		if _, err := rand.Read(random); err != nil {
+			return nil, nil, err
+		}
+		for j, b := range random {
+			random[j] = recoveryCodeAlphabet[int(b)%len(recoveryCodeAlphabet)]
+		}
+		codes[i] = string(random[:5]) + "-" + string(random[5:])
+		hashes[i] = hashToken(normalizeRecoveryCode(codes[i]))
+	}
+	return codes, hashes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
@@ -0,0 +1,503 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"financial-api/internal/mailer"
+	"financial-api/internal/models"
+	"financial-api/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrWorkspaceNotFound    = errors.New("workspace not found")
+	ErrMemberNotFound       = errors.New("member not found")
+	ErrInvitationNotFound   = errors.New("invitation not found")
+	ErrWorkspaceRole        = errors.New("your role in this workspace does not allow this")
+	ErrPersonalWorkspace    = errors.New("a personal workspace cannot be deleted or left by its user")
+	ErrLastOwner            = errors.New("a workspace must keep at least one owner")
+	ErrAlreadyMember        = errors.New("already a member of this workspace")
+	ErrInvalidInvitation    = errors.New("invalid or expired invitation")
+	ErrInvitationEmail      = errors.New("the invitation was sent to another email")
+	ErrSharedWorkspaceOwner = errors.New("you are the only owner of a shared workspace; make another member an owner or remove them first")
+)
+
+// roleRanks orders the roles; a role allows everything a lower one does.
+var roleRanks = map[string]int{
+	models.RoleViewer: 1,
+	models.RoleEditor: 2,
+	models.RoleOwner:  3,
+}
+
+// HasRole reports whether role allows what required does.
+func HasRole(role, required string) bool {
+	return roleRanks[role] >= roleRanks[required]
+}
+
+// WorkspaceService manages workspaces, their members and invitations, and
+// resolves the workspace and role of each request.
+type WorkspaceService struct {
+	repo           *repositories.WorkspaceRepository
+	invitationRepo *repositories.InvitationRepository
+	userRepo       *repositories.UserRepository
+	userData       *repositories.UserDataRepository
+	mailer         mailer.Mailer
+	appURL         string
+	invitationTTL  time.Duration
+}
+
+func NewWorkspaceService(
+	repo *repositories.WorkspaceRepository,
+	invitationRepo *repositories.InvitationRepository,
+	userRepo *repositories.UserRepository,
+	userData *repositories.UserDataRepository,
+	sender mailer.Mailer,
+	appURL string,
+	invitationTTL time.Duration,
+) *WorkspaceService {
+	return &WorkspaceService{
+		repo:           repo,
+		invitationRepo: invitationRepo,
+		userRepo:       userRepo,
+		userData:       userData,
+		mailer:         sender,
+		appURL:         appURL,
+		invitationTTL:  invitationTTL,
+	}
+}
+
+// Resolve returns the workspace of a request, the user's personal one when
+// workspaceID is empty, and the user's role in it. Workspaces the user is not
+// a member of are reported as not found.
+func (s *WorkspaceService) Resolve(userID, workspaceID string) (string, string, error) {
+	if workspaceID == "" {
+		workspaceID = userID
+	}
+
+	member, err := s.repo.FindMember(workspaceID, userID)
+	if err == mongo.ErrNoDocuments && workspaceID == userID {
+		if err := s.ensurePersonal(userID); err != nil {
+			return "", "", err
+		}
+		return workspaceID, models.RoleOwner, nil
+	}
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return "", "", ErrWorkspaceNotFound
+		}
+		return "", "", err
+	}
+	return workspaceID, member.Role, nil
+}
+
+// GetWorkspaces returns the workspaces of the user with the user's role in
+// each, the personal one first.
+func (s *WorkspaceService) GetWorkspaces(userID string) ([]models.Workspace, error) {
+	if err := s.ensurePersonal(userID); err != nil {
+		return nil, err
+	}
+
+	memberships, err := s.repo.FindMemberships(userID)
+	if err != nil {
+		return nil, err
+	}
+	roles := make(map[string]string, len(memberships))
+	ids := make([]string, len(memberships))
+	for i, member := range memberships {
+		roles[member.WorkspaceID] = member.Role
+		ids[i] = member.WorkspaceID
+	}
+
+	workspaces, err := s.repo.FindByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range workspaces {
+		workspaces[i].Role = roles[workspaces[i].ID.Hex()]
+	}
+	return workspaces, nil
+}
+
+// CreateWorkspace creates a shared workspace owned by the user.
+func (s *WorkspaceService) CreateWorkspace(userID string, req *models.CreateWorkspaceRequest) (*models.Workspace, error) {
+	workspace := &models.Workspace{Name: req.Name}
+	if err := s.repo.Create(workspace, userID); err != nil {
+		return nil, err
+	}
+	workspace.Role = models.RoleOwner
+	return workspace, nil
+}
+
+func (s *WorkspaceService) RenameWorkspace(id, userID string, req *models.UpdateWorkspaceRequest) (*models.Workspace, error) {
+	member, err := s.authorize(id, userID, models.RoleOwner)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateName(id, req.Name); err != nil {
+		return nil, err
+	}
+
+	workspace, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	workspace.Role = member.Role
+	return workspace, nil
+}
+
+// DeleteWorkspace deletes a shared workspace with all of its records.
+func (s *WorkspaceService) DeleteWorkspace(id, userID string) error {
+	if _, err := s.authorize(id, userID, models.RoleOwner); err != nil {
+		return err
+	}
+
+	workspace, err := s.repo.FindByID(id)
+	if err != nil {
+		return err
+	}
+	if workspace.Personal {
+		return ErrPersonalWorkspace
+	}
+	return s.userData.DeleteWorkspace(id)
+}
+
+// GetMembers lists the members of the workspace with their names and
+// emails.
+func (s *WorkspaceService) GetMembers(id, userID string) ([]models.WorkspaceMember, error) {
+	if _, err := s.authorize(id, userID, models.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	members, err := s.repo.FindMembers(id)
+	if err != nil {
+		return nil, err
+	}
+	for i := range members {
+		user, err := s.userRepo.FindByID(members[i].UserID)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				continue
+			}
+			return nil, err
+		}
+		members[i].Name = user.Name
+		members[i].Email = user.Email
+	}
+	return members, nil
+}
+
+// UpdateMember changes the role of a member. Owners cannot demote the last
+// owner, nor the user of a personal workspace.
+func (s *WorkspaceService) UpdateMember(id, userID, memberID string, req *models.UpdateMemberRequest) (*models.WorkspaceMember, error) {
+	if _, err := s.authorize(id, userID, models.RoleOwner); err != nil {
+		return nil, err
+	}
+
+	member, err := s.repo.FindMember(id, memberID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrMemberNotFound
+		}
+		return nil, err
+	}
+	if member.Role == models.RoleOwner && req.Role != models.RoleOwner {
+		if err := s.checkOwnerLeaves(id, memberID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.UpdateMemberRole(id, memberID, req.Role); err != nil {
+		return nil, err
+	}
+	member.Role = req.Role
+	return member, nil
+}
+
+// RemoveMember takes a member out of the workspace. Owners can remove
+// anyone and every member can leave.
+func (s *WorkspaceService) RemoveMember(id, userID, memberID string) error {
+	required := models.RoleOwner
+	if memberID == userID {
+		required = models.RoleViewer
+	}
+	if _, err := s.authorize(id, userID, required); err != nil {
+		return err
+	}
+
+	member, err := s.repo.FindMember(id, memberID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return ErrMemberNotFound
+		}
+		return err
+	}
+	if member.Role == models.RoleOwner {
+		if err := s.checkOwnerLeaves(id, memberID); err != nil {
+			return err
+		}
+	}
+	return s.repo.RemoveMember(id, memberID)
+}
+
+// Invite mails an invitation to join the workspace with the role, replacing
+// an earlier one to the same email.
+func (s *WorkspaceService) Invite(id, userID string, req *models.InviteMemberRequest) (*models.WorkspaceInvitation, error) {
+	if _, err := s.authorize(id, userID, models.RoleOwner); err != nil {
+		return nil, err
+	}
+	workspace, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	inviter, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	email := strings.ToLower(req.Email)
+	invitee, err := s.userRepo.FindByEmail(email)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+	if invitee != nil {
+		if _, err := s.repo.FindMember(id, invitee.ID.Hex()); err == nil {
+			return nil, ErrAlreadyMember
+		} else if err != mongo.ErrNoDocuments {
+			return nil, err
+		}
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	invitation := &models.WorkspaceInvitation{
+		WorkspaceID: id,
+		Email:       email,
+		Role:        req.Role,
+		TokenHash:   hashToken(token),
+		InvitedBy:   userID,
+		ExpiresAt:   time.Now().Add(s.invitationTTL),
+	}
+	if err := s.invitationRepo.Replace(invitation); err != nil {
+		return nil, err
+	}
+
+	err = s.mailer.Send(mailer.Message{
+		To:      email,
+		Subject: "Convite para " + workspace.Name,
+		Body: fmt.Sprintf("Olá.\n\n%s convidou você para gerenciar as finanças de \"%s\" como %s. Para aceitar, acesse o link abaixo em até %s:\n\n%s\n\nSe você não esperava este convite, ignore este email.",
+			inviter.Name, workspace.Name, roleName(req.Role), formatTTL(s.invitationTTL), tokenLink(s.appURL, "/workspaces/accept", token)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return invitation, nil
+}
+
+// GetInvitations lists the pending invitations of the workspace.
+func (s *WorkspaceService) GetInvitations(id, userID string) ([]models.WorkspaceInvitation, error) {
+	if _, err := s.authorize(id, userID, models.RoleOwner); err != nil {
+		return nil, err
+	}
+	return s.invitationRepo.FindByWorkspace(id)
+}
+
+func (s *WorkspaceService) RevokeInvitation(id, userID, invitationID string) error {
+	if _, err := s.authorize(id, userID, models.RoleOwner); err != nil {
+		return err
+	}
+	if err := s.invitationRepo.Delete(invitationID, id); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return ErrInvitationNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// AcceptInvitation adds the user to the workspace of the invitation, which
+// must have been sent to the user's email.
+func (s *WorkspaceService) AcceptInvitation(userID, token string) (*models.Workspace, error) {
+	invitation, err := s.invitationRepo.FindByHash(hashToken(token))
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrInvalidInvitation
+		}
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(user.Email, invitation.Email) {
+		return nil, ErrInvitationEmail
+	}
+
+	err = s.repo.AddMember(&models.WorkspaceMember{
+		WorkspaceID: invitation.WorkspaceID,
+		UserID:      userID,
+		Role:        invitation.Role,
+	})
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrAlreadyMember
+		}
+		return nil, err
+	}
+	if err := s.invitationRepo.Delete(invitation.ID.Hex(), invitation.WorkspaceID); err != nil && err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+
+	workspace, err := s.repo.FindByID(invitation.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	workspace.Role = invitation.Role
+	return workspace, nil
+}
+
+// CheckDeletion fails when deleting the user would leave a shared workspace
+// without an owner.
+func (s *WorkspaceService) CheckDeletion(userID string) error {
+	_, err := s.soleOwned(userID)
+	return err
+}
+
+// DeleteForUser deletes the workspaces the user alone owns and uses, and
+// then the user's memberships.
+func (s *WorkspaceService) DeleteForUser(userID string) error {
+	owned, err := s.soleOwned(userID)
+	if err != nil {
+		return err
+	}
+	for _, id := range owned {
+		if err := s.userData.DeleteWorkspace(id); err != nil {
+			return err
+		}
+	}
+
+	memberships, err := s.repo.FindMemberships(userID)
+	if err != nil {
+		return err
+	}
+	for _, member := range memberships {
+		if err := s.repo.RemoveMember(member.WorkspaceID, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MemberWorkspaceIDs returns the IDs of every workspace of the user.
+func (s *WorkspaceService) MemberWorkspaceIDs(userID string) ([]string, error) {
+	memberships, err := s.repo.FindMemberships(userID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(memberships))
+	for i, member := range memberships {
+		ids[i] = member.WorkspaceID
+	}
+	return ids, nil
+}
+
+// soleOwned returns the workspaces the user is the only member of.
+// Workspaces the user is the only owner of, shared with others, are an
+// ErrSharedWorkspaceOwner.
+func (s *WorkspaceService) soleOwned(userID string) ([]string, error) {
+	memberships, err := s.repo.FindMemberships(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	owned := []string{}
+	for _, membership := range memberships {
+		if membership.Role != models.RoleOwner {
+			continue
+		}
+		members, err := s.repo.FindMembers(membership.WorkspaceID)
+		if err != nil {
+			return nil, err
+		}
+		owners := 0
+		for _, member := range members {
+			if member.Role == models.RoleOwner {
+				owners++
+			}
+		}
+		if len(members) == 1 {
+			owned = append(owned, membership.WorkspaceID)
+		} else if owners == 1 {
+			return nil, ErrSharedWorkspaceOwner
+		}
+	}
+	return owned, nil
+}
+
+// authorize returns the membership of the user, which must have at least the
+// required role. Workspaces the user is not a member of are not found.
+func (s *WorkspaceService) authorize(id, userID, required string) (*models.WorkspaceMember, error) {
+	member, err := s.repo.FindMember(id, userID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrWorkspaceNotFound
+		}
+		return nil, err
+	}
+	if err := requireRole(member.Role, required); err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+// requireRole fails with ErrWorkspaceRole unless role allows what required
+// does. Services that change workspace data check the caller's role with it,
+// so viewers cannot write whichever way the service is reached.
+func requireRole(role, required string) error {
+	if !HasRole(role, required) {
+		return ErrWorkspaceRole
+	}
+	return nil
+}
+
+// checkOwnerLeaves fails when the owner leaving, or losing the role, is the
+// last owner or the user of a personal workspace.
+func (s *WorkspaceService) checkOwnerLeaves(id, ownerID string) error {
+	if id == ownerID {
+		return ErrPersonalWorkspace
+	}
+	owners, err := s.repo.CountOwners(id)
+	if err != nil {
+		return err
+	}
+	if owners <= 1 {
+		return ErrLastOwner
+	}
+	return nil
+}
+
+func (s *WorkspaceService) ensurePersonal(userID string) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	return s.repo.EnsurePersonal(userID, user.Name)
+}
+
+// roleName writes a role in Portuguese, for emails.
+func roleName(role string) string {
+	switch role {
+	case models.RoleOwner:
+		return "proprietário"
+	case models.RoleEditor:
+		return "editor"
+	default:
+		return "leitor"
+	}
+}
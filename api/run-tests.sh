@@ -12,6 +12,7 @@ NC='\033[0m' # No Color
 # Configuration
 TEST_TIMEOUT=300s
 API_URL="http://localhost:8081"
+MOCK_OIDC_URL="http://localhost:9091"
 COMPOSE_FILE="docker-compose.test.yml"
 
 echo -e "${BLUE}🚀 Starting Financial API Test Suite${NC}"
@@ -25,7 +26,7 @@ cleanup() {
     docker compose -f $COMPOSE_FILE down -v 2>/dev/null || true
     
     # Remove only our specific containers if they exist
-    docker rm -f financial-api-test financial-mongodb-test 2>/dev/null || true
+    docker rm -f financial-api-test financial-mongodb-test financial-mock-oidc-test 2>/dev/null || true
     
     # Remove only our specific network
     docker network rm financial-test-network 2>/dev/null || true
@@ -65,6 +66,7 @@ run_tests() {
 
     # Set test environment variables
     export FINANCIAL_API_URL=$API_URL
+    export FINANCIAL_API_MOCK_OIDC_URL=$MOCK_OIDC_URL
 
     # Run tests with timeout and verbose output
     if timeout $TEST_TIMEOUT go test -v -race -count=1 ./tests/integration/... -timeout=$TEST_TIMEOUT; then
@@ -0,0 +1,138 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+)
+
+const (
+	// Endpoints
+	forgotPasswordEndpoint = "/api/auth/password/forgot"
+	resetPasswordEndpoint = "/api/auth/password/reset"
+	verifyEmailEndpoint = "/api/auth/email/verify"
+	resendVerificationEndpoint = "/api/auth/email/resend"
+
+	// Test data
+	resetEmail = "reset@example.com"
+	resetUserName = "Reset User"
+	verifyEmail = "verify@example.com"
+	verifyUserName = "Verify User"
+	newPassword = "newpassword123"
+)
+
+var mailedTokenPattern = regexp.MustCompile(`token=([A-Za-z0-9_-]+)`)
+
+// mailedToken reads the token of the latest email sent to the address by the
+// file mailer. FINANCIAL_API_OUTBOX must point at the API's MAIL_OUTBOX_DIR.
+func mailedToken(t *testing.T, email string) string {
+	dir := os.Getenv("FINANCIAL_API_OUTBOX")
+	if dir == "" {
+		t.Skip("FINANCIAL_API_OUTBOX not set")
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Failed to read outbox: %v", err)
+	}
+	suffix := strings.ReplaceAll(email, "@", "_at_") + ".eml"
+	names := []string{}
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), suffix) {
+			names = append(names, entry.Name())
+		}
+	}
+	if len(names) == 0 {
+		t.Fatalf("Expected an email to %s", email)
+	}
+	sort.Strings(names)
+
+	content, err := os.ReadFile(filepath.Join(dir, names[len(names)-1]))
+	if err != nil {
+		t.Fatalf("Failed to read email: %v", err)
+	}
+	match := mailedTokenPattern.FindSubmatch(content)
+	if match == nil {
+		t.Fatalf("Expected a token in the email to %s", email)
+	}
+	return string(match[1])
+}
+
+func postStatus(t *testing.T, path string, body map[string]any) int {
+	resp, err := makeRequest("POST", path, body)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestAccountTokenValidation(t *testing.T) {
+	if got := postStatus(t, forgotPasswordEndpoint, map[string]any{"email": nonExistentEmail}); got != http.StatusAccepted {
+		t.Errorf("Expected status 202 for an unknown email, got %d", got)
+	}
+	if got := postStatus(t, resendVerificationEndpoint, map[string]any{"email": nonExistentEmail}); got != http.StatusAccepted {
+		t.Errorf("Expected status 202 for an unknown email, got %d", got)
+	}
+	if got := postStatus(t, resetPasswordEndpoint, map[string]any{"token": randomToken, "password": newPassword}); got != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an unknown reset token, got %d", got)
+	}
+	if got := postStatus(t, verifyEmailEndpoint, map[string]any{"token": randomToken}); got != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an unknown verification token, got %d", got)
+	}
+	if got := postStatus(t, resetPasswordEndpoint, map[string]any{"token": randomToken, "password": shortPassword}); got != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for a short password, got %d", got)
+	}
+}
+
+func TestPasswordReset(t *testing.T) {
+	session := registerForTokens(t, resetEmail, resetUserName)
+
+	if got := postStatus(t, forgotPasswordEndpoint, map[string]any{"email": resetEmail}); got != http.StatusAccepted {
+		t.Fatalf("Expected status 202, got %d", got)
+	}
+	token := mailedToken(t, resetEmail)
+
+	if got := postStatus(t, resetPasswordEndpoint, map[string]any{"token": token, "password": newPassword}); got != http.StatusNoContent {
+		t.Fatalf("Expected status 204, got %d", got)
+	}
+	if got := meStatus(t, session.Token); got != http.StatusUnauthorized {
+		t.Errorf("Expected existing sessions to be revoked, got %d", got)
+	}
+	if got := postStatus(t, loginEndpoint, map[string]any{"email": resetEmail, "password": newPassword}); got != http.StatusOK {
+		t.Errorf("Expected login with the new password, got %d", got)
+	}
+	if got := postStatus(t, resetPasswordEndpoint, map[string]any{"token": token, "password": testPassword}); got != http.StatusBadRequest {
+		t.Errorf("Expected the token to be single-use, got %d", got)
+	}
+}
+
+func TestEmailVerification(t *testing.T) {
+	session := registerForTokens(t, verifyEmail, verifyUserName)
+	token := mailedToken(t, verifyEmail)
+
+	if got := postStatus(t, verifyEmailEndpoint, map[string]any{"token": token}); got != http.StatusNoContent {
+		t.Fatalf("Expected status 204, got %d", got)
+	}
+
+	resp, err := makeRequestWithAuth("GET", meEndpoint, nil, session.Token)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	defer resp.Body.Close()
+
+	var user struct {
+		EmailVerifiedAt *string `json:"emailVerifiedAt"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		t.Fatalf(failedDecodeMsg, err)
+	}
+	if user.EmailVerifiedAt == nil {
+		t.Error("Expected the email to be verified")
+	}
+}
@@ -0,0 +1,140 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"testing"
+)
+
+const (
+	// Endpoints
+	allocationTargetsEndpoint = "/api/allocation/targets"
+	rebalanceEndpoint = "/api/allocation/rebalance"
+
+	// Test data
+	allocationUserEmail = "allocation@test.com"
+	allocationUserName = "Allocation User"
+	groupByTypeValue = "type"
+	rebalanceFullMode = "full"
+
+	allocationInvestment = 1000.0 // single CDB holding
+	halfTarget = 50.0
+	allocationContribution = 1000.0
+	allocationTolerance = 5.0
+)
+
+type RebalanceReport struct {
+	Mode        string  `json:"mode"`
+	TotalValue  float64 `json:"totalValue"`
+	Unallocated float64 `json:"unallocated"`
+	Balanced    bool    `json:"balanced"`
+	Items       []struct {
+		Name      string  `json:"name"`
+		Drift     float64 `json:"drift"`
+		Suggested float64 `json:"suggested"`
+	} `json:"items"`
+}
+
+func getRebalance(t *testing.T, token, query string) RebalanceReport {
+	resp, err := makeRequestWithAuth("GET", rebalanceEndpoint+"?"+query, nil, token)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+
+	var report RebalanceReport
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		t.Fatalf(failedDecodeMsg, err)
+	}
+	return report
+}
+
+func suggestedFor(report RebalanceReport, name string) float64 {
+	for _, item := range report.Items {
+		if item.Name == name {
+			return item.Suggested
+		}
+	}
+	return 0
+}
+
+func TestAllocationRebalance(t *testing.T) {
+	token, err := createAuthenticatedUser(allocationUserEmail, allocationUserName)
+	if err != nil {
+		t.Fatalf(failedCreateUserMsg, err)
+	}
+
+	resp, err := makeRequestWithAuth("GET", rebalanceEndpoint, nil, token)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected status 404 without targets, got %d", resp.StatusCode)
+	}
+
+	createTestInvestment(t, token, allocationInvestment)
+
+	t.Run("Targets must add up to 100", func(t *testing.T) {
+		resp, err := makeRequestWithAuth("PUT", allocationTargetsEndpoint, map[string]interface{}{
+			"groupBy": groupByTypeValue,
+			"targets": []map[string]interface{}{{"name": cdbType, "percentage": halfTarget}},
+		}, token)
+		if err != nil {
+			t.Fatalf(failedRequestMsg, err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", resp.StatusCode)
+		}
+	})
+
+	resp, err = makeRequestWithAuth("PUT", allocationTargetsEndpoint, map[string]interface{}{
+		"groupBy": groupByTypeValue,
+		"targets": []map[string]interface{}{
+			{"name": cdbType, "percentage": halfTarget},
+			{"name": lciType, "percentage": halfTarget},
+		},
+		"tolerance": allocationTolerance,
+	}, token)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+
+	t.Run("Contribution goes to the underweight group", func(t *testing.T) {
+		report := getRebalance(t, token, fmt.Sprintf("contribution=%.2f", allocationContribution))
+
+		if report.Balanced {
+			t.Error("Expected portfolio to be out of balance")
+		}
+		if got := suggestedFor(report, lciType); math.Abs(got-allocationContribution) > 0.01 {
+			t.Errorf("Expected LCI buy of %f, got %f", allocationContribution, got)
+		}
+		if got := suggestedFor(report, cdbType); got != 0 {
+			t.Errorf("Expected no CDB trade, got %f", got)
+		}
+	})
+
+	t.Run("Full rebalance sells the overweight group", func(t *testing.T) {
+		report := getRebalance(t, token, "mode="+rebalanceFullMode)
+
+		half := allocationInvestment / 2
+		if got := suggestedFor(report, cdbType); math.Abs(got+half) > 0.01 {
+			t.Errorf("Expected CDB sell of %f, got %f", half, got)
+		}
+		if got := suggestedFor(report, lciType); math.Abs(got-half) > 0.01 {
+			t.Errorf("Expected LCI buy of %f, got %f", half, got)
+		}
+	})
+}
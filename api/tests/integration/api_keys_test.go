@@ -0,0 +1,100 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+)
+
+const (
+	// Endpoints
+	apiKeysEndpoint = "/api/auth/api-keys"
+
+	// Test data
+	apiKeyUserEmail = "apikeys@test.com"
+	apiKeyUserName = "API Key User"
+	apiKeyName = "Import script"
+	apiKeyDescription = "Mercado Central"
+)
+
+type APIKey struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Prefix     string   `json:"prefix"`
+	Key        string   `json:"key"`
+	Scopes     []string `json:"scopes"`
+	ExpiresAt  *string  `json:"expiresAt"`
+	LastUsedAt *string  `json:"lastUsedAt"`
+}
+
+func TestAPIKeys(t *testing.T) {
+	token, err := createAuthenticatedUser(apiKeyUserEmail, apiKeyUserName)
+	if err != nil {
+		t.Fatalf(failedRegisterMsg, err)
+	}
+
+	var key APIKey
+	body := map[string]any{"name": apiKeyName, "scopes": []string{"transactions:read", "transactions:write"}, "expiresInDays": 30}
+	if status := decodeInto(t, "POST", apiKeysEndpoint, body, token, &key); status != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d", status)
+	}
+	if key.Key == "" || key.ExpiresAt == nil {
+		t.Fatalf("Expected the key and its expiry, got %+v", key)
+	}
+
+	t.Run("Invalid scope", func(t *testing.T) {
+		body := map[string]any{"name": apiKeyName, "scopes": []string{"everything"}}
+		if status := decodeInto(t, "POST", apiKeysEndpoint, body, token, nil); status != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", status)
+		}
+	})
+
+	t.Run("Scoped access", func(t *testing.T) {
+		body := map[string]any{"type": expenseType, "description": apiKeyDescription, "amount": 30.0, "date": testDate}
+		if status := decodeInto(t, "POST", transactionsEndpoint, body, key.Key, nil); status != http.StatusCreated {
+			t.Errorf("Expected status 201 creating a transaction, got %d", status)
+		}
+		if status := decodeInto(t, "GET", transactionsEndpoint, nil, key.Key, nil); status != http.StatusOK {
+			t.Errorf("Expected status 200 listing transactions, got %d", status)
+		}
+		if status := decodeInto(t, "GET", investmentsEndpoint, nil, key.Key, nil); status != http.StatusForbidden {
+			t.Errorf("Expected status 403 without the investments:read scope, got %d", status)
+		}
+	})
+
+	t.Run("Account routes", func(t *testing.T) {
+		if status := decodeInto(t, "GET", apiKeysEndpoint, nil, key.Key, nil); status != http.StatusForbidden {
+			t.Errorf("Expected status 403 managing keys with a key, got %d", status)
+		}
+		if status := decodeInto(t, "POST", exportsEndpoint, nil, key.Key, nil); status != http.StatusForbidden {
+			t.Errorf("Expected status 403 exporting data with a key, got %d", status)
+		}
+	})
+
+	t.Run("List", func(t *testing.T) {
+		var keys []APIKey
+		if status := decodeInto(t, "GET", apiKeysEndpoint, nil, token, &keys); status != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", status)
+		}
+		if len(keys) != 1 || keys[0].ID != key.ID {
+			t.Fatalf("Expected only key %s, got %+v", key.ID, keys)
+		}
+		if keys[0].Key != "" {
+			t.Error("Expected the key to be hidden after creation")
+		}
+		if keys[0].LastUsedAt == nil {
+			t.Error("Expected the last use to be recorded")
+		}
+	})
+
+	t.Run("Revoke", func(t *testing.T) {
+		if status := decodeInto(t, "DELETE", apiKeysEndpoint+"/"+key.ID, nil, token, nil); status != http.StatusNoContent {
+			t.Fatalf("Expected status 204, got %d", status)
+		}
+		if status := decodeInto(t, "GET", transactionsEndpoint, nil, key.Key, nil); status != http.StatusUnauthorized {
+			t.Errorf("Expected status 401 with a revoked key, got %d", status)
+		}
+		if status := decodeInto(t, "DELETE", apiKeysEndpoint+"/"+key.ID, nil, token, nil); status != http.StatusNotFound {
+			t.Errorf("Expected status 404 revoking twice, got %d", status)
+		}
+	})
+}
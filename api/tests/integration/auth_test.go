@@ -1,8 +1,10 @@
 package integration
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"net/http"
+	"strings"
 	"testing"
 )
 
@@ -12,6 +14,9 @@ const (
 	registerEndpoint = "/api/auth/register"
 	loginEndpoint = "/api/auth/login"
 	meEndpoint = "/api/auth/me"
+	refreshEndpoint = "/api/auth/refresh"
+	logoutEndpoint = "/api/auth/logout"
+	logoutAllEndpoint = "/api/auth/logout-all"
 	failedRequestMsg = "Failed to make request: %v"
 	failedDecodeMsg = "Failed to decode response: %v"
 	failedRegisterMsg = "Failed to register user: %v"
@@ -35,6 +40,15 @@ const (
 	randomToken = "randomstring123"
 	emptyString = ""
 	testString = "Test"
+	refreshEmail = "refresh@example.com"
+	refreshUserName = "Refresh User"
+	logoutEmail = "logout@example.com"
+	logoutUserName = "Logout User"
+	logoutAllEmail = "logoutall@example.com"
+	logoutAllUserName = "Logout All User"
+	jwksEndpoint = "/.well-known/jwks.json"
+	claimsEmail = "claims@example.com"
+	claimsUserName = "Claims User"
 )
 
 func TestUserRegistration(t *testing.T) {
@@ -422,3 +436,216 @@ func TestLoginFieldValidation(t *testing.T) {
 		})
 	}
 }
+
+func registerForTokens(t *testing.T, email, name string) AuthResponse {
+	resp, err := makeRequest("POST", registerEndpoint, map[string]any{
+		"email": email, "password": testPassword, "name": name,
+	})
+	if err != nil {
+		t.Fatalf(failedRegisterMsg, err)
+	}
+	defer resp.Body.Close()
+
+	var authResp AuthResponse
+	if err := json.NewDecoder(resp.Body).Decode(&authResp); err != nil {
+		t.Fatalf(failedDecodeMsg, err)
+	}
+	return authResp
+}
+
+func loginForTokens(t *testing.T, email string) AuthResponse {
+	resp, err := makeRequest("POST", loginEndpoint, map[string]any{"email": email, "password": testPassword})
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	defer resp.Body.Close()
+
+	var authResp AuthResponse
+	if err := json.NewDecoder(resp.Body).Decode(&authResp); err != nil {
+		t.Fatalf(failedDecodeMsg, err)
+	}
+	return authResp
+}
+
+func refreshTokens(t *testing.T, refreshToken string) (AuthResponse, int) {
+	resp, err := makeRequest("POST", refreshEndpoint, map[string]any{"refreshToken": refreshToken})
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	defer resp.Body.Close()
+
+	var authResp AuthResponse
+	if resp.StatusCode == http.StatusOK {
+		if err := json.NewDecoder(resp.Body).Decode(&authResp); err != nil {
+			t.Fatalf(failedDecodeMsg, err)
+		}
+	}
+	return authResp, resp.StatusCode
+}
+
+func meStatus(t *testing.T, token string) int {
+	resp, err := makeRequestWithAuth("GET", meEndpoint, nil, token)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+	first := registerForTokens(t, refreshEmail, refreshUserName)
+	if first.RefreshToken == "" {
+		t.Fatal("Expected a refresh token on registration")
+	}
+
+	second, status := refreshTokens(t, first.RefreshToken)
+	if status != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", status)
+	}
+	if second.RefreshToken == first.RefreshToken {
+		t.Error("Expected the refresh token to rotate")
+	}
+	if got := meStatus(t, second.Token); got != http.StatusOK {
+		t.Errorf("Expected the new access token to work, got %d", got)
+	}
+
+	t.Run("Reuse revokes the family", func(t *testing.T) {
+		if _, status := refreshTokens(t, first.RefreshToken); status != http.StatusUnauthorized {
+			t.Errorf("Expected status 401 on reuse, got %d", status)
+		}
+		if _, status := refreshTokens(t, second.RefreshToken); status != http.StatusUnauthorized {
+			t.Errorf("Expected the rotated token to be revoked, got %d", status)
+		}
+		if got := meStatus(t, second.Token); got != http.StatusUnauthorized {
+			t.Errorf("Expected the access token to be rejected, got %d", got)
+		}
+	})
+
+	t.Run("Unknown token", func(t *testing.T) {
+		if _, status := refreshTokens(t, randomToken); status != http.StatusUnauthorized {
+			t.Errorf("Expected status 401, got %d", status)
+		}
+	})
+}
+
+func TestLogout(t *testing.T) {
+	authResp := registerForTokens(t, logoutEmail, logoutUserName)
+
+	resp, err := makeRequestWithAuth("POST", logoutEndpoint, nil, authResp.Token)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("Expected status 204, got %d", resp.StatusCode)
+	}
+	if got := meStatus(t, authResp.Token); got != http.StatusUnauthorized {
+		t.Errorf("Expected the access token to be rejected after logout, got %d", got)
+	}
+	if _, status := refreshTokens(t, authResp.RefreshToken); status != http.StatusUnauthorized {
+		t.Errorf("Expected the refresh token to be rejected after logout, got %d", status)
+	}
+}
+
+func TestLogoutEverywhere(t *testing.T) {
+	web := registerForTokens(t, logoutAllEmail, logoutAllUserName)
+	mobile := loginForTokens(t, logoutAllEmail)
+
+	var key APIKey
+	body := map[string]any{"name": apiKeyName, "scopes": []string{"transactions:read"}}
+	if status := decodeInto(t, "POST", apiKeysEndpoint, body, web.Token, &key); status != http.StatusCreated {
+		t.Fatalf("Expected status 201 creating an API key, got %d", status)
+	}
+
+	resp, err := makeRequestWithAuth("POST", logoutAllEndpoint, nil, mobile.Token)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("Expected status 204, got %d", resp.StatusCode)
+	}
+	for _, session := range []AuthResponse{web, mobile} {
+		if got := meStatus(t, session.Token); got != http.StatusUnauthorized {
+			t.Errorf("Expected every session to be revoked, got %d", got)
+		}
+	}
+	if status := decodeInto(t, "GET", transactionsEndpoint, nil, key.Key, nil); status != http.StatusUnauthorized {
+		t.Errorf("Expected the API key to be revoked, got %d", status)
+	}
+}
+
+// tokenPart decodes the header or the payload of a JWT.
+func tokenPart(t *testing.T, token string, part int) map[string]any {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Fatalf("Expected a JWT with 3 parts, got %d", len(parts))
+	}
+	data, err := base64.RawURLEncoding.DecodeString(parts[part])
+	if err != nil {
+		t.Fatalf("Failed to decode token: %v", err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf(failedDecodeMsg, err)
+	}
+	return decoded
+}
+
+func TestAccessTokenClaims(t *testing.T) {
+	authResp := registerForTokens(t, claimsEmail, claimsUserName)
+	header := tokenPart(t, authResp.Token, 0)
+	claims := tokenPart(t, authResp.Token, 1)
+
+	for _, claim := range []string{"iss", "aud", "exp", "iat", "sid"} {
+		if _, ok := claims[claim]; !ok {
+			t.Errorf("Expected the %s claim in the access token", claim)
+		}
+	}
+
+	t.Run("Unsigned token", func(t *testing.T) {
+		parts := strings.Split(authResp.Token, ".")
+		none := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+		if got := meStatus(t, none+"."+parts[1]+"."); got != http.StatusUnauthorized {
+			t.Errorf("Expected status 401 for alg none, got %d", got)
+		}
+	})
+
+	t.Run("JWKS", func(t *testing.T) {
+		resp, err := makeRequest("GET", jwksEndpoint, nil)
+		if err != nil {
+			t.Fatalf(failedRequestMsg, err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+		}
+		var jwks struct {
+			Keys []struct {
+				Kid string `json:"kid"`
+				Kty string `json:"kty"`
+			} `json:"keys"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&jwks); err != nil {
+			t.Fatalf(failedDecodeMsg, err)
+		}
+
+		// Shared secret setups publish no keys and sign without a kid
+		kid, ok := header["kid"].(string)
+		if !ok {
+			if header["alg"] != "HS256" || len(jwks.Keys) != 0 {
+				t.Errorf("Expected an HS256 token and no keys without a kid, got %v and %d keys", header["alg"], len(jwks.Keys))
+			}
+			return
+		}
+		for _, key := range jwks.Keys {
+			if key.Kid == kid {
+				return
+			}
+		}
+		t.Errorf("Expected key %s in the JWKS", kid)
+	})
+}
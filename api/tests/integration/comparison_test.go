@@ -0,0 +1,129 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+const (
+	// Endpoints
+	comparisonEndpoint = "/api/reports/comparison"
+
+	// Test data
+	comparisonUserEmail = "comparison@test.com"
+	comparisonUserName = "Comparison User"
+	foodCategoryName = "Alimentação"
+	leisureCategoryName = "Lazer"
+	comparisonMonth = "2024-10"
+	previousMonthDate = "2024-09-15"
+	currentMonthDate = "2024-10-15"
+
+	previousFood = 100.0
+	currentFood = 123.0
+	previousLeisure = 50.0
+	expectedFoodIncrease = 23.0 // percent
+)
+
+type ComparisonReport struct {
+	Current struct {
+		From string `json:"from"`
+		To   string `json:"to"`
+	} `json:"current"`
+	Expenses struct {
+		Current  float64 `json:"current"`
+		Previous float64 `json:"previous"`
+	} `json:"expenses"`
+	Increases []CategoryDelta `json:"increases"`
+	Decreases []CategoryDelta `json:"decreases"`
+}
+
+type CategoryDelta struct {
+	Name          string   `json:"name"`
+	Change        float64  `json:"change"`
+	ChangePercent *float64 `json:"changePercent"`
+}
+
+func categoryIDs(t *testing.T, token string) map[string]string {
+	resp, err := makeRequestWithAuth("GET", categoriesEndpoint, nil, token)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	defer resp.Body.Close()
+
+	var categories []Category
+	if err := json.NewDecoder(resp.Body).Decode(&categories); err != nil {
+		t.Fatalf(failedDecodeMsg, err)
+	}
+
+	ids := map[string]string{}
+	for _, category := range categories {
+		ids[category.Name] = category.ID
+	}
+	return ids
+}
+
+func TestComparisonReport(t *testing.T) {
+	token, err := createAuthenticatedUser(comparisonUserEmail, comparisonUserName)
+	if err != nil {
+		t.Fatalf(failedCreateUserMsg, err)
+	}
+
+	ids := categoryIDs(t, token)
+	for _, payload := range []map[string]interface{}{
+		{"type": expenseType, "description": foodCategoryName, "amount": previousFood, "date": previousMonthDate, "categoryId": ids[foodCategoryName]},
+		{"type": expenseType, "description": foodCategoryName, "amount": currentFood, "date": currentMonthDate, "categoryId": ids[foodCategoryName]},
+		{"type": expenseType, "description": leisureCategoryName, "amount": previousLeisure, "date": previousMonthDate, "categoryId": ids[leisureCategoryName]},
+	} {
+		resp, err := makeRequestWithAuth("POST", transactionsEndpoint, payload, token)
+		if err != nil {
+			t.Fatalf(failedRequestMsg, err)
+		}
+		resp.Body.Close()
+	}
+
+	t.Run("Month over month", func(t *testing.T) {
+		resp, err := makeRequestWithAuth("GET", comparisonEndpoint+"?mode=mom&month="+comparisonMonth, nil, token)
+		if err != nil {
+			t.Fatalf(failedRequestMsg, err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+		}
+
+		var report ComparisonReport
+		if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+			t.Fatalf(failedDecodeMsg, err)
+		}
+
+		if report.Current.From != "2024-10-01" || report.Current.To != "2024-10-31" {
+			t.Errorf("Expected October as the current period, got %+v", report.Current)
+		}
+		if report.Expenses.Current != currentFood || report.Expenses.Previous != previousFood+previousLeisure {
+			t.Errorf("Unexpected expenses %+v", report.Expenses)
+		}
+		if len(report.Increases) != 1 || report.Increases[0].Name != foodCategoryName {
+			t.Fatalf("Expected %s as the only increase, got %+v", foodCategoryName, report.Increases)
+		}
+		if percent := report.Increases[0].ChangePercent; percent == nil || *percent != expectedFoodIncrease {
+			t.Errorf("Expected %s up %f%%, got %v", foodCategoryName, expectedFoodIncrease, percent)
+		}
+		if len(report.Decreases) != 1 || report.Decreases[0].Change != -previousLeisure {
+			t.Errorf("Expected %s down %f, got %+v", leisureCategoryName, previousLeisure, report.Decreases)
+		}
+	})
+
+	t.Run("Invalid mode", func(t *testing.T) {
+		resp, err := makeRequestWithAuth("GET", comparisonEndpoint+"?mode=weekly", nil, token)
+		if err != nil {
+			t.Fatalf(failedRequestMsg, err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", resp.StatusCode)
+		}
+	})
+}
@@ -15,6 +15,8 @@ const (
 	dashboardUserName = "Dashboard User"
 	emptyDashboardUserEmail = "emptydash@test.com"
 	emptyDashboardUserName = "Empty Dashboard User"
+	indexedDashboardUserEmail = "indexeddash@test.com"
+	indexedDashboardUserName = "Indexed Dashboard User"
 
 	// Transaction descriptions
 	salaryDesc = "Salary"
@@ -50,6 +52,9 @@ type Totals struct {
 	TotalInvestments    float64 `json:"totalInvestments"`
 	TotalMonthlyReturn  float64 `json:"totalMonthlyReturn"`
 	AverageRate         float64 `json:"averageRate"`
+	TotalGrossValue     float64 `json:"totalGrossValue"`
+	TotalTaxDue         float64 `json:"totalTaxDue"`
+	TotalNetValue       float64 `json:"totalNetValue"`
 }
 
 type Category struct {
@@ -308,7 +313,7 @@ func TestDashboardSummaryInvestmentCalculations(t *testing.T) {
 		{"Investment C", 15000.0, 80.0},
 	}
 
-	var totalAmount, totalMonthlyReturn, totalRate float64
+	var totalAmount, totalMonthlyReturn, weightedRate float64
 	for _, inv := range testInvestments {
 		payload := map[string]interface{}{
 			"name":   inv.name,
@@ -325,10 +330,11 @@ func TestDashboardSummaryInvestmentCalculations(t *testing.T) {
 
 		totalAmount += inv.amount
 		totalMonthlyReturn += (inv.amount * (inv.rate / 100)) / 12
-		totalRate += inv.rate
+		weightedRate += inv.amount * inv.rate
 	}
 
-	expectedAverageRate := totalRate / float64(len(testInvestments))
+	// Average rate is weighted by position size
+	expectedAverageRate := weightedRate / totalAmount
 
 	resp, err := makeRequestWithAuth("GET", dashboardSummaryEndpoint, nil, token)
 	if err != nil {
@@ -353,3 +359,44 @@ func TestDashboardSummaryInvestmentCalculations(t *testing.T) {
 		t.Errorf("Expected average rate %f, got %f", expectedAverageRate, summary.Totals.AverageRate)
 	}
 }
+
+func TestDashboardSummaryAverageRateSkipsIndexed(t *testing.T) {
+	token, err := createAuthenticatedUser(indexedDashboardUserEmail, indexedDashboardUserName)
+	if err != nil {
+		t.Fatalf(failedCreateUserMsg, err)
+	}
+
+	fixed := map[string]interface{}{
+		"name":   "Fixed CDB",
+		"amount": 10000.0,
+		"rate":   12.0,
+		"date":   testDate,
+	}
+	// The rate of an indexed investment is a percentage of the index, not an
+	// annual rate, so it must not pull the average down
+	indexed := map[string]interface{}{
+		"name":         "Indexed CDB",
+		"amount":       30000.0,
+		"date":         testDate,
+		"indexer":      cdiIndexer,
+		"indexPercent": cdiPercent,
+	}
+	for _, payload := range []map[string]interface{}{fixed, indexed} {
+		if status := decodeInto(t, "POST", investmentsEndpoint, payload, token, nil); status != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d", status)
+		}
+	}
+
+	var summary DashboardSummary
+	if status := decodeInto(t, "GET", dashboardSummaryEndpoint, nil, token, &summary); status != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", status)
+	}
+
+	if summary.Totals.TotalInvestments != 40000.0 {
+		t.Errorf("Expected total investments %f, got %f", 40000.0, summary.Totals.TotalInvestments)
+	}
+
+	if summary.Totals.AverageRate != 12.0 {
+		t.Errorf("Expected average rate %f, got %f", 12.0, summary.Totals.AverageRate)
+	}
+}
@@ -0,0 +1,215 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+const (
+	// Endpoints
+	dividendsEndpoint = "/api/dividends"
+	dividendReportEndpoint = "/api/dividends/report"
+
+	// Test data
+	dividendUserEmail = "dividends@test.com"
+	dividendUserName = "Dividend User"
+	noPositionUserEmail = "nopositiondividend@test.com"
+	noPositionUserName = "No Position Dividend User"
+	currentMonthUserEmail = "currentmonthdividend@test.com"
+	currentMonthUserName = "Current Month Dividend User"
+
+	dividendTicker = "TSTD11"
+	jcpKind = "jcp"
+	rendimentoKind = "rendimento"
+	dividendBuyDate = "2024-01-02"
+	dividendPayDate = "2024-02-15"
+	dividendReportDate = "2024-06-30"
+	dividendMonth = "2024-02"
+	midMonthReportDate = "2024-06-15"
+	currentMonthPayDate = "2024-06-10"
+	firstReportMonth = "2023-07"
+	currentReportMonth = "2024-06"
+
+	jcpGross = 100.0
+	jcpExpectedNet = 85.0 // 15% withheld at source
+	rendimentoGross = 50.0
+	dividendPositionValue = 1000.0 // 100 units @ 10
+	expectedDividendYield = 15.0 // (100 + 50) / 1000
+)
+
+type Dividend struct {
+	ID             string  `json:"id"`
+	Ticker         string  `json:"ticker"`
+	Kind           string  `json:"kind"`
+	GrossAmount    float64 `json:"grossAmount"`
+	WithholdingTax float64 `json:"withholdingTax"`
+	NetAmount      float64 `json:"netAmount"`
+	PayDate        string  `json:"payDate"`
+}
+
+type DividendReport struct {
+	TotalGross     float64 `json:"totalGross"`
+	TotalNet       float64 `json:"totalNet"`
+	PortfolioYield float64 `json:"portfolioYield"`
+	Assets         []struct {
+		Ticker string  `json:"ticker"`
+		Yield  float64 `json:"yield"`
+	} `json:"assets"`
+	Months []struct {
+		Month string  `json:"month"`
+		Gross float64 `json:"gross"`
+		Net   float64 `json:"net"`
+	} `json:"months"`
+}
+
+func TestDividendsAndReport(t *testing.T) {
+	token, err := createAuthenticatedUser(dividendUserEmail, dividendUserName)
+	if err != nil {
+		t.Fatalf(failedCreateUserMsg, err)
+	}
+
+	createTrade(t, token, map[string]interface{}{
+		"ticker": dividendTicker, "assetClass": fiiClass, "side": buySide, "quantity": buyQuantity, "price": firstBuyPrice, "date": dividendBuyDate,
+	})
+
+	resp, err := makeRequestWithAuth("POST", dividendsEndpoint, map[string]interface{}{
+		"ticker": dividendTicker, "kind": jcpKind, "grossAmount": jcpGross, "payDate": dividendPayDate,
+	}, token)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d", resp.StatusCode)
+	}
+
+	var dividend Dividend
+	if err := json.NewDecoder(resp.Body).Decode(&dividend); err != nil {
+		t.Fatalf(failedDecodeMsg, err)
+	}
+
+	if dividend.NetAmount != jcpExpectedNet {
+		t.Errorf("Expected JCP net amount %f, got %f", jcpExpectedNet, dividend.NetAmount)
+	}
+
+	resp2, err := makeRequestWithAuth("POST", dividendsEndpoint, map[string]interface{}{
+		"ticker": dividendTicker, "kind": rendimentoKind, "grossAmount": rendimentoGross, "payDate": dividendPayDate,
+	}, token)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	resp2.Body.Close()
+
+	reportResp, err := makeRequestWithAuth("GET", dividendReportEndpoint+"?date="+dividendReportDate, nil, token)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	defer reportResp.Body.Close()
+
+	var report DividendReport
+	if err := json.NewDecoder(reportResp.Body).Decode(&report); err != nil {
+		t.Fatalf(failedDecodeMsg, err)
+	}
+
+	if report.TotalGross != jcpGross+rendimentoGross {
+		t.Errorf("Expected total gross %f, got %f", jcpGross+rendimentoGross, report.TotalGross)
+	}
+
+	if len(report.Assets) != 1 || report.Assets[0].Yield != expectedDividendYield {
+		t.Errorf("Expected one asset yielding %f, got %+v", expectedDividendYield, report.Assets)
+	}
+
+	overviewResp, err := makeRequestWithAuth("GET", overviewEndpoint, nil, token)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	defer overviewResp.Body.Close()
+
+	var overview OverviewData
+	if err := json.NewDecoder(overviewResp.Body).Decode(&overview); err != nil {
+		t.Fatalf(failedDecodeMsg, err)
+	}
+
+	found := false
+	for _, month := range overview.MonthlyData {
+		if month.Month == dividendMonth {
+			found = true
+			if month.Proventos != jcpExpectedNet+rendimentoGross {
+				t.Errorf("Expected proventos %f, got %f", jcpExpectedNet+rendimentoGross, month.Proventos)
+			}
+		}
+	}
+
+	if !found {
+		t.Errorf("Expected month %s in overview monthly data", dividendMonth)
+	}
+}
+
+func TestDividendWithoutPosition(t *testing.T) {
+	token, err := createAuthenticatedUser(noPositionUserEmail, noPositionUserName)
+	if err != nil {
+		t.Fatalf(failedCreateUserMsg, err)
+	}
+
+	resp, err := makeRequestWithAuth("POST", dividendsEndpoint, map[string]interface{}{
+		"ticker": dividendTicker, "kind": rendimentoKind, "grossAmount": rendimentoGross, "payDate": dividendPayDate,
+	}, token)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", resp.StatusCode)
+	}
+}
+
+func TestDividendReportIncludesCurrentMonth(t *testing.T) {
+	token, err := createAuthenticatedUser(currentMonthUserEmail, currentMonthUserName)
+	if err != nil {
+		t.Fatalf(failedCreateUserMsg, err)
+	}
+
+	createTrade(t, token, map[string]interface{}{
+		"ticker": dividendTicker, "assetClass": fiiClass, "side": buySide, "quantity": buyQuantity, "price": firstBuyPrice, "date": dividendBuyDate,
+	})
+
+	resp, err := makeRequestWithAuth("POST", dividendsEndpoint, map[string]interface{}{
+		"ticker": dividendTicker, "kind": rendimentoKind, "grossAmount": rendimentoGross, "payDate": currentMonthPayDate,
+	}, token)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d", resp.StatusCode)
+	}
+
+	var report DividendReport
+	if status := decodeInto(t, "GET", dividendReportEndpoint+"?date="+midMonthReportDate, nil, token, &report); status != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", status)
+	}
+
+	if len(report.Months) != 12 {
+		t.Fatalf("Expected 12 monthly buckets, got %d", len(report.Months))
+	}
+
+	if report.Months[0].Month != firstReportMonth || report.Months[11].Month != currentReportMonth {
+		t.Errorf("Expected buckets from %s to %s, got %s to %s", firstReportMonth, currentReportMonth, report.Months[0].Month, report.Months[11].Month)
+	}
+
+	if report.Months[11].Gross != rendimentoGross {
+		t.Errorf("Expected %f gross in %s, got %f", rendimentoGross, currentReportMonth, report.Months[11].Gross)
+	}
+
+	var bucketed float64
+	for _, month := range report.Months {
+		bucketed += month.Gross
+	}
+	if bucketed != report.TotalGross {
+		t.Errorf("Expected monthly buckets to add up to total gross %f, got %f", report.TotalGross, bucketed)
+	}
+}
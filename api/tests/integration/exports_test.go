@@ -0,0 +1,167 @@
+package integration
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	// Endpoints
+	exportsEndpoint = "/api/exports"
+
+	// Test data
+	exportUserEmail = "exports@test.com"
+	exportUserName = "Export User"
+	exportOtherEmail = "exports.other@test.com"
+	exportOtherName = "Other Export User"
+	exportDescription = "Padaria Central"
+	exportReadyStatus = "ready"
+)
+
+type ExportJob struct {
+	ID          string `json:"id"`
+	Status      string `json:"status"`
+	Error       string `json:"error"`
+	DownloadURL string `json:"downloadUrl"`
+}
+
+type ExportManifest struct {
+	Files []struct {
+		Name    string `json:"name"`
+		Records int    `json:"records"`
+	} `json:"files"`
+}
+
+// waitForExport polls the job until it leaves the queue.
+func waitForExport(t *testing.T, id, token string) ExportJob {
+	deadline := time.Now().Add(Timeout)
+	for time.Now().Before(deadline) {
+		var job ExportJob
+		if status := decodeInto(t, "GET", exportsEndpoint+"/"+id, nil, token, &job); status != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", status)
+		}
+		if job.Status != "pending" && job.Status != "running" {
+			return job
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+	t.Fatalf("Export %s did not finish in %s", id, Timeout)
+	return ExportJob{}
+}
+
+func download(t *testing.T, url string) (int, []byte) {
+	resp, err := makeRequest("GET", url, nil)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Failed to read body: %v", err)
+	}
+	return resp.StatusCode, body
+}
+
+func TestDataExport(t *testing.T) {
+	token, err := createAuthenticatedUser(exportUserEmail, exportUserName)
+	if err != nil {
+		t.Fatalf(failedRegisterMsg, err)
+	}
+	otherToken, err := createAuthenticatedUser(exportOtherEmail, exportOtherName)
+	if err != nil {
+		t.Fatalf(failedRegisterMsg, err)
+	}
+
+	body := map[string]any{"type": expenseType, "description": exportDescription, "amount": 12.5, "date": testDate}
+	if status := decodeInto(t, "POST", transactionsEndpoint, body, token, nil); status != http.StatusCreated {
+		t.Fatalf("Expected status 201 creating a transaction, got %d", status)
+	}
+
+	var job ExportJob
+	if status := decodeInto(t, "POST", exportsEndpoint, nil, token, &job); status != http.StatusAccepted {
+		t.Fatalf("Expected status 202, got %d", status)
+	}
+	job = waitForExport(t, job.ID, token)
+	if job.Status != exportReadyStatus {
+		t.Fatalf("Expected status %s, got %s (%s)", exportReadyStatus, job.Status, job.Error)
+	}
+
+	t.Run("Archive", func(t *testing.T) {
+		status, archive := download(t, job.DownloadURL)
+		if status != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", status)
+		}
+
+		reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+		if err != nil {
+			t.Fatalf("Failed to open archive: %v", err)
+		}
+		files := map[string]*zip.File{}
+		for _, file := range reader.File {
+			files[file.Name] = file
+		}
+		for _, name := range []string{"manifest.json", "profile.json", "transactions.json", "transactions.csv"} {
+			if files[name] == nil {
+				t.Errorf("Expected %s in the archive", name)
+			}
+		}
+		if files["refresh_tokens.json"] != nil {
+			t.Error("Expected refresh tokens to be left out of the archive")
+		}
+
+		manifestFile, err := files["manifest.json"].Open()
+		if err != nil {
+			t.Fatalf("Failed to open manifest: %v", err)
+		}
+		defer manifestFile.Close()
+		var manifest ExportManifest
+		if err := json.NewDecoder(manifestFile).Decode(&manifest); err != nil {
+			t.Fatalf(failedDecodeMsg, err)
+		}
+		for _, file := range manifest.Files {
+			if file.Name == "transactions.csv" && file.Records != 1 {
+				t.Errorf("Expected 1 transaction in the manifest, got %d", file.Records)
+			}
+		}
+
+		csvFile, err := files["transactions.csv"].Open()
+		if err != nil {
+			t.Fatalf("Failed to open transactions.csv: %v", err)
+		}
+		defer csvFile.Close()
+		content, _ := io.ReadAll(csvFile)
+		if !strings.Contains(string(content), exportDescription) {
+			t.Errorf("Expected %q in transactions.csv", exportDescription)
+		}
+	})
+
+	t.Run("Tampered link", func(t *testing.T) {
+		status, _ := download(t, job.DownloadURL+"0")
+		if status != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d", status)
+		}
+	})
+
+	t.Run("Other user", func(t *testing.T) {
+		if status := decodeInto(t, "GET", exportsEndpoint+"/"+job.ID, nil, otherToken, nil); status != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", status)
+		}
+	})
+
+	t.Run("List", func(t *testing.T) {
+		var jobs []ExportJob
+		if status := decodeInto(t, "GET", exportsEndpoint, nil, token, &jobs); status != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", status)
+		}
+		if len(jobs) != 1 || jobs[0].ID != job.ID {
+			t.Errorf("Expected only export %s, got %+v", job.ID, jobs)
+		}
+	})
+}
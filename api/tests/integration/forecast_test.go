@@ -0,0 +1,124 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+)
+
+const (
+	// Endpoints
+	recurringEndpoint = "/api/recurring"
+	forecastEndpoint = "/api/forecast"
+
+	// Test data
+	forecastUserEmail = "forecast@test.com"
+	forecastUserName = "Forecast User"
+	rentDescription = "Aluguel"
+	salaryDescription = "Salário"
+	monthlyFrequency = "monthly"
+
+	forecastBalance = 1000.0
+	rentAmount = 600.0
+	expectedEndBalance = -800.0 // three rent payments in the next three months
+)
+
+type ForecastReport struct {
+	StartBalance      float64 `json:"startBalance"`
+	EndBalance        float64 `json:"endBalance"`
+	FirstNegativeDate *string `json:"firstNegativeDate"`
+	Points            []struct {
+		Date     string  `json:"date"`
+		Outflows float64 `json:"outflows"`
+		Balance  float64 `json:"balance"`
+	} `json:"points"`
+	Events []struct {
+		Date   string  `json:"date"`
+		Source string  `json:"source"`
+		Amount float64 `json:"amount"`
+	} `json:"events"`
+}
+
+func getForecast(t *testing.T, token, query string) ForecastReport {
+	resp, err := makeRequestWithAuth("GET", forecastEndpoint+"?"+query, nil, token)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+
+	var report ForecastReport
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		t.Fatalf(failedDecodeMsg, err)
+	}
+	return report
+}
+
+func TestCashFlowForecast(t *testing.T) {
+	token, err := createAuthenticatedUser(forecastUserEmail, forecastUserName)
+	if err != nil {
+		t.Fatalf(failedCreateUserMsg, err)
+	}
+
+	resp, err := makeRequestWithAuth("POST", transactionsEndpoint, map[string]interface{}{
+		"type": incomeType, "description": salaryDescription, "amount": forecastBalance, "date": testDate,
+	}, token)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	resp.Body.Close()
+
+	tomorrow := time.Now().UTC().AddDate(0, 0, 1).Format("2006-01-02")
+	resp, err = makeRequestWithAuth("POST", recurringEndpoint, map[string]interface{}{
+		"type": expenseType, "description": rentDescription, "amount": rentAmount, "frequency": monthlyFrequency, "startDate": tomorrow,
+	}, token)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d", resp.StatusCode)
+	}
+
+	t.Run("Daily forecast", func(t *testing.T) {
+		report := getForecast(t, token, "months=3")
+
+		if report.StartBalance != forecastBalance || report.EndBalance != expectedEndBalance {
+			t.Errorf("Expected balance from %f to %f, got %f to %f", forecastBalance, expectedEndBalance, report.StartBalance, report.EndBalance)
+		}
+		if len(report.Events) != 3 || report.Events[0].Date != tomorrow {
+			t.Errorf("Expected 3 rent payments starting %s, got %+v", tomorrow, report.Events)
+		}
+		if report.FirstNegativeDate == nil || *report.FirstNegativeDate != report.Events[1].Date {
+			t.Errorf("Expected the balance to go negative on the second rent payment, got %v", report.FirstNegativeDate)
+		}
+		if len(report.Points) == 0 || report.Points[0].Date != tomorrow || report.Points[0].Outflows != rentAmount {
+			t.Errorf("Expected the first daily point to pay the rent, got %+v", report.Points)
+		}
+	})
+
+	t.Run("Monthly forecast", func(t *testing.T) {
+		report := getForecast(t, token, "months=3&interval=monthly")
+
+		last := report.Points[len(report.Points)-1]
+		if last.Balance != expectedEndBalance || len(report.Points[0].Date) != len("2006-01") {
+			t.Errorf("Expected monthly points ending at %f, got %+v", expectedEndBalance, report.Points)
+		}
+	})
+
+	t.Run("Invalid months", func(t *testing.T) {
+		resp, err := makeRequestWithAuth("GET", forecastEndpoint+"?months=36", nil, token)
+		if err != nil {
+			t.Fatalf(failedRequestMsg, err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", resp.StatusCode)
+		}
+	})
+}
@@ -0,0 +1,197 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const (
+	// Endpoints
+	indexImportEndpoint = "/api/indexes/import"
+	cdiIndexEndpoint = "/api/indexes/CDI"
+
+	// Test data
+	indexUserEmail = "indexes@test.com"
+	indexUserName = "Index User"
+	indexedInvestmentName = "CDB 110% CDI"
+	cdiIndexer = "CDI"
+	cdiPercent = 110.0
+
+	// CDI daily rates (percent per day) for the first business days of 2024
+	cdiCSV = "data;valor\n02/01/2024;0,043739\n03/01/2024;0,043739\n04/01/2024;0,043739\n05/01/2024;0,043739\n08/01/2024;0,043739\n"
+	cdiImportedValues = 5
+	indexedStartDate = "2024-01-02"
+	indexedValuationDate = "2024-01-09"
+)
+
+type IndexImportResult struct {
+	Imported int      `json:"imported"`
+	Skipped  int      `json:"skipped"`
+	Errors   []string `json:"errors"`
+}
+
+type InvestmentValuation struct {
+	InvestmentID string  `json:"investmentId"`
+	Invested     float64 `json:"invested"`
+	GrossValue   float64 `json:"grossValue"`
+	Earnings     float64 `json:"earnings"`
+	IOF          float64 `json:"iof"`
+	IncomeTax    float64 `json:"incomeTax"`
+	TaxDue       float64 `json:"taxDue"`
+	NetValue     float64 `json:"netValue"`
+	Factor       float64 `json:"factor"`
+	BusinessDays int     `json:"businessDays"`
+}
+
+func importIndexCSV(t *testing.T, index, body string) IndexImportResult {
+	req, err := http.NewRequest("POST", BaseURL+indexImportEndpoint+"?index="+index, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	req.Header.Set("Content-Type", "text/csv")
+	req.Header.Set("X-Operator-Token", OperatorToken)
+
+	resp, err := (&http.Client{Timeout: Timeout}).Do(req)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+
+	var result IndexImportResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf(failedDecodeMsg, err)
+	}
+	return result
+}
+
+func TestIndexImportAndValuation(t *testing.T) {
+	token, err := createAuthenticatedUser(indexUserEmail, indexUserName)
+	if err != nil {
+		t.Fatalf(failedCreateUserMsg, err)
+	}
+
+	result := importIndexCSV(t, cdiIndexer, cdiCSV)
+	if result.Imported != cdiImportedValues {
+		t.Errorf("Expected %d imported values, got %d", cdiImportedValues, result.Imported)
+	}
+
+	payload := map[string]interface{}{
+		"name":         indexedInvestmentName,
+		"amount":       investmentAmount1,
+		"date":         indexedStartDate,
+		"type":         cdbType,
+		"indexer":      cdiIndexer,
+		"indexPercent": cdiPercent,
+	}
+
+	resp, err := makeRequestWithAuth("POST", investmentsEndpoint, payload, token)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d", resp.StatusCode)
+	}
+
+	var investment Investment
+	if err := json.NewDecoder(resp.Body).Decode(&investment); err != nil {
+		t.Fatalf(failedDecodeMsg, err)
+	}
+
+	path := fmt.Sprintf("%s/%s/valuation?date=%s", investmentsEndpoint, investment.ID, indexedValuationDate)
+	valResp, err := makeRequestWithAuth("GET", path, nil, token)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	defer valResp.Body.Close()
+
+	var valuation InvestmentValuation
+	if err := json.NewDecoder(valResp.Body).Decode(&valuation); err != nil {
+		t.Fatalf(failedDecodeMsg, err)
+	}
+
+	if valuation.BusinessDays != cdiImportedValues {
+		t.Errorf("Expected %d business days, got %d", cdiImportedValues, valuation.BusinessDays)
+	}
+
+	if valuation.Invested != investmentAmount1 {
+		t.Errorf("Expected invested %f, got %f", investmentAmount1, valuation.Invested)
+	}
+
+	if valuation.GrossValue <= investmentAmount1 || valuation.Earnings <= 0 {
+		t.Errorf("Expected positive earnings, got gross %f", valuation.GrossValue)
+	}
+}
+
+func TestIndexValuesList(t *testing.T) {
+	token, err := createAuthenticatedUser("indexlist@test.com", "Index List User")
+	if err != nil {
+		t.Fatalf(failedCreateUserMsg, err)
+	}
+
+	importIndexCSV(t, cdiIndexer, cdiCSV)
+
+	resp, err := makeRequestWithAuth("GET", cdiIndexEndpoint+"?from=2024-01-01&to=2024-01-31", nil, token)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	defer resp.Body.Close()
+
+	var values []map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&values); err != nil {
+		t.Fatalf(failedDecodeMsg, err)
+	}
+
+	if len(values) < cdiImportedValues {
+		t.Errorf("Expected at least %d values, got %d", cdiImportedValues, len(values))
+	}
+}
+
+func TestUnsupportedIndex(t *testing.T) {
+	token, err := createAuthenticatedUser("unsupportedindex@test.com", "Unsupported Index User")
+	if err != nil {
+		t.Fatalf(failedCreateUserMsg, err)
+	}
+
+	resp, err := makeRequestWithAuth("GET", "/api/indexes/UNKNOWN", nil, token)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", resp.StatusCode)
+	}
+}
+
+func TestIndexImportRequiresOperator(t *testing.T) {
+	token, err := createAuthenticatedUser("indexoperator@test.com", "Index Operator User")
+	if err != nil {
+		t.Fatalf(failedCreateUserMsg, err)
+	}
+
+	req, err := http.NewRequest("POST", BaseURL+indexImportEndpoint+"?index="+cdiIndexer, strings.NewReader(cdiCSV))
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	req.Header.Set("Content-Type", "text/csv")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := (&http.Client{Timeout: Timeout}).Do(req)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected status 403 importing as a user, got %d", resp.StatusCode)
+	}
+}
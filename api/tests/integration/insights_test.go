@@ -0,0 +1,124 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+const (
+	// Endpoints
+	insightsEndpoint = "/api/insights"
+
+	// Test data
+	insightUserEmail = "insights@test.com"
+	insightUserName = "Insight User"
+	groceryDescription = "Mercado Central"
+	unusualCategoryFlag = "unusual_for_category"
+	largeTransactionKind = "large_transaction"
+
+	unusualGroceryAmount = 1000.0
+)
+
+var usualGroceryAmounts = []float64{100, 110, 90, 105, 95, 100}
+
+type Insight struct {
+	ID            string  `json:"id"`
+	Kind          string  `json:"kind"`
+	TransactionID *string `json:"transactionId"`
+	Amount        float64 `json:"amount"`
+	Baseline      float64 `json:"baseline"`
+}
+
+type FlaggedTransaction struct {
+	ID    string   `json:"id"`
+	Flags []string `json:"flags"`
+}
+
+func createGroceryExpense(t *testing.T, token, categoryID string, amount float64) FlaggedTransaction {
+	resp, err := makeRequestWithAuth("POST", transactionsEndpoint, map[string]interface{}{
+		"type": expenseType, "description": groceryDescription, "amount": amount, "date": testDate, "categoryId": categoryID,
+	}, token)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	defer resp.Body.Close()
+
+	var transaction FlaggedTransaction
+	if err := json.NewDecoder(resp.Body).Decode(&transaction); err != nil {
+		t.Fatalf(failedDecodeMsg, err)
+	}
+	return transaction
+}
+
+func getInsights(t *testing.T, token string) []Insight {
+	resp, err := makeRequestWithAuth("GET", insightsEndpoint, nil, token)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	defer resp.Body.Close()
+
+	var insights []Insight
+	if err := json.NewDecoder(resp.Body).Decode(&insights); err != nil {
+		t.Fatalf(failedDecodeMsg, err)
+	}
+	return insights
+}
+
+func TestSpendingAnomalies(t *testing.T) {
+	token, err := createAuthenticatedUser(insightUserEmail, insightUserName)
+	if err != nil {
+		t.Fatalf(failedCreateUserMsg, err)
+	}
+
+	categoryID := categoryIDs(t, token)[foodCategoryName]
+	for _, amount := range usualGroceryAmounts {
+		if usual := createGroceryExpense(t, token, categoryID, amount); len(usual.Flags) != 0 {
+			t.Errorf("Expected usual expense not to be flagged, got %v", usual.Flags)
+		}
+	}
+
+	unusual := createGroceryExpense(t, token, categoryID, unusualGroceryAmount)
+	flagged := false
+	for _, flag := range unusual.Flags {
+		flagged = flagged || flag == unusualCategoryFlag
+	}
+	if !flagged {
+		t.Fatalf("Expected the expense to be flagged %s, got %v", unusualCategoryFlag, unusual.Flags)
+	}
+
+	insights := getInsights(t, token)
+	if len(insights) != 1 || insights[0].Kind != largeTransactionKind || insights[0].TransactionID == nil || *insights[0].TransactionID != unusual.ID {
+		t.Fatalf("Expected one insight about the flagged expense, got %+v", insights)
+	}
+	if insights[0].Baseline != 100 {
+		t.Errorf("Expected a baseline of 100, got %f", insights[0].Baseline)
+	}
+
+	t.Run("Sweep does not repeat insights", func(t *testing.T) {
+		resp, err := makeRequestWithAuth("POST", insightsEndpoint+"/sweep", nil, token)
+		if err != nil {
+			t.Fatalf(failedRequestMsg, err)
+		}
+		resp.Body.Close()
+
+		if got := getInsights(t, token); len(got) != 1 {
+			t.Errorf("Expected 1 insight after the sweep, got %d", len(got))
+		}
+	})
+
+	t.Run("Dismiss", func(t *testing.T) {
+		resp, err := makeRequestWithAuth("POST", insightsEndpoint+"/"+insights[0].ID+"/dismiss", nil, token)
+		if err != nil {
+			t.Fatalf(failedRequestMsg, err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusNoContent {
+			t.Errorf("Expected status 204, got %d", resp.StatusCode)
+		}
+		if got := getInsights(t, token); len(got) != 0 {
+			t.Errorf("Expected the feed to be empty, got %d", len(got))
+		}
+	})
+}
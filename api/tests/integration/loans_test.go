@@ -0,0 +1,147 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+const (
+	// Endpoints
+	loansEndpoint = "/api/loans"
+	loanSimulateEndpoint = "/api/loans/simulate"
+
+	// Test data
+	loanUserEmail = "loans@test.com"
+	loanUserName = "Loan User"
+	loanName = "Financiamento carro"
+	sacSystem = "sac"
+	priceSystem = "price"
+	reduceTermMode = "term"
+	loanStartDate = "2024-01-31"
+	loanPostUntil = "2024-03-31"
+
+	loanPrincipal = 12000.0
+	loanAnnualRate = 12.68250301 // 1% a month
+	loanTerm = 12
+	loanExtraPayment = 3000.0
+
+	expectedSACFirstPayment = 1120.0 // 1000 amortization + 120 interest
+	expectedPricePayment = 1066.19
+	expectedTermAfterExtra = 9
+)
+
+type LoanInstallment struct {
+	Number    int     `json:"number"`
+	Date      string  `json:"date"`
+	Payment   float64 `json:"payment"`
+	Interest  float64 `json:"interest"`
+	Principal float64 `json:"principal"`
+	Balance   float64 `json:"balance"`
+	Posted    bool    `json:"posted"`
+}
+
+type LoanSchedule struct {
+	Loan struct {
+		ID string `json:"id"`
+	} `json:"loan"`
+	Term          int               `json:"term"`
+	TotalInterest float64           `json:"totalInterest"`
+	Installments  []LoanInstallment `json:"installments"`
+}
+
+type LoanPostResult struct {
+	Posted       []int `json:"posted"`
+	Transactions int   `json:"transactions"`
+}
+
+func loanPayload(system string) map[string]interface{} {
+	return map[string]interface{}{
+		"name": loanName, "system": system, "principal": loanPrincipal, "rate": loanAnnualRate, "term": loanTerm, "startDate": loanStartDate,
+	}
+}
+
+func decodeLoanResponse(t *testing.T, method, path string, payload interface{}, token string, expectedStatus int, target interface{}) {
+	resp, err := makeRequestWithAuth(method, path, payload, token)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != expectedStatus {
+		t.Fatalf("Expected status %d, got %d", expectedStatus, resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+		t.Fatalf(failedDecodeMsg, err)
+	}
+}
+
+func TestLoanSchedules(t *testing.T) {
+	token, err := createAuthenticatedUser(loanUserEmail, loanUserName)
+	if err != nil {
+		t.Fatalf(failedCreateUserMsg, err)
+	}
+
+	t.Run("SAC and Price", func(t *testing.T) {
+		var sac, price LoanSchedule
+		decodeLoanResponse(t, "POST", loanSimulateEndpoint, loanPayload(sacSystem), token, http.StatusOK, &sac)
+		decodeLoanResponse(t, "POST", loanSimulateEndpoint, loanPayload(priceSystem), token, http.StatusOK, &price)
+
+		if len(sac.Installments) != loanTerm || len(price.Installments) != loanTerm {
+			t.Fatalf("Expected %d installments, got %d and %d", loanTerm, len(sac.Installments), len(price.Installments))
+		}
+		if sac.Installments[0].Payment != expectedSACFirstPayment {
+			t.Errorf("Expected first SAC installment %f, got %f", expectedSACFirstPayment, sac.Installments[0].Payment)
+		}
+		for _, installment := range price.Installments {
+			if installment.Payment != expectedPricePayment {
+				t.Errorf("Expected Price installment %f, got %f", expectedPricePayment, installment.Payment)
+			}
+		}
+		if sac.Installments[loanTerm-1].Balance != 0 || price.Installments[loanTerm-1].Balance != 0 {
+			t.Error("Expected the loans to be paid off at the last installment")
+		}
+		if sac.TotalInterest >= price.TotalInterest {
+			t.Errorf("Expected SAC to pay less interest than Price, got %f and %f", sac.TotalInterest, price.TotalInterest)
+		}
+	})
+
+	var loan struct {
+		ID string `json:"id"`
+	}
+	decodeLoanResponse(t, "POST", loansEndpoint, loanPayload(sacSystem), token, http.StatusCreated, &loan)
+	loanPath := fmt.Sprintf("%s/%s", loansEndpoint, loan.ID)
+
+	t.Run("Extra payment shortens the term", func(t *testing.T) {
+		var schedule LoanSchedule
+		decodeLoanResponse(t, "POST", loanPath+"/extra-payments", map[string]interface{}{
+			"installment": 2, "amount": loanExtraPayment, "mode": reduceTermMode,
+		}, token, http.StatusCreated, &schedule)
+
+		if schedule.Term != expectedTermAfterExtra {
+			t.Errorf("Expected term %d, got %d", expectedTermAfterExtra, schedule.Term)
+		}
+	})
+
+	t.Run("Post due installments once", func(t *testing.T) {
+		var result LoanPostResult
+		decodeLoanResponse(t, "POST", loanPath+"/post?until="+loanPostUntil, nil, token, http.StatusOK, &result)
+
+		if len(result.Posted) != 2 || result.Transactions != 4 {
+			t.Errorf("Expected 2 installments as 4 transactions, got %v and %d", result.Posted, result.Transactions)
+		}
+
+		decodeLoanResponse(t, "POST", loanPath+"/post?until="+loanPostUntil, nil, token, http.StatusOK, &result)
+		if len(result.Posted) != 0 {
+			t.Errorf("Expected nothing left to post, got %v", result.Posted)
+		}
+
+		var schedule LoanSchedule
+		decodeLoanResponse(t, "GET", loanPath, nil, token, http.StatusOK, &schedule)
+		if !schedule.Installments[1].Posted || schedule.Installments[2].Posted {
+			t.Error("Expected only the first two installments to be posted")
+		}
+	})
+}
@@ -0,0 +1,91 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+)
+
+const (
+	// Endpoints
+	unlockEndpoint = "/api/auth/unlock"
+	securityEventsEndpoint = "/api/auth/security-events"
+
+	// Test data
+	lockoutEmail = "lockout@example.com"
+	lockoutUserName = "Lockout User"
+	accountLockedEvent = "account_locked"
+	accountUnlockedEvent = "account_unlocked"
+)
+
+func loginStatus(t *testing.T, email, password string) (int, string) {
+	resp, err := makeRequest("POST", loginEndpoint, map[string]any{"email": email, "password": password})
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode, resp.Header.Get("Retry-After")
+}
+
+func TestLoginLockout(t *testing.T) {
+	registerForTokens(t, lockoutEmail, lockoutUserName)
+
+	for i := 0; i < 3; i++ {
+		if status, _ := loginStatus(t, lockoutEmail, wrongPassword); status != http.StatusUnauthorized {
+			t.Fatalf("Expected status 401 on failure %d, got %d", i+1, status)
+		}
+	}
+
+	// The fourth attempt waits one second, the fifth two
+	status, retryAfter := loginStatus(t, lockoutEmail, wrongPassword)
+	if status != http.StatusTooManyRequests || retryAfter == "" {
+		t.Fatalf("Expected status 429 with Retry-After, got %d %q", status, retryAfter)
+	}
+	time.Sleep(1100 * time.Millisecond)
+	if status, _ := loginStatus(t, lockoutEmail, wrongPassword); status != http.StatusUnauthorized {
+		t.Fatalf("Expected status 401 after the delay, got %d", status)
+	}
+	time.Sleep(2100 * time.Millisecond)
+	if status, _ := loginStatus(t, lockoutEmail, wrongPassword); status != http.StatusUnauthorized {
+		t.Fatalf("Expected status 401 after the delay, got %d", status)
+	}
+
+	if status, _ := loginStatus(t, lockoutEmail, testPassword); status != http.StatusTooManyRequests {
+		t.Fatalf("Expected the account to be locked, got %d", status)
+	}
+
+	t.Run("Unlock by email", func(t *testing.T) {
+		token := mailedToken(t, lockoutEmail)
+		if got := postStatus(t, unlockEndpoint, map[string]any{"token": token}); got != http.StatusNoContent {
+			t.Fatalf("Expected status 204, got %d", got)
+		}
+
+		session := loginForTokens(t, lockoutEmail)
+		if session.Token == "" {
+			t.Fatal("Expected to log in after unlocking")
+		}
+
+		resp, err := makeRequestWithAuth("GET", securityEventsEndpoint, nil, session.Token)
+		if err != nil {
+			t.Fatalf(failedRequestMsg, err)
+		}
+		defer resp.Body.Close()
+
+		var events []struct {
+			Event string `json:"event"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&events); err != nil {
+			t.Fatalf(failedDecodeMsg, err)
+		}
+		if len(events) != 2 || events[0].Event != accountUnlockedEvent || events[1].Event != accountLockedEvent {
+			t.Errorf("Expected the lock and the unlock in the audit log, got %+v", events)
+		}
+	})
+
+	t.Run("Invalid unlock token", func(t *testing.T) {
+		if got := postStatus(t, unlockEndpoint, map[string]any{"token": randomToken}); got != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", got)
+		}
+	})
+}
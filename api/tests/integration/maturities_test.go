@@ -0,0 +1,164 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+)
+
+const (
+	// Endpoints
+	maturitiesEndpoint = investmentsEndpoint + "/maturities"
+
+	// Test data
+	maturityUserEmail = "maturities@test.com"
+	maturityUserName = "Maturity User"
+	indexedMaturityEmail = "maturities.indexed@test.com"
+	indexedMaturityName = "Indexed Maturity User"
+	maturityInvestmentName = "CDB 2 anos"
+	atMaturityLiquidity = "maturity"
+	dailyLiquidity = "D+0"
+
+	maturityAmount = 1000.0
+	maturityRate = 12.0
+	daysToMaturity = 60
+)
+
+type LiquidityReport struct {
+	TotalValue float64 `json:"totalValue"`
+	Buckets    []struct {
+		Days   int     `json:"days"`
+		Amount float64 `json:"amount"`
+	} `json:"buckets"`
+	Maturities []struct {
+		Name                string  `json:"name"`
+		MaturityDate        string  `json:"maturityDate"`
+		DaysToMaturity      int     `json:"daysToMaturity"`
+		CurrentValue        float64 `json:"currentValue"`
+		ProjectedGrossValue float64 `json:"projectedGrossValue"`
+		ProjectedNetValue   float64 `json:"projectedNetValue"`
+	} `json:"maturities"`
+}
+
+func TestInvestmentMaturities(t *testing.T) {
+	token, err := createAuthenticatedUser(maturityUserEmail, maturityUserName)
+	if err != nil {
+		t.Fatalf(failedCreateUserMsg, err)
+	}
+
+	maturity := time.Now().UTC().AddDate(0, 0, daysToMaturity).Format("2006-01-02")
+
+	t.Run("Maturity liquidity requires a maturity date", func(t *testing.T) {
+		for _, payload := range []map[string]interface{}{
+			{"name": maturityInvestmentName, "amount": maturityAmount, "rate": maturityRate, "date": testDate, "type": cdbType, "liquidity": atMaturityLiquidity},
+			{"name": maturityInvestmentName, "amount": maturityAmount, "rate": maturityRate, "date": testDate, "type": cdbType, "maturityDate": "2024-01-01"},
+		} {
+			resp, err := makeRequestWithAuth("POST", investmentsEndpoint, payload, token)
+			if err != nil {
+				t.Fatalf(failedRequestMsg, err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Errorf("Expected status 400, got %d", resp.StatusCode)
+			}
+		}
+	})
+
+	for _, payload := range []map[string]interface{}{
+		{"name": testInvestmentName, "amount": maturityAmount, "rate": maturityRate, "date": testDate, "type": cdbType, "liquidity": dailyLiquidity},
+		{"name": maturityInvestmentName, "amount": maturityAmount, "rate": maturityRate, "date": testDate, "type": cdbType, "maturityDate": maturity},
+	} {
+		resp, err := makeRequestWithAuth("POST", investmentsEndpoint, payload, token)
+		if err != nil {
+			t.Fatalf(failedRequestMsg, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d", resp.StatusCode)
+		}
+	}
+
+	resp, err := makeRequestWithAuth("GET", maturitiesEndpoint, nil, token)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+
+	var report LiquidityReport
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		t.Fatalf(failedDecodeMsg, err)
+	}
+
+	if len(report.Buckets) != 4 {
+		t.Fatalf("Expected 4 liquidity buckets, got %d", len(report.Buckets))
+	}
+
+	// Only the daily investment is available now; both are within 90 days
+	immediate, within90 := report.Buckets[0].Amount, report.Buckets[2].Amount
+	if immediate <= 0 || immediate >= within90 {
+		t.Errorf("Expected immediate liquidity below the 90-day bucket, got %f and %f", immediate, within90)
+	}
+	if within90 != report.TotalValue {
+		t.Errorf("Expected the whole portfolio within 90 days, got %f of %f", within90, report.TotalValue)
+	}
+
+	if len(report.Maturities) != 1 {
+		t.Fatalf("Expected 1 upcoming maturity, got %d", len(report.Maturities))
+	}
+
+	item := report.Maturities[0]
+	if item.MaturityDate != maturity || item.DaysToMaturity != daysToMaturity {
+		t.Errorf("Expected maturity %s in %d days, got %s in %d", maturity, daysToMaturity, item.MaturityDate, item.DaysToMaturity)
+	}
+	if item.ProjectedGrossValue <= item.CurrentValue || item.ProjectedNetValue >= item.ProjectedGrossValue {
+		t.Errorf("Expected projection to grow at the stored rate and be taxed, got %+v", item)
+	}
+}
+
+func TestIndexedInvestmentMaturityProjection(t *testing.T) {
+	token, err := createAuthenticatedUser(indexedMaturityEmail, indexedMaturityName)
+	if err != nil {
+		t.Fatalf(failedCreateUserMsg, err)
+	}
+
+	importIndexCSV(t, cdiIndexer, cdiCSV)
+
+	maturity := time.Now().UTC().AddDate(0, 0, daysToMaturity).Format("2006-01-02")
+	payload := map[string]interface{}{
+		"name":         indexedInvestmentName,
+		"amount":       maturityAmount,
+		"date":         indexedStartDate,
+		"type":         cdbType,
+		"indexer":      cdiIndexer,
+		"indexPercent": cdiPercent,
+		"maturityDate": maturity,
+	}
+	resp, err := makeRequestWithAuth("POST", investmentsEndpoint, payload, token)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d", resp.StatusCode)
+	}
+
+	var report LiquidityReport
+	if status := decodeInto(t, "GET", maturitiesEndpoint, nil, token, &report); status != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", status)
+	}
+	if len(report.Maturities) != 1 {
+		t.Fatalf("Expected 1 upcoming maturity, got %d", len(report.Maturities))
+	}
+
+	// Without a stored rate the projection follows the last CDI value
+	item := report.Maturities[0]
+	if item.ProjectedNetValue <= item.CurrentValue {
+		t.Errorf("Expected the projection to keep accruing CDI, got %+v", item)
+	}
+}
@@ -0,0 +1,142 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+const (
+	// Endpoints
+	merchantsEndpoint = "/api/merchants"
+	topMerchantsEndpoint = "/api/merchants/top"
+
+	// Test data
+	merchantUserEmail = "merchants@test.com"
+	merchantUserName = "Merchant User"
+	deliveryMerchant = "Ifood"
+	rideMerchant = "Uber"
+	rideAlias = "UBR VIAGEM 3344"
+	aliasMerchant = "Ubr Viagem"
+)
+
+type Merchant struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases"`
+}
+
+type MerchantItem struct {
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+	Count int     `json:"count"`
+}
+
+type MerchantTransaction struct {
+	Description string `json:"description"`
+	Merchant    string `json:"merchant"`
+}
+
+func createMerchantExpense(t *testing.T, token, description string, amount float64) MerchantTransaction {
+	resp, err := makeRequestWithAuth("POST", transactionsEndpoint, map[string]interface{}{
+		"type": expenseType, "description": description, "amount": amount, "date": testDate,
+	}, token)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	defer resp.Body.Close()
+
+	var transaction MerchantTransaction
+	if err := json.NewDecoder(resp.Body).Decode(&transaction); err != nil {
+		t.Fatalf(failedDecodeMsg, err)
+	}
+	return transaction
+}
+
+func getMerchants(t *testing.T, token string) map[string]Merchant {
+	resp, err := makeRequestWithAuth("GET", merchantsEndpoint, nil, token)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	defer resp.Body.Close()
+
+	var merchants []Merchant
+	if err := json.NewDecoder(resp.Body).Decode(&merchants); err != nil {
+		t.Fatalf(failedDecodeMsg, err)
+	}
+
+	byName := map[string]Merchant{}
+	for _, merchant := range merchants {
+		byName[merchant.Name] = merchant
+	}
+	return byName
+}
+
+func TestMerchantNormalization(t *testing.T) {
+	token, err := createAuthenticatedUser(merchantUserEmail, merchantUserName)
+	if err != nil {
+		t.Fatalf(failedCreateUserMsg, err)
+	}
+
+	for _, description := range []string{"PAG*IFOOD 1234 SAO PAULO", "IFOOD *PEDIDO"} {
+		transaction := createMerchantExpense(t, token, description, 25)
+		if transaction.Merchant != deliveryMerchant || transaction.Description != description {
+			t.Errorf("Expected %q to keep its description under %s, got %+v", description, deliveryMerchant, transaction)
+		}
+	}
+	createMerchantExpense(t, token, "UBER *TRIP HELP.UBER.COM", 15)
+	createMerchantExpense(t, token, rideAlias, 12)
+
+	merchants := getMerchants(t, token)
+	if _, ok := merchants[aliasMerchant]; !ok || len(merchants) != 3 {
+		t.Fatalf("Expected %s, %s and %s, got %+v", deliveryMerchant, rideMerchant, aliasMerchant, merchants)
+	}
+
+	t.Run("Merge alias", func(t *testing.T) {
+		resp, err := makeRequestWithAuth("PUT", merchantsEndpoint+"/"+merchants[rideMerchant].ID, map[string]interface{}{
+			"name": rideMerchant, "aliases": []string{rideAlias},
+		}, token)
+		if err != nil {
+			t.Fatalf(failedRequestMsg, err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+		}
+		if _, ok := getMerchants(t, token)[aliasMerchant]; ok {
+			t.Errorf("Expected %s to be merged into %s", aliasMerchant, rideMerchant)
+		}
+		if transaction := createMerchantExpense(t, token, rideAlias, 10); transaction.Merchant != rideMerchant {
+			t.Errorf("Expected new %s expenses under %s, got %s", rideAlias, rideMerchant, transaction.Merchant)
+		}
+	})
+
+	t.Run("Top merchants", func(t *testing.T) {
+		resp, err := makeRequestWithAuth("GET", topMerchantsEndpoint+"?limit=1", nil, token)
+		if err != nil {
+			t.Fatalf(failedRequestMsg, err)
+		}
+		defer resp.Body.Close()
+
+		var top []MerchantItem
+		if err := json.NewDecoder(resp.Body).Decode(&top); err != nil {
+			t.Fatalf(failedDecodeMsg, err)
+		}
+		if len(top) != 1 || top[0].Name != deliveryMerchant || top[0].Value != 50 || top[0].Count != 2 {
+			t.Errorf("Expected %s with 50 over 2 expenses, got %+v", deliveryMerchant, top)
+		}
+	})
+
+	t.Run("Unknown merchant", func(t *testing.T) {
+		resp, err := makeRequestWithAuth("PUT", merchantsEndpoint+"/000000000000000000000000", map[string]interface{}{"name": rideMerchant}, token)
+		if err != nil {
+			t.Fatalf(failedRequestMsg, err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", resp.StatusCode)
+		}
+	})
+}
@@ -0,0 +1,252 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"testing"
+)
+
+const (
+	// Test data
+	movementUserEmail = "movements@test.com"
+	movementUserName = "Movement User"
+	overdraftUserEmail = "overdraft@test.com"
+	overdraftUserName = "Overdraft User"
+	backdatedWithdrawalUserEmail = "backdatedwithdrawal@test.com"
+	backdatedWithdrawalUserName = "Backdated Withdrawal User"
+	redemptionUserEmail = "redemption@test.com"
+	redemptionUserName = "Redemption User"
+
+	// Movement types
+	contributionMovement = "contribution"
+	withdrawalMovement = "withdrawal"
+	incomeMovement = "income"
+	feeMovement = "fee"
+
+	// Amounts
+	ledgerInitialAmount = 1000.0
+	ledgerContribution = 500.0
+	ledgerWithdrawal = 200.0
+	ledgerIncome = 12.5
+	ledgerFee = 2.5
+	ledgerExpectedPosition = 1310.0 // 1000 + 500 - 200 + 12.5 - 2.5
+	redemptionAmount = 5000.0
+	laterContributionDate = "2024-11-01"
+	backdatedWithdrawalDate = "2024-10-15"
+
+	// Error messages
+	failedCreateMovementMsg = "Failed to create movement: %v"
+)
+
+type InvestmentMovement struct {
+	ID           string  `json:"id"`
+	InvestmentID string  `json:"investmentId"`
+	Type         string  `json:"type"`
+	Amount       float64 `json:"amount"`
+	Date         string  `json:"date"`
+}
+
+type InvestmentLedger struct {
+	Investment Investment           `json:"investment"`
+	Movements  []InvestmentMovement `json:"movements"`
+	Position   float64              `json:"position"`
+}
+
+func movementsEndpoint(investmentID string) string {
+	return fmt.Sprintf("%s/%s/movements", investmentsEndpoint, investmentID)
+}
+
+func createTestInvestment(t *testing.T, token string, amount float64) Investment {
+	payload := map[string]interface{}{
+		"name":   testInvestmentName,
+		"amount": amount,
+		"rate":   rate100,
+		"date":   testDate,
+		"type":   cdbType,
+	}
+
+	resp, err := makeRequestWithAuth("POST", investmentsEndpoint, payload, token)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	defer resp.Body.Close()
+
+	var investment Investment
+	if err := json.NewDecoder(resp.Body).Decode(&investment); err != nil {
+		t.Fatalf(failedDecodeMsg, err)
+	}
+	return investment
+}
+
+func TestInvestmentLedgerPosition(t *testing.T) {
+	token, err := createAuthenticatedUser(movementUserEmail, movementUserName)
+	if err != nil {
+		t.Fatalf(failedCreateUserMsg, err)
+	}
+
+	investment := createTestInvestment(t, token, ledgerInitialAmount)
+
+	movements := []map[string]interface{}{
+		{"type": contributionMovement, "amount": ledgerContribution, "date": testDate},
+		{"type": withdrawalMovement, "amount": ledgerWithdrawal, "date": testDate},
+		{"type": incomeMovement, "amount": ledgerIncome, "date": testDate},
+		{"type": feeMovement, "amount": ledgerFee, "date": testDate},
+	}
+
+	for _, movement := range movements {
+		resp, err := makeRequestWithAuth("POST", movementsEndpoint(investment.ID), movement, token)
+		if err != nil {
+			t.Fatalf(failedCreateMovementMsg, err)
+		}
+		if resp.StatusCode != http.StatusCreated {
+			t.Errorf("Expected status 201, got %d", resp.StatusCode)
+		}
+		resp.Body.Close()
+	}
+
+	resp, err := makeRequestWithAuth("GET", movementsEndpoint(investment.ID), nil, token)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	defer resp.Body.Close()
+
+	var ledger InvestmentLedger
+	if err := json.NewDecoder(resp.Body).Decode(&ledger); err != nil {
+		t.Fatalf(failedDecodeMsg, err)
+	}
+
+	// Initial contribution plus the four movements above
+	if len(ledger.Movements) != len(movements)+1 {
+		t.Errorf("Expected %d movements, got %d", len(movements)+1, len(ledger.Movements))
+	}
+
+	if ledger.Position != ledgerExpectedPosition {
+		t.Errorf("Expected position %f, got %f", ledgerExpectedPosition, ledger.Position)
+	}
+
+	dashResp, err := makeRequestWithAuth("GET", dashboardSummaryEndpoint, nil, token)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	defer dashResp.Body.Close()
+
+	var summary DashboardSummary
+	if err := json.NewDecoder(dashResp.Body).Decode(&summary); err != nil {
+		t.Fatalf(failedDecodeMsg, err)
+	}
+
+	if summary.Totals.TotalInvestments != ledgerExpectedPosition {
+		t.Errorf("Expected total investments %f, got %f", ledgerExpectedPosition, summary.Totals.TotalInvestments)
+	}
+}
+
+func TestInvestmentWithdrawalExceedingPosition(t *testing.T) {
+	token, err := createAuthenticatedUser(overdraftUserEmail, overdraftUserName)
+	if err != nil {
+		t.Fatalf(failedCreateUserMsg, err)
+	}
+
+	investment := createTestInvestment(t, token, ledgerInitialAmount)
+
+	payload := map[string]interface{}{
+		"type":   withdrawalMovement,
+		"amount": ledgerInitialAmount * 2,
+		"date":   testDate,
+	}
+
+	resp, err := makeRequestWithAuth("POST", movementsEndpoint(investment.ID), payload, token)
+	if err != nil {
+		t.Fatalf(failedCreateMovementMsg, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", resp.StatusCode)
+	}
+}
+
+func TestBackdatedWithdrawalExceedingBalance(t *testing.T) {
+	token, err := createAuthenticatedUser(backdatedWithdrawalUserEmail, backdatedWithdrawalUserName)
+	if err != nil {
+		t.Fatalf(failedCreateUserMsg, err)
+	}
+
+	investment := createTestInvestment(t, token, ledgerInitialAmount)
+
+	contribution := map[string]any{"type": contributionMovement, "amount": ledgerContribution, "date": laterContributionDate}
+	if status := decodeInto(t, "POST", movementsEndpoint(investment.ID), contribution, token, nil); status != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d", status)
+	}
+
+	// Today's position covers it, but not the balance on the day it is dated
+	withdrawal := map[string]any{"type": withdrawalMovement, "amount": ledgerInitialAmount + ledgerWithdrawal, "date": backdatedWithdrawalDate}
+	if status := decodeInto(t, "POST", movementsEndpoint(investment.ID), withdrawal, token, nil); status != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", status)
+	}
+
+	var ledger InvestmentLedger
+	withdrawal["amount"] = ledgerWithdrawal
+	if status := decodeInto(t, "POST", movementsEndpoint(investment.ID), withdrawal, token, &ledger); status != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d", status)
+	}
+
+	// Initial contribution, the backdated withdrawal and the later contribution
+	if len(ledger.Movements) != 3 || ledger.Movements[1].Date != backdatedWithdrawalDate {
+		t.Errorf("Expected the withdrawal in date order, got %+v", ledger.Movements)
+	}
+
+	expected := ledgerInitialAmount + ledgerContribution - ledgerWithdrawal
+	if ledger.Position != expected {
+		t.Errorf("Expected position %f, got %f", expected, ledger.Position)
+	}
+}
+
+func TestInvestmentMovementsNotFound(t *testing.T) {
+	token, err := createAuthenticatedUser("movementsnotfound@test.com", "Movements Not Found User")
+	if err != nil {
+		t.Fatalf(failedCreateUserMsg, err)
+	}
+
+	resp, err := makeRequestWithAuth("GET", movementsEndpoint("000000000000000000000000"), nil, token)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", resp.StatusCode)
+	}
+}
+
+func TestWithdrawalRedeemsEarningsProportionally(t *testing.T) {
+	token, err := createAuthenticatedUser(redemptionUserEmail, redemptionUserName)
+	if err != nil {
+		t.Fatalf(failedCreateUserMsg, err)
+	}
+
+	investment := createInvestmentAt(t, token, cdbType, longTermStartDate)
+	before := getValuation(t, token, investment.ID, longTermValuationDate)
+
+	withdrawal := map[string]interface{}{"type": withdrawalMovement, "amount": redemptionAmount, "date": longTermValuationDate}
+	resp, err := makeRequestWithAuth("POST", movementsEndpoint(investment.ID), withdrawal, token)
+	if err != nil {
+		t.Fatalf(failedCreateMovementMsg, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d", resp.StatusCode)
+	}
+
+	// The withdrawal redeems principal and earnings in the proportion the
+	// lot held them, not principal first
+	after := getValuation(t, token, investment.ID, longTermValuationDate)
+	expectedInvested := before.Invested * (1 - redemptionAmount/before.GrossValue)
+	if math.Abs(after.Invested-expectedInvested) > taxTolerance {
+		t.Errorf("Expected invested %f, got %f", expectedInvested, after.Invested)
+	}
+	if math.Abs(after.GrossValue-(before.GrossValue-redemptionAmount)) > taxTolerance {
+		t.Errorf("Expected gross value %f, got %f", before.GrossValue-redemptionAmount, after.GrossValue)
+	}
+}
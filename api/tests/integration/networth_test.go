@@ -0,0 +1,150 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+const (
+	// Endpoints
+	netWorthEndpoint = "/api/net-worth"
+	netWorthItemsEndpoint = "/api/net-worth/items"
+	netWorthSnapshotsEndpoint = "/api/net-worth/snapshots"
+
+	// Test data
+	netWorthUserEmail = "networth@test.com"
+	netWorthUserName = "Net Worth User"
+	assetKind = "asset"
+	liabilityKind = "liability"
+	houseName = "Apartamento"
+	mortgageName = "Financiamento imobiliário"
+
+	houseValue = 300000.0
+	mortgageBalance = 200000.0
+	mortgageAfterPayments = 150000.0
+)
+
+type NetWorthItem struct {
+	ID    string  `json:"id"`
+	Kind  string  `json:"kind"`
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+}
+
+type NetWorthSnapshot struct {
+	Date        string  `json:"date"`
+	Cash        float64 `json:"cash"`
+	Assets      float64 `json:"assets"`
+	Liabilities float64 `json:"liabilities"`
+	NetWorth    float64 `json:"netWorth"`
+}
+
+func saveNetWorthItem(t *testing.T, token, method, path string, payload map[string]interface{}) NetWorthItem {
+	resp, err := makeRequestWithAuth(method, path, payload, token)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 201 or 200, got %d", resp.StatusCode)
+	}
+
+	var item NetWorthItem
+	if err := json.NewDecoder(resp.Body).Decode(&item); err != nil {
+		t.Fatalf(failedDecodeMsg, err)
+	}
+	return item
+}
+
+func takeNetWorthSnapshot(t *testing.T, token string) NetWorthSnapshot {
+	resp, err := makeRequestWithAuth("POST", netWorthSnapshotsEndpoint, nil, token)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d", resp.StatusCode)
+	}
+
+	var snapshot NetWorthSnapshot
+	if err := json.NewDecoder(resp.Body).Decode(&snapshot); err != nil {
+		t.Fatalf(failedDecodeMsg, err)
+	}
+	return snapshot
+}
+
+func TestNetWorth(t *testing.T) {
+	token, err := createAuthenticatedUser(netWorthUserEmail, netWorthUserName)
+	if err != nil {
+		t.Fatalf(failedCreateUserMsg, err)
+	}
+
+	house := saveNetWorthItem(t, token, "POST", netWorthItemsEndpoint, map[string]interface{}{
+		"kind": assetKind, "name": houseName, "value": houseValue,
+	})
+	mortgage := saveNetWorthItem(t, token, "POST", netWorthItemsEndpoint, map[string]interface{}{
+		"kind": liabilityKind, "name": mortgageName, "value": mortgageBalance,
+	})
+
+	snapshot := takeNetWorthSnapshot(t, token)
+	if snapshot.Assets != houseValue || snapshot.Liabilities != mortgageBalance {
+		t.Errorf("Expected assets %f and liabilities %f, got %f and %f", houseValue, mortgageBalance, snapshot.Assets, snapshot.Liabilities)
+	}
+	if snapshot.NetWorth != houseValue-mortgageBalance {
+		t.Errorf("Expected net worth %f, got %f", houseValue-mortgageBalance, snapshot.NetWorth)
+	}
+
+	t.Run("Same day snapshot is replaced", func(t *testing.T) {
+		saveNetWorthItem(t, token, "PUT", netWorthItemsEndpoint+"/"+mortgage.ID, map[string]interface{}{
+			"kind": liabilityKind, "name": mortgageName, "value": mortgageAfterPayments,
+		})
+		takeNetWorthSnapshot(t, token)
+
+		resp, err := makeRequestWithAuth("GET", netWorthEndpoint+"?from="+snapshot.Date+"&to="+snapshot.Date, nil, token)
+		if err != nil {
+			t.Fatalf(failedRequestMsg, err)
+		}
+		defer resp.Body.Close()
+
+		var series []NetWorthSnapshot
+		if err := json.NewDecoder(resp.Body).Decode(&series); err != nil {
+			t.Fatalf(failedDecodeMsg, err)
+		}
+
+		if len(series) != 1 {
+			t.Fatalf("Expected 1 snapshot, got %d", len(series))
+		}
+		if series[0].NetWorth != houseValue-mortgageAfterPayments {
+			t.Errorf("Expected net worth %f, got %f", houseValue-mortgageAfterPayments, series[0].NetWorth)
+		}
+	})
+
+	t.Run("Invalid interval", func(t *testing.T) {
+		resp, err := makeRequestWithAuth("GET", netWorthEndpoint+"?interval=weekly", nil, token)
+		if err != nil {
+			t.Fatalf(failedRequestMsg, err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("Delete item", func(t *testing.T) {
+		for _, expected := range []int{http.StatusNoContent, http.StatusNotFound} {
+			resp, err := makeRequestWithAuth("DELETE", netWorthItemsEndpoint+"/"+house.ID, nil, token)
+			if err != nil {
+				t.Fatalf(failedRequestMsg, err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != expected {
+				t.Errorf("Expected status %d, got %d", expected, resp.StatusCode)
+			}
+		}
+	})
+}
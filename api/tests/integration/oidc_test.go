@@ -0,0 +1,231 @@
+package integration
+
+import (
+	"net/http"
+	"net/url"
+	"os"
+	"testing"
+)
+
+const (
+	// Endpoints
+	oidcProvidersEndpoint = "/api/auth/oidc/providers"
+	oidcCallbackEndpoint = "/api/auth/oidc/callback"
+	oidcLinkEndpoint = "/api/auth/oidc/link"
+	mockAuthorizeEndpoint = "/api/auth/oidc/mock/authorize"
+	mockLinkEndpoint = "/api/auth/oidc/mock/link"
+	mockUnlinkEndpoint = "/api/auth/oidc/mock"
+
+	// Test data
+	mockProvider = "mock"
+	oidcNewEmail = "oidc.new@example.com"
+	oidcNewName = "Oidc New User"
+	oidcExistingEmail = "oidc.existing@example.com"
+	oidcExistingName = "Oidc Existing User"
+	oidcUnverifiedEmail = "oidc.unverified@example.com"
+	oidcLinkEmail = "oidc.link@example.com"
+	oidcLinkName = "Oidc Link User"
+	oidcLinkIdentityEmail = "oidc.link.other@example.com"
+)
+
+type OIDCAuthorization struct {
+	AuthorizationURL string `json:"authorizationUrl"`
+	State            string `json:"state"`
+}
+
+type OIDCUser struct {
+	ID         string `json:"id"`
+	Email      string `json:"email"`
+	Identities []struct {
+		Provider string `json:"provider"`
+		Email    string `json:"email"`
+	} `json:"identities"`
+}
+
+type OIDCAuthResponse struct {
+	Token string   `json:"token"`
+	User  OIDCUser `json:"user"`
+}
+
+// requireMockProvider skips unless the API has the mock provider configured.
+func requireMockProvider(t *testing.T) {
+	var providers struct {
+		Providers []string `json:"providers"`
+	}
+	if status := decodeInto(t, "GET", oidcProvidersEndpoint, nil, "", &providers); status != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", status)
+	}
+	for _, provider := range providers.Providers {
+		if provider == mockProvider {
+			return
+		}
+	}
+	t.Skip("Mock OIDC provider not configured")
+}
+
+// signInAtMock follows the authorization URL at the mock provider, which
+// signs in email at once, and returns the code and state of its redirect.
+func signInAtMock(t *testing.T, authorization OIDCAuthorization, email string, extra url.Values) map[string]any {
+	target, err := url.Parse(authorization.AuthorizationURL)
+	if err != nil {
+		t.Fatalf("Invalid authorization URL: %v", err)
+	}
+	// The API may reach the provider at another address than the tests
+	if mockURL := os.Getenv("FINANCIAL_API_MOCK_OIDC_URL"); mockURL != "" {
+		base, _ := url.Parse(mockURL)
+		target.Scheme, target.Host = base.Scheme, base.Host
+	}
+	query := target.Query()
+	query.Set("login_hint", email)
+	for key := range extra {
+		query.Set(key, extra.Get(key))
+	}
+	target.RawQuery = query.Encode()
+
+	client := &http.Client{
+		Timeout:       Timeout,
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+	resp, err := client.Get(target.String())
+	if err != nil {
+		t.Skipf("Mock OIDC provider not reachable: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("Expected a redirect from the provider, got %d", resp.StatusCode)
+	}
+	callback, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatalf("Invalid redirect: %v", err)
+	}
+	if callback.Query().Get("state") != authorization.State {
+		t.Fatalf("Expected the state to come back unchanged")
+	}
+	return map[string]any{"code": callback.Query().Get("code"), "state": authorization.State}
+}
+
+// startOIDC asks the API for an authorization URL at path.
+func startOIDC(t *testing.T, path, token string) OIDCAuthorization {
+	var authorization OIDCAuthorization
+	status := decodeInto(t, "POST", path, nil, token, &authorization)
+	if status == http.StatusUnauthorized {
+		t.Skip("Mock OIDC provider not reachable from the API")
+	}
+	if status != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", status)
+	}
+	return authorization
+}
+
+func oidcLogin(t *testing.T, email string, extra url.Values) (OIDCAuthResponse, int) {
+	callback := signInAtMock(t, startOIDC(t, mockAuthorizeEndpoint, ""), email, extra)
+
+	var response OIDCAuthResponse
+	status := decodeInto(t, "POST", oidcCallbackEndpoint, callback, "", &response)
+	return response, status
+}
+
+func TestOIDCLogin(t *testing.T) {
+	requireMockProvider(t)
+
+	t.Run("New account", func(t *testing.T) {
+		response, status := oidcLogin(t, oidcNewEmail, url.Values{"name": {oidcNewName}})
+		if status != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", status)
+		}
+		if response.Token == "" || response.User.Email != oidcNewEmail {
+			t.Fatalf("Expected a session for %s, got %+v", oidcNewEmail, response)
+		}
+		if len(response.User.Identities) != 1 || response.User.Identities[0].Provider != mockProvider {
+			t.Errorf("Expected the mock identity to be linked, got %+v", response.User.Identities)
+		}
+
+		again, status := oidcLogin(t, oidcNewEmail, nil)
+		if status != http.StatusOK || again.User.ID != response.User.ID {
+			t.Errorf("Expected the same account on the next sign in, got %d and %s", status, again.User.ID)
+		}
+
+		if status := decodeInto(t, "DELETE", mockUnlinkEndpoint, nil, response.Token, nil); status != http.StatusConflict {
+			t.Errorf("Expected status 409 unlinking the only sign in method, got %d", status)
+		}
+
+		// Without a password, the recent sign in confirms sensitive changes
+		if status := decodeInto(t, "POST", deleteRequestEndpoint, map[string]any{}, response.Token, nil); status != http.StatusOK {
+			t.Errorf("Expected status 200 requesting deletion without a password, got %d", status)
+		}
+	})
+
+	t.Run("Existing account by verified email", func(t *testing.T) {
+		existing := registerForTokens(t, oidcExistingEmail, oidcExistingName)
+
+		if _, status := oidcLogin(t, oidcExistingEmail, nil); status != http.StatusConflict {
+			t.Fatalf("Expected status 409 while the account email is unverified, got %d", status)
+		}
+
+		token := mailedToken(t, oidcExistingEmail)
+		if status := decodeInto(t, "POST", verifyEmailEndpoint, map[string]any{"token": token}, "", nil); status != http.StatusNoContent {
+			t.Fatalf("Expected status 204 verifying the email, got %d", status)
+		}
+
+		response, status := oidcLogin(t, oidcExistingEmail, nil)
+		if status != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", status)
+		}
+		if response.User.ID != existing.User.ID {
+			t.Errorf("Expected the existing account %s, got %s", existing.User.ID, response.User.ID)
+		}
+
+		var user OIDCUser
+		if status := decodeInto(t, "DELETE", mockUnlinkEndpoint, nil, response.Token, &user); status != http.StatusOK {
+			t.Fatalf("Expected status 200 unlinking, got %d", status)
+		}
+		if len(user.Identities) != 0 {
+			t.Errorf("Expected no identities after unlinking, got %+v", user.Identities)
+		}
+	})
+
+	t.Run("Unverified email", func(t *testing.T) {
+		if _, status := oidcLogin(t, oidcUnverifiedEmail, url.Values{"email_verified": {"false"}}); status != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d", status)
+		}
+	})
+
+	t.Run("State is single use", func(t *testing.T) {
+		callback := signInAtMock(t, startOIDC(t, mockAuthorizeEndpoint, ""), oidcNewEmail, nil)
+		if status := decodeInto(t, "POST", oidcCallbackEndpoint, callback, "", nil); status != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", status)
+		}
+		if status := decodeInto(t, "POST", oidcCallbackEndpoint, callback, "", nil); status != http.StatusBadRequest {
+			t.Errorf("Expected status 400 reusing the state, got %d", status)
+		}
+	})
+}
+
+func TestOIDCLink(t *testing.T) {
+	requireMockProvider(t)
+	session := registerForTokens(t, oidcLinkEmail, oidcLinkName)
+
+	// A link must be finished by the user who started it
+	callback := signInAtMock(t, startOIDC(t, mockLinkEndpoint, session.Token), oidcLinkIdentityEmail, nil)
+	if status := decodeInto(t, "POST", oidcCallbackEndpoint, callback, "", nil); status != http.StatusBadRequest {
+		t.Errorf("Expected status 400 finishing a link as a sign in, got %d", status)
+	}
+
+	callback = signInAtMock(t, startOIDC(t, mockLinkEndpoint, session.Token), oidcLinkIdentityEmail, nil)
+	var user OIDCUser
+	if status := decodeInto(t, "POST", oidcLinkEndpoint, callback, session.Token, &user); status != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", status)
+	}
+	if len(user.Identities) != 1 || user.Identities[0].Email != oidcLinkIdentityEmail {
+		t.Fatalf("Expected the identity of %s, got %+v", oidcLinkIdentityEmail, user.Identities)
+	}
+
+	response, status := oidcLogin(t, oidcLinkIdentityEmail, nil)
+	if status != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", status)
+	}
+	if response.User.ID != session.User.ID {
+		t.Errorf("Expected the linked identity to sign in to %s, got %s", session.User.ID, response.User.ID)
+	}
+}
@@ -75,6 +75,7 @@ type MonthlyItem struct {
 	Despesas      float64 `json:"despesas"`
 	Saldo         float64 `json:"saldo"`
 	Investimentos float64 `json:"investimentos"`
+	Proventos     float64 `json:"proventos"`
 }
 
 type CategoryItem struct {
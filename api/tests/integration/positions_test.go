@@ -0,0 +1,236 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const (
+	// Endpoints
+	tradesEndpoint = "/api/trades"
+	positionsEndpoint = "/api/positions"
+	quoteImportEndpoint = "/api/quotes/import"
+
+	// Test data
+	positionUserEmail = "positions@test.com"
+	positionUserName = "Position User"
+	oversellUserEmail = "oversell@test.com"
+	oversellUserName = "Oversell User"
+	backdatedUserEmail = "backdated@test.com"
+	backdatedUserName = "Backdated User"
+	assetClassUserEmail = "assetclass@test.com"
+	assetClassUserName = "Asset Class User"
+
+	positionTicker = "TSTA3"
+	oversellTicker = "TSTB3"
+	backdatedTicker = "TSTD3"
+	backdatedDate = "2024-10-01"
+	laterTradeDate = "2024-11-01"
+	assetClassTicker = "TSTC11"
+	stockClass = "stock"
+	fiiClass = "fii"
+	buySide = "buy"
+	sellSide = "sell"
+
+	// Trades: buy 100 @ 10, buy 100 @ 20, sell 50 @ 25
+	firstBuyPrice = 10.0
+	secondBuyPrice = 20.0
+	sellPrice = 25.0
+	buyQuantity = 100.0
+	sellQuantity = 50.0
+	quotePrice = 30.0
+
+	expectedAverageCost = 15.0
+	expectedQuantity = 150.0
+	expectedRealizedPnL = 500.0    // 50 * (25 - 15)
+	expectedMarketValue = 4500.0   // 150 * 30
+	expectedUnrealizedPnL = 2250.0 // 4500 - 150 * 15
+
+	fiiAssetClassName = "FIIs"
+)
+
+type Position struct {
+	Ticker        string       `json:"ticker"`
+	AssetClass    string       `json:"assetClass"`
+	Quantity      float64      `json:"quantity"`
+	AverageCost   float64      `json:"averageCost"`
+	LastPrice     float64      `json:"lastPrice"`
+	MarketValue   float64      `json:"marketValue"`
+	UnrealizedPnL float64      `json:"unrealizedPnl"`
+	RealizedPnL   float64      `json:"realizedPnl"`
+	Sales         []SaleResult `json:"sales"`
+}
+
+type SaleResult struct {
+	TradeID     string  `json:"tradeId"`
+	Quantity    float64 `json:"quantity"`
+	RealizedPnL float64 `json:"realizedPnl"`
+}
+
+func createTrade(t *testing.T, token string, trade map[string]interface{}) *http.Response {
+	resp, err := makeRequestWithAuth("POST", tradesEndpoint, trade, token)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	resp.Body.Close()
+	return resp
+}
+
+func importQuotes(t *testing.T, body string) {
+	req, err := http.NewRequest("POST", BaseURL+quoteImportEndpoint, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	req.Header.Set("Content-Type", "text/csv")
+	req.Header.Set("X-Operator-Token", OperatorToken)
+
+	resp, err := (&http.Client{Timeout: Timeout}).Do(req)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+}
+
+func TestPositionAverageCostAndPnL(t *testing.T) {
+	token, err := createAuthenticatedUser(positionUserEmail, positionUserName)
+	if err != nil {
+		t.Fatalf(failedCreateUserMsg, err)
+	}
+
+	trades := []map[string]interface{}{
+		{"ticker": positionTicker, "assetClass": stockClass, "side": buySide, "quantity": buyQuantity, "price": firstBuyPrice, "date": "2024-01-02"},
+		{"ticker": positionTicker, "assetClass": stockClass, "side": buySide, "quantity": buyQuantity, "price": secondBuyPrice, "date": "2024-02-01"},
+		{"ticker": positionTicker, "assetClass": stockClass, "side": sellSide, "quantity": sellQuantity, "price": sellPrice, "date": "2024-03-01"},
+	}
+
+	for _, trade := range trades {
+		if resp := createTrade(t, token, trade); resp.StatusCode != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d", resp.StatusCode)
+		}
+	}
+
+	importQuotes(t, "ticker,date,close\n"+positionTicker+",2024-03-28,30.00\n")
+
+	resp, err := makeRequestWithAuth("GET", positionsEndpoint+"/"+positionTicker, nil, token)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	defer resp.Body.Close()
+
+	var position Position
+	if err := json.NewDecoder(resp.Body).Decode(&position); err != nil {
+		t.Fatalf(failedDecodeMsg, err)
+	}
+
+	if position.Quantity != expectedQuantity {
+		t.Errorf("Expected quantity %f, got %f", expectedQuantity, position.Quantity)
+	}
+
+	if position.AverageCost != expectedAverageCost {
+		t.Errorf("Expected average cost %f, got %f", expectedAverageCost, position.AverageCost)
+	}
+
+	if position.LastPrice != quotePrice {
+		t.Errorf("Expected last price %f, got %f", quotePrice, position.LastPrice)
+	}
+
+	if position.MarketValue != expectedMarketValue {
+		t.Errorf("Expected market value %f, got %f", expectedMarketValue, position.MarketValue)
+	}
+
+	if position.UnrealizedPnL != expectedUnrealizedPnL {
+		t.Errorf("Expected unrealized P&L %f, got %f", expectedUnrealizedPnL, position.UnrealizedPnL)
+	}
+
+	if len(position.Sales) != 1 || position.Sales[0].RealizedPnL != expectedRealizedPnL {
+		t.Errorf("Expected one sale realizing %f, got %+v", expectedRealizedPnL, position.Sales)
+	}
+}
+
+func TestSellMoreThanHeld(t *testing.T) {
+	token, err := createAuthenticatedUser(oversellUserEmail, oversellUserName)
+	if err != nil {
+		t.Fatalf(failedCreateUserMsg, err)
+	}
+
+	createTrade(t, token, map[string]interface{}{
+		"ticker": oversellTicker, "assetClass": stockClass, "side": buySide, "quantity": sellQuantity, "price": firstBuyPrice, "date": testDate,
+	})
+
+	resp := createTrade(t, token, map[string]interface{}{
+		"ticker": oversellTicker, "assetClass": stockClass, "side": sellSide, "quantity": buyQuantity, "price": sellPrice, "date": testDate,
+	})
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", resp.StatusCode)
+	}
+}
+
+func TestBackdatedSellUncoveringLaterSell(t *testing.T) {
+	token, err := createAuthenticatedUser(backdatedUserEmail, backdatedUserName)
+	if err != nil {
+		t.Fatalf(failedCreateUserMsg, err)
+	}
+
+	createTrade(t, token, map[string]interface{}{
+		"ticker": backdatedTicker, "assetClass": stockClass, "side": buySide, "quantity": sellQuantity, "price": firstBuyPrice, "date": backdatedDate,
+	})
+	resp := createTrade(t, token, map[string]interface{}{
+		"ticker": backdatedTicker, "assetClass": stockClass, "side": sellSide, "quantity": sellQuantity, "price": sellPrice, "date": laterTradeDate,
+	})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d", resp.StatusCode)
+	}
+
+	// The position held on the backdated sell covers it, but the later sell
+	// would then sell shares no longer held
+	resp = createTrade(t, token, map[string]interface{}{
+		"ticker": backdatedTicker, "assetClass": stockClass, "side": sellSide, "quantity": sellQuantity, "price": sellPrice, "date": testDate,
+	})
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", resp.StatusCode)
+	}
+}
+
+func TestOverviewGroupByAssetClass(t *testing.T) {
+	token, err := createAuthenticatedUser(assetClassUserEmail, assetClassUserName)
+	if err != nil {
+		t.Fatalf(failedCreateUserMsg, err)
+	}
+
+	createTestInvestment(t, token, investmentAmount1)
+	createTrade(t, token, map[string]interface{}{
+		"ticker": assetClassTicker, "assetClass": fiiClass, "side": buySide, "quantity": buyQuantity, "price": firstBuyPrice, "date": testDate,
+	})
+
+	resp, err := makeRequestWithAuth("GET", overviewEndpoint+"?groupBy=assetClass", nil, token)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	defer resp.Body.Close()
+
+	var overview OverviewData
+	if err := json.NewDecoder(resp.Body).Decode(&overview); err != nil {
+		t.Fatalf(failedDecodeMsg, err)
+	}
+
+	found := false
+	for _, group := range overview.InvestmentTypes {
+		if group.Name == fiiAssetClassName {
+			found = true
+			if group.Value != buyQuantity*firstBuyPrice {
+				t.Errorf("Expected FII value %f, got %f", buyQuantity*firstBuyPrice, group.Value)
+			}
+		}
+	}
+
+	if !found {
+		t.Errorf("Expected %s in investment types, got %+v", fiiAssetClassName, overview.InvestmentTypes)
+	}
+}
@@ -0,0 +1,131 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+)
+
+const (
+	// Endpoints
+	deleteRequestEndpoint = "/api/auth/me/delete-request"
+
+	// Test data
+	profileEmail = "profile@example.com"
+	profileUserName = "Profile User"
+	profileNewEmail = "profile.new@example.com"
+	profileNewName = "Renamed User"
+	rejectedName = "Rejected Rename"
+	takenEmail = "taken@example.com"
+	takenUserName = "Taken User"
+	deletionEmail = "deletion@example.com"
+	deletionUserName = "Deletion User"
+)
+
+func TestUpdateProfile(t *testing.T) {
+	session := registerForTokens(t, profileEmail, profileUserName)
+	other := loginForTokens(t, profileEmail)
+
+	t.Run("Name", func(t *testing.T) {
+		var user User
+		if status := decodeInto(t, "PATCH", meEndpoint, map[string]any{"name": profileNewName}, session.Token, &user); status != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", status)
+		}
+		if user.Name != profileNewName {
+			t.Errorf("Expected name %s, got %s", profileNewName, user.Name)
+		}
+	})
+
+	t.Run("Email requires the current password", func(t *testing.T) {
+		if status := decodeInto(t, "PATCH", meEndpoint, map[string]any{"email": profileNewEmail}, session.Token, nil); status != http.StatusUnauthorized {
+			t.Errorf("Expected status 401 without the current password, got %d", status)
+		}
+		body := map[string]any{"email": profileNewEmail, "currentPassword": wrongPassword}
+		if status := decodeInto(t, "PATCH", meEndpoint, body, session.Token, nil); status != http.StatusUnauthorized {
+			t.Errorf("Expected status 401 with a wrong password, got %d", status)
+		}
+		registerForTokens(t, takenEmail, takenUserName)
+		body = map[string]any{"name": rejectedName, "email": takenEmail, "currentPassword": testPassword}
+		if status := decodeInto(t, "PATCH", meEndpoint, body, session.Token, nil); status != http.StatusConflict {
+			t.Errorf("Expected status 409 for an email in use, got %d", status)
+		}
+		var user User
+		if status := decodeInto(t, "GET", meEndpoint, nil, session.Token, &user); status != http.StatusOK || user.Name != profileNewName {
+			t.Errorf("Expected the rejected request to keep name %s, got %d %s", profileNewName, status, user.Name)
+		}
+	})
+
+	t.Run("Email", func(t *testing.T) {
+		var user struct {
+			Email           string  `json:"email"`
+			EmailVerifiedAt *string `json:"emailVerifiedAt"`
+		}
+		body := map[string]any{"email": profileNewEmail, "currentPassword": testPassword}
+		if status := decodeInto(t, "PATCH", meEndpoint, body, session.Token, &user); status != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", status)
+		}
+		if user.Email != profileNewEmail || user.EmailVerifiedAt != nil {
+			t.Errorf("Expected the new email pending verification, got %+v", user)
+		}
+	})
+
+	t.Run("Password", func(t *testing.T) {
+		var key APIKey
+		keyBody := map[string]any{"name": apiKeyName, "scopes": []string{"transactions:read"}}
+		if status := decodeInto(t, "POST", apiKeysEndpoint, keyBody, session.Token, &key); status != http.StatusCreated {
+			t.Fatalf("Expected status 201 creating a key, got %d", status)
+		}
+
+		body := map[string]any{"newPassword": newPassword, "currentPassword": testPassword}
+		if status := decodeInto(t, "PATCH", meEndpoint, body, session.Token, nil); status != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", status)
+		}
+		if got := meStatus(t, session.Token); got != http.StatusOK {
+			t.Errorf("Expected the current session to stay, got %d", got)
+		}
+		if got := meStatus(t, other.Token); got != http.StatusUnauthorized {
+			t.Errorf("Expected other sessions to be revoked, got %d", got)
+		}
+		if status := decodeInto(t, "GET", transactionsEndpoint, nil, key.Key, nil); status != http.StatusUnauthorized {
+			t.Errorf("Expected API keys to be revoked, got %d", status)
+		}
+		if status, _ := loginStatus(t, profileNewEmail, newPassword); status != http.StatusOK {
+			t.Errorf("Expected login with the new email and password, got %d", status)
+		}
+	})
+}
+
+func TestDeleteAccount(t *testing.T) {
+	session := registerForTokens(t, deletionEmail, deletionUserName)
+
+	resp, err := makeRequestWithAuth("POST", transactionsEndpoint, map[string]any{
+		"type": expenseType, "description": testInvestmentName, "amount": 10, "date": testDate,
+	}, session.Token)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	resp.Body.Close()
+
+	if status := decodeInto(t, "POST", deleteRequestEndpoint, map[string]any{"password": wrongPassword}, session.Token, nil); status != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 with a wrong password, got %d", status)
+	}
+	if status := decodeInto(t, "DELETE", meEndpoint, map[string]any{"confirmationToken": randomToken}, session.Token, nil); status != http.StatusBadRequest {
+		t.Errorf("Expected status 400 without a confirmation, got %d", status)
+	}
+
+	var confirmation struct {
+		ConfirmationToken string `json:"confirmationToken"`
+	}
+	if status := decodeInto(t, "POST", deleteRequestEndpoint, map[string]any{"password": testPassword}, session.Token, &confirmation); status != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", status)
+	}
+	if status := decodeInto(t, "DELETE", meEndpoint, map[string]any{"confirmationToken": confirmation.ConfirmationToken}, session.Token, nil); status != http.StatusNoContent {
+		t.Fatalf("Expected status 204, got %d", status)
+	}
+
+	if got := meStatus(t, session.Token); got != http.StatusUnauthorized {
+		t.Errorf("Expected the session to be gone, got %d", got)
+	}
+	if status, _ := loginStatus(t, deletionEmail, testPassword); status != http.StatusUnauthorized {
+		t.Errorf("Expected the account to be gone, got %d", status)
+	}
+}
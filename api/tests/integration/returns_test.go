@@ -0,0 +1,117 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+const (
+	// Endpoints
+	portfolioPerformanceEndpoint = "/api/performance"
+
+	// Test data
+	returnsUserEmail = "returns@test.com"
+	returnsUserName = "Returns User"
+
+	returnsStartDate = "2023-01-02"
+	returnsContributionDate = "2023-07-03"
+	returnsFrom = "2023-01-01"
+	returnsTo = "2024-01-01"
+	returnsRate = 12.0
+	expectedBenchmarks = 2
+)
+
+type PerformanceReport struct {
+	From            string   `json:"from"`
+	To              string   `json:"to"`
+	StartValue      float64  `json:"startValue"`
+	EndValue        float64  `json:"endValue"`
+	NetContribution float64  `json:"netContribution"`
+	TWR             float64  `json:"twr"`
+	XIRR            *float64 `json:"xirr"`
+	Benchmarks      []struct {
+		Name      string  `json:"name"`
+		Return    float64 `json:"return"`
+		Available bool    `json:"available"`
+	} `json:"benchmarks"`
+}
+
+func TestPortfolioReturns(t *testing.T) {
+	token, err := createAuthenticatedUser(returnsUserEmail, returnsUserName)
+	if err != nil {
+		t.Fatalf(failedCreateUserMsg, err)
+	}
+
+	resp, err := makeRequestWithAuth("POST", investmentsEndpoint, map[string]interface{}{
+		"name": testInvestmentName, "amount": investmentAmount1, "rate": returnsRate, "date": returnsStartDate, "type": cdbType,
+	}, token)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	defer resp.Body.Close()
+
+	var investment Investment
+	if err := json.NewDecoder(resp.Body).Decode(&investment); err != nil {
+		t.Fatalf(failedDecodeMsg, err)
+	}
+
+	movementResp, err := makeRequestWithAuth("POST", movementsEndpoint(investment.ID), map[string]interface{}{
+		"type": contributionMovement, "amount": investmentAmount2, "date": returnsContributionDate,
+	}, token)
+	if err != nil {
+		t.Fatalf(failedCreateMovementMsg, err)
+	}
+	movementResp.Body.Close()
+
+	path := fmt.Sprintf("%s?from=%s&to=%s", portfolioPerformanceEndpoint, returnsFrom, returnsTo)
+	perfResp, err := makeRequestWithAuth("GET", path, nil, token)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	defer perfResp.Body.Close()
+
+	if perfResp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", perfResp.StatusCode)
+	}
+
+	var report PerformanceReport
+	if err := json.NewDecoder(perfResp.Body).Decode(&report); err != nil {
+		t.Fatalf(failedDecodeMsg, err)
+	}
+
+	if report.NetContribution != investmentAmount1+investmentAmount2 {
+		t.Errorf("Expected net contribution %f, got %f", investmentAmount1+investmentAmount2, report.NetContribution)
+	}
+
+	// A fixed-rate portfolio returns close to its rate regardless of when money came in
+	if report.TWR <= 0 || report.TWR > returnsRate {
+		t.Errorf("Expected TWR between 0 and %f, got %f", returnsRate, report.TWR)
+	}
+
+	if report.XIRR == nil || *report.XIRR <= 0 {
+		t.Errorf("Expected positive XIRR, got %v", report.XIRR)
+	}
+
+	if len(report.Benchmarks) != expectedBenchmarks {
+		t.Errorf("Expected %d benchmarks, got %d", expectedBenchmarks, len(report.Benchmarks))
+	}
+}
+
+func TestPortfolioReturnsInvalidPeriod(t *testing.T) {
+	token, err := createAuthenticatedUser("returnsperiod@test.com", "Returns Period User")
+	if err != nil {
+		t.Fatalf(failedCreateUserMsg, err)
+	}
+
+	resp, err := makeRequestWithAuth("GET", portfolioPerformanceEndpoint+"?period=2w", nil, token)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", resp.StatusCode)
+	}
+}
@@ -11,8 +11,9 @@ import (
 )
 
 var (
-	BaseURL = getBaseURL()
-	Timeout = 30 * time.Second
+	BaseURL       = getBaseURL()
+	OperatorToken = getOperatorToken()
+	Timeout       = 30 * time.Second
 )
 
 func getBaseURL() string {
@@ -22,6 +23,15 @@ func getBaseURL() string {
 	return "http://localhost:8080"
 }
 
+// getOperatorToken returns the API's OPERATOR_TOKEN, which market data
+// uploads need.
+func getOperatorToken() string {
+	if token := os.Getenv("FINANCIAL_API_OPERATOR_TOKEN"); token != "" {
+		return token
+	}
+	return "test-operator-token"
+}
+
 func TestMain(m *testing.M) {
 	// Wait for API to be ready
 	if !waitForAPI() {
@@ -106,8 +116,9 @@ func makeRequestWithAuth(method, path string, body interface{}, token string) (*
 }
 
 type AuthResponse struct {
-	Token string `json:"token"`
-	User  User   `json:"user"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refreshToken"`
+	User         User   `json:"user"`
 }
 
 type User struct {
@@ -0,0 +1,117 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+)
+
+const (
+	// Endpoints
+	subscriptionsEndpoint = "/api/subscriptions"
+	confirmSubscriptionEndpoint = "/api/subscriptions/confirm"
+
+	// Test data
+	subscriptionUserEmail = "subscriptions@test.com"
+	subscriptionUserName = "Subscription User"
+	streamingDescription = "NETFLIX.COM"
+	streamingMerchant = "Netflix.com"
+	unknownMerchant = "unknown merchant"
+
+	oldStreamingPrice = 39.90
+	newStreamingPrice = 55.90 // More than the amount tolerance above the old price
+	expectedAnnualCost = 670.80 // 55.90 * 12
+)
+
+type Subscription struct {
+	Merchant        string  `json:"merchant"`
+	Frequency       string  `json:"frequency"`
+	Amount          float64 `json:"amount"`
+	PriceIncrease   bool    `json:"priceIncrease"`
+	NextCharge      string  `json:"nextCharge"`
+	AnnualCost      float64 `json:"annualCost"`
+	RecurringItemID *string `json:"recurringItemId"`
+}
+
+func getSubscriptions(t *testing.T, token string) []Subscription {
+	resp, err := makeRequestWithAuth("GET", subscriptionsEndpoint, nil, token)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	defer resp.Body.Close()
+
+	var subscriptions []Subscription
+	if err := json.NewDecoder(resp.Body).Decode(&subscriptions); err != nil {
+		t.Fatalf(failedDecodeMsg, err)
+	}
+	return subscriptions
+}
+
+func TestSubscriptionDetection(t *testing.T) {
+	token, err := createAuthenticatedUser(subscriptionUserEmail, subscriptionUserName)
+	if err != nil {
+		t.Fatalf(failedCreateUserMsg, err)
+	}
+
+	// Charged on the first of the last four months, with a price increase
+	now := time.Now().UTC()
+	firstOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for months := 3; months >= 0; months-- {
+		amount := oldStreamingPrice
+		if months == 0 {
+			amount = newStreamingPrice
+		}
+		resp, err := makeRequestWithAuth("POST", transactionsEndpoint, map[string]interface{}{
+			"type": expenseType, "description": streamingDescription, "amount": amount, "date": firstOfMonth.AddDate(0, -months, 0).Format("2006-01-02"),
+		}, token)
+		if err != nil {
+			t.Fatalf(failedRequestMsg, err)
+		}
+		resp.Body.Close()
+	}
+
+	subscriptions := getSubscriptions(t, token)
+	if len(subscriptions) != 1 {
+		t.Fatalf("Expected 1 subscription, got %+v", subscriptions)
+	}
+
+	subscription := subscriptions[0]
+	if subscription.Merchant != streamingMerchant || subscription.Frequency != monthlyFrequency {
+		t.Errorf("Expected a monthly %s subscription, got %+v", streamingMerchant, subscription)
+	}
+	if !subscription.PriceIncrease || subscription.Amount != newStreamingPrice || subscription.AnnualCost != expectedAnnualCost {
+		t.Errorf("Expected a price increase to %f costing %f a year, got %+v", newStreamingPrice, expectedAnnualCost, subscription)
+	}
+	if expected := firstOfMonth.AddDate(0, 1, 0).Format("2006-01-02"); subscription.NextCharge != expected {
+		t.Errorf("Expected next charge on %s, got %s", expected, subscription.NextCharge)
+	}
+
+	t.Run("Confirm as recurring item", func(t *testing.T) {
+		resp, err := makeRequestWithAuth("POST", confirmSubscriptionEndpoint, map[string]interface{}{"merchant": streamingMerchant}, token)
+		if err != nil {
+			t.Fatalf(failedRequestMsg, err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d", resp.StatusCode)
+		}
+
+		if got := getSubscriptions(t, token); len(got) != 1 || got[0].RecurringItemID == nil {
+			t.Errorf("Expected the subscription to be linked to a recurring item, got %+v", got)
+		}
+	})
+
+	t.Run("Unknown merchant", func(t *testing.T) {
+		resp, err := makeRequestWithAuth("POST", confirmSubscriptionEndpoint, map[string]interface{}{"merchant": unknownMerchant}, token)
+		if err != nil {
+			t.Fatalf(failedRequestMsg, err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", resp.StatusCode)
+		}
+	})
+}
@@ -0,0 +1,158 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"testing"
+)
+
+const (
+	// Test data
+	taxUserEmail = "taxes@test.com"
+	taxUserName = "Tax User"
+	exemptUserEmail = "taxexempt@test.com"
+	exemptUserName = "Tax Exempt User"
+	shortTermUserEmail = "taxshortterm@test.com"
+	shortTermUserName = "Short Term Tax User"
+
+	// Dates: more than 720 days between application and valuation
+	longTermStartDate = "2021-01-04"
+	longTermValuationDate = "2024-01-02"
+	longTermIncomeTaxRate = 0.15
+
+	// Dates: redeemed within the 30 days IOF applies
+	shortTermStartDate = "2024-01-02"
+	shortTermValuationDate = "2024-01-12"
+
+	// Tolerance for float comparisons
+	taxTolerance = 0.01
+)
+
+func createInvestmentAt(t *testing.T, token, investmentType, date string) Investment {
+	payload := map[string]interface{}{
+		"name":   testInvestmentName,
+		"amount": investmentAmount1,
+		"rate":   rate110,
+		"date":   date,
+		"type":   investmentType,
+	}
+
+	resp, err := makeRequestWithAuth("POST", investmentsEndpoint, payload, token)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	defer resp.Body.Close()
+
+	var investment Investment
+	if err := json.NewDecoder(resp.Body).Decode(&investment); err != nil {
+		t.Fatalf(failedDecodeMsg, err)
+	}
+	return investment
+}
+
+func getValuation(t *testing.T, token, investmentID, date string) InvestmentValuation {
+	path := fmt.Sprintf("%s/%s/valuation?date=%s", investmentsEndpoint, investmentID, date)
+	resp, err := makeRequestWithAuth("GET", path, nil, token)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	defer resp.Body.Close()
+
+	var valuation InvestmentValuation
+	if err := json.NewDecoder(resp.Body).Decode(&valuation); err != nil {
+		t.Fatalf(failedDecodeMsg, err)
+	}
+	return valuation
+}
+
+func TestRegressiveIncomeTax(t *testing.T) {
+	token, err := createAuthenticatedUser(taxUserEmail, taxUserName)
+	if err != nil {
+		t.Fatalf(failedCreateUserMsg, err)
+	}
+
+	investment := createInvestmentAt(t, token, cdbType, longTermStartDate)
+	valuation := getValuation(t, token, investment.ID, longTermValuationDate)
+
+	if valuation.Earnings <= 0 {
+		t.Fatalf("Expected positive earnings, got %f", valuation.Earnings)
+	}
+
+	if valuation.IOF != 0 {
+		t.Errorf("Expected no IOF after 30 days, got %f", valuation.IOF)
+	}
+
+	expectedTax := valuation.Earnings * longTermIncomeTaxRate
+	if math.Abs(valuation.IncomeTax-expectedTax) > taxTolerance {
+		t.Errorf("Expected income tax %f, got %f", expectedTax, valuation.IncomeTax)
+	}
+
+	if math.Abs(valuation.NetValue-(valuation.GrossValue-valuation.TaxDue)) > taxTolerance {
+		t.Errorf("Expected net value %f, got %f", valuation.GrossValue-valuation.TaxDue, valuation.NetValue)
+	}
+}
+
+func TestTaxExemptInvestment(t *testing.T) {
+	token, err := createAuthenticatedUser(exemptUserEmail, exemptUserName)
+	if err != nil {
+		t.Fatalf(failedCreateUserMsg, err)
+	}
+
+	investment := createInvestmentAt(t, token, lciType, longTermStartDate)
+	valuation := getValuation(t, token, investment.ID, longTermValuationDate)
+
+	if valuation.TaxDue != 0 {
+		t.Errorf("Expected LCI to be tax exempt, got tax %f", valuation.TaxDue)
+	}
+
+	if valuation.NetValue != valuation.GrossValue {
+		t.Errorf("Expected net value %f, got %f", valuation.GrossValue, valuation.NetValue)
+	}
+}
+
+func TestIncomeTaxExemptInvestmentPaysIOF(t *testing.T) {
+	token, err := createAuthenticatedUser(shortTermUserEmail, shortTermUserName)
+	if err != nil {
+		t.Fatalf(failedCreateUserMsg, err)
+	}
+
+	investment := createInvestmentAt(t, token, lciType, shortTermStartDate)
+	valuation := getValuation(t, token, investment.ID, shortTermValuationDate)
+
+	if valuation.IOF <= 0 {
+		t.Errorf("Expected LCI redeemed within 30 days to pay IOF, got %f", valuation.IOF)
+	}
+
+	if valuation.IncomeTax != 0 {
+		t.Errorf("Expected LCI to be exempt from income tax, got %f", valuation.IncomeTax)
+	}
+}
+
+func TestDashboardNetTotals(t *testing.T) {
+	token, err := createAuthenticatedUser("taxdashboard@test.com", "Tax Dashboard User")
+	if err != nil {
+		t.Fatalf(failedCreateUserMsg, err)
+	}
+
+	createInvestmentAt(t, token, cdbType, longTermStartDate)
+
+	resp, err := makeRequestWithAuth("GET", dashboardSummaryEndpoint, nil, token)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	defer resp.Body.Close()
+
+	var summary DashboardSummary
+	if err := json.NewDecoder(resp.Body).Decode(&summary); err != nil {
+		t.Fatalf(failedDecodeMsg, err)
+	}
+
+	if summary.Totals.TotalTaxDue <= 0 {
+		t.Errorf("Expected tax due to be positive, got %f", summary.Totals.TotalTaxDue)
+	}
+
+	if math.Abs(summary.Totals.TotalNetValue-(summary.Totals.TotalGrossValue-summary.Totals.TotalTaxDue)) > taxTolerance {
+		t.Errorf("Expected net value to be gross minus tax, got %f", summary.Totals.TotalNetValue)
+	}
+}
@@ -0,0 +1,211 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"financial-api/internal/totp"
+)
+
+const (
+	// Endpoints
+	twoFactorEnrollEndpoint = "/api/auth/2fa/enroll"
+	twoFactorConfirmEndpoint = "/api/auth/2fa/confirm"
+	twoFactorVerifyEndpoint = "/api/auth/2fa/verify"
+	twoFactorDisableEndpoint = "/api/auth/2fa/disable"
+	recoveryCodesEndpoint = "/api/auth/2fa/recovery-codes"
+
+	// Test data
+	twoFactorEmail = "twofactor@example.com"
+	twoFactorUserName = "Two Factor User"
+	twoFactorLockoutEmail = "twofactor.lockout@example.com"
+	twoFactorSessionEmail = "twofactor.session@example.com"
+	maxTwoFactorFailures = 5
+	wrongTOTPCode = "000000"
+	expectedRecoveryCodes = 10
+)
+
+type TwoFactorEnrollment struct {
+	Secret     string `json:"secret"`
+	OtpauthURI string `json:"otpauthUri"`
+}
+
+type TwoFactorChallenge struct {
+	Token             string `json:"token"`
+	TwoFactorRequired bool   `json:"twoFactorRequired"`
+	ChallengeToken    string `json:"challengeToken"`
+}
+
+func decodeInto(t *testing.T, method, path string, body map[string]any, token string, target any) int {
+	resp, err := makeRequestWithAuth(method, path, body, token)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 300 && target != nil {
+		if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+			t.Fatalf(failedDecodeMsg, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+// totpCode returns the code of the next step, which the API accepts for clock
+// drift and which was not used by an earlier request in the current step.
+func totpCode(t *testing.T, secret string, steps int) string {
+	code, err := totp.Code(secret, time.Now().Add(time.Duration(steps)*totp.Period))
+	if err != nil {
+		t.Fatalf("Failed to generate code: %v", err)
+	}
+	return code
+}
+
+func TestTwoFactorAuthentication(t *testing.T) {
+	session := registerForTokens(t, twoFactorEmail, twoFactorUserName)
+
+	var enrollment TwoFactorEnrollment
+	if status := decodeInto(t, "POST", twoFactorEnrollEndpoint, nil, session.Token, &enrollment); status != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", status)
+	}
+	if enrollment.Secret == "" || enrollment.OtpauthURI == "" {
+		t.Fatalf("Expected a secret and an otpauth URI, got %+v", enrollment)
+	}
+
+	if status := decodeInto(t, "POST", twoFactorConfirmEndpoint, map[string]any{"code": wrongTOTPCode}, session.Token, nil); status != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for a wrong code, got %d", status)
+	}
+
+	var recovery struct {
+		RecoveryCodes []string `json:"recoveryCodes"`
+	}
+	if status := decodeInto(t, "POST", twoFactorConfirmEndpoint, map[string]any{"code": totpCode(t, enrollment.Secret, 0)}, session.Token, &recovery); status != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", status)
+	}
+	if len(recovery.RecoveryCodes) != expectedRecoveryCodes {
+		t.Fatalf("Expected %d recovery codes, got %d", expectedRecoveryCodes, len(recovery.RecoveryCodes))
+	}
+
+	login := func(t *testing.T) TwoFactorChallenge {
+		var challenge TwoFactorChallenge
+		status := decodeInto(t, "POST", loginEndpoint, map[string]any{"email": twoFactorEmail, "password": testPassword}, "", &challenge)
+		if status != http.StatusOK || !challenge.TwoFactorRequired || challenge.Token != "" {
+			t.Fatalf("Expected a challenge without a token, got %d %+v", status, challenge)
+		}
+		return challenge
+	}
+
+	t.Run("Wrong code", func(t *testing.T) {
+		challenge := login(t)
+		status := decodeInto(t, "POST", twoFactorVerifyEndpoint, map[string]any{"challengeToken": challenge.ChallengeToken, "code": wrongTOTPCode}, "", nil)
+		if status != http.StatusUnauthorized {
+			t.Errorf("Expected status 401, got %d", status)
+		}
+	})
+
+	t.Run("TOTP code", func(t *testing.T) {
+		challenge := login(t)
+		code := totpCode(t, enrollment.Secret, 1)
+
+		var authResp AuthResponse
+		status := decodeInto(t, "POST", twoFactorVerifyEndpoint, map[string]any{"challengeToken": challenge.ChallengeToken, "code": code}, "", &authResp)
+		if status != http.StatusOK || authResp.Token == "" {
+			t.Fatalf("Expected a token after the second step, got %d", status)
+		}
+
+		replay := login(t)
+		status = decodeInto(t, "POST", twoFactorVerifyEndpoint, map[string]any{"challengeToken": replay.ChallengeToken, "code": code}, "", nil)
+		if status != http.StatusUnauthorized {
+			t.Errorf("Expected a replayed code to be rejected, got %d", status)
+		}
+	})
+
+	t.Run("Recovery code", func(t *testing.T) {
+		code := recovery.RecoveryCodes[0]
+		challenge := login(t)
+		var authResp AuthResponse
+		status := decodeInto(t, "POST", twoFactorVerifyEndpoint, map[string]any{"challengeToken": challenge.ChallengeToken, "code": code}, "", &authResp)
+		if status != http.StatusOK || authResp.Token == "" {
+			t.Fatalf("Expected a token with a recovery code, got %d", status)
+		}
+
+		again := login(t)
+		status = decodeInto(t, "POST", twoFactorVerifyEndpoint, map[string]any{"challengeToken": again.ChallengeToken, "code": code}, "", nil)
+		if status != http.StatusUnauthorized {
+			t.Errorf("Expected the recovery code to be single-use, got %d", status)
+		}
+
+		status = decodeInto(t, "POST", twoFactorDisableEndpoint, map[string]any{"password": testPassword, "code": recovery.RecoveryCodes[1]}, authResp.Token, nil)
+		if status != http.StatusNoContent {
+			t.Fatalf("Expected status 204 when disabling, got %d", status)
+		}
+		if token := loginForTokens(t, twoFactorEmail).Token; token == "" {
+			t.Error("Expected a token right after the password once disabled")
+		}
+	})
+}
+
+func TestTwoFactorLockout(t *testing.T) {
+	session := registerForTokens(t, twoFactorLockoutEmail, twoFactorUserName)
+
+	var enrollment TwoFactorEnrollment
+	if status := decodeInto(t, "POST", twoFactorEnrollEndpoint, nil, session.Token, &enrollment); status != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", status)
+	}
+	if status := decodeInto(t, "POST", twoFactorConfirmEndpoint, map[string]any{"code": totpCode(t, enrollment.Secret, 0)}, session.Token, nil); status != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", status)
+	}
+
+	// Each correct password issues a new challenge, which must not reset
+	// the wrong codes counted against the user
+	verify := func(t *testing.T, code string) int {
+		var challenge TwoFactorChallenge
+		if status := decodeInto(t, "POST", loginEndpoint, map[string]any{"email": twoFactorLockoutEmail, "password": testPassword}, "", &challenge); status != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", status)
+		}
+		return decodeInto(t, "POST", twoFactorVerifyEndpoint, map[string]any{"challengeToken": challenge.ChallengeToken, "code": code}, "", nil)
+	}
+
+	for i := 0; i < maxTwoFactorFailures; i++ {
+		if status := verify(t, wrongTOTPCode); status != http.StatusUnauthorized {
+			t.Fatalf("Expected status 401 for wrong code %d, got %d", i+1, status)
+		}
+	}
+
+	if status := verify(t, totpCode(t, enrollment.Secret, 1)); status != http.StatusTooManyRequests {
+		t.Errorf("Expected status 429 once the second factor is locked, got %d", status)
+	}
+}
+
+func TestTwoFactorLockoutWithSession(t *testing.T) {
+	session := registerForTokens(t, twoFactorSessionEmail, twoFactorUserName)
+
+	var enrollment TwoFactorEnrollment
+	if status := decodeInto(t, "POST", twoFactorEnrollEndpoint, nil, session.Token, &enrollment); status != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", status)
+	}
+	if status := decodeInto(t, "POST", twoFactorConfirmEndpoint, map[string]any{"code": totpCode(t, enrollment.Secret, 0)}, session.Token, nil); status != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", status)
+	}
+
+	t.Run("Disable requires the password", func(t *testing.T) {
+		body := map[string]any{"password": wrongPassword, "code": wrongTOTPCode}
+		if status := decodeInto(t, "POST", twoFactorDisableEndpoint, body, session.Token, nil); status != http.StatusUnauthorized {
+			t.Errorf("Expected status 401 with a wrong password, got %d", status)
+		}
+	})
+
+	// A stolen session guesses codes under the same lockout as the login
+	for i := 0; i < maxTwoFactorFailures; i++ {
+		if status := decodeInto(t, "POST", recoveryCodesEndpoint, map[string]any{"code": wrongTOTPCode}, session.Token, nil); status != http.StatusBadRequest {
+			t.Fatalf("Expected status 400 for wrong code %d, got %d", i+1, status)
+		}
+	}
+
+	body := map[string]any{"password": testPassword, "code": totpCode(t, enrollment.Secret, 1)}
+	if status := decodeInto(t, "POST", twoFactorDisableEndpoint, body, session.Token, nil); status != http.StatusTooManyRequests {
+		t.Errorf("Expected status 429 once the second factor is locked, got %d", status)
+	}
+}
@@ -0,0 +1,227 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+const (
+	// Endpoints
+	workspacesEndpoint = "/api/workspaces"
+	acceptInvitationEndpoint = "/api/workspaces/invitations/accept"
+
+	// Test data
+	workspaceOwnerEmail = "household.owner@example.com"
+	workspaceOwnerName = "Household Owner"
+	workspaceMemberEmail = "household.member@example.com"
+	workspaceMemberName = "Household Member"
+	workspaceOutsiderEmail = "household.outsider@example.com"
+	workspaceOutsiderName = "Household Outsider"
+	workspaceName = "Casa"
+	sharedExpenseDesc = "Supermercado da casa"
+)
+
+type Workspace struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Personal bool   `json:"personal"`
+	Role     string `json:"role"`
+}
+
+type WorkspaceMember struct {
+	UserID string `json:"userId"`
+	Role   string `json:"role"`
+	Email  string `json:"email"`
+}
+
+// inWorkspace makes an authenticated request to the workspace selected by
+// the X-Workspace-ID header and decodes a successful response into target.
+func inWorkspace(t *testing.T, method, path string, body map[string]any, token, workspaceID string, target any) int {
+	var reqBody bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&reqBody).Encode(body); err != nil {
+			t.Fatalf("Failed to encode body: %v", err)
+		}
+	}
+
+	req, err := http.NewRequest(method, BaseURL+path, &reqBody)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("X-Workspace-ID", workspaceID)
+
+	resp, err := (&http.Client{Timeout: Timeout}).Do(req)
+	if err != nil {
+		t.Fatalf(failedRequestMsg, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 300 && target != nil {
+		if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+			t.Fatalf(failedDecodeMsg, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+func TestWorkspaces(t *testing.T) {
+	owner := registerForTokens(t, workspaceOwnerEmail, workspaceOwnerName)
+	member := registerForTokens(t, workspaceMemberEmail, workspaceMemberName)
+	outsider := registerForTokens(t, workspaceOutsiderEmail, workspaceOutsiderName)
+
+	var workspace Workspace
+	if status := decodeInto(t, "POST", workspacesEndpoint, map[string]any{"name": workspaceName}, owner.Token, &workspace); status != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d", status)
+	}
+	if workspace.Personal || workspace.Role != "owner" {
+		t.Fatalf("Expected a shared workspace owned by the user, got %+v", workspace)
+	}
+	workspacePath := workspacesEndpoint + "/" + workspace.ID
+	expense := map[string]any{"type": expenseType, "description": sharedExpenseDesc, "amount": 250.0, "date": testDate}
+
+	t.Run("List", func(t *testing.T) {
+		var workspaces []Workspace
+		if status := decodeInto(t, "GET", workspacesEndpoint, nil, owner.Token, &workspaces); status != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", status)
+		}
+		if len(workspaces) != 2 || !workspaces[0].Personal || workspaces[0].ID != owner.User.ID || workspaces[1].ID != workspace.ID {
+			t.Errorf("Expected the personal workspace and %s, got %+v", workspace.ID, workspaces)
+		}
+	})
+
+	t.Run("Not a member", func(t *testing.T) {
+		if status := inWorkspace(t, "GET", transactionsEndpoint, nil, outsider.Token, workspace.ID, nil); status != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", status)
+		}
+		if status := decodeInto(t, "GET", workspacePath+"/members", nil, outsider.Token, nil); status != http.StatusNotFound {
+			t.Errorf("Expected status 404 listing members, got %d", status)
+		}
+	})
+
+	if status := inWorkspace(t, "POST", transactionsEndpoint, expense, owner.Token, workspace.ID, nil); status != http.StatusCreated {
+		t.Fatalf("Expected status 201 creating a shared transaction, got %d", status)
+	}
+
+	invitation := map[string]any{"email": workspaceMemberEmail, "role": "viewer"}
+	if status := decodeInto(t, "POST", workspacePath+"/invitations", invitation, owner.Token, nil); status != http.StatusCreated {
+		t.Fatalf("Expected status 201 inviting, got %d", status)
+	}
+	token := mailedToken(t, workspaceMemberEmail)
+
+	t.Run("Accept with another email", func(t *testing.T) {
+		if status := decodeInto(t, "POST", acceptInvitationEndpoint, map[string]any{"token": token}, outsider.Token, nil); status != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d", status)
+		}
+	})
+
+	var joined Workspace
+	if status := decodeInto(t, "POST", acceptInvitationEndpoint, map[string]any{"token": token}, member.Token, &joined); status != http.StatusOK {
+		t.Fatalf("Expected status 200 accepting, got %d", status)
+	}
+	if joined.ID != workspace.ID || joined.Role != "viewer" {
+		t.Fatalf("Expected to join %s as viewer, got %+v", workspace.ID, joined)
+	}
+
+	t.Run("Accept twice", func(t *testing.T) {
+		if status := decodeInto(t, "POST", acceptInvitationEndpoint, map[string]any{"token": token}, member.Token, nil); status != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", status)
+		}
+	})
+
+	t.Run("Viewer", func(t *testing.T) {
+		var shared PaginatedResponse
+		if status := inWorkspace(t, "GET", transactionsEndpoint, nil, member.Token, workspace.ID, &shared); status != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", status)
+		}
+		if len(shared.Data) != 1 || shared.Data[0].Description != sharedExpenseDesc {
+			t.Errorf("Expected the shared transaction, got %+v", shared.Data)
+		}
+
+		var personal PaginatedResponse
+		if status := decodeInto(t, "GET", transactionsEndpoint, nil, member.Token, &personal); status != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", status)
+		}
+		if len(personal.Data) != 0 {
+			t.Errorf("Expected no transactions in the personal workspace, got %+v", personal.Data)
+		}
+
+		if status := inWorkspace(t, "POST", transactionsEndpoint, expense, member.Token, workspace.ID, nil); status != http.StatusForbidden {
+			t.Errorf("Expected status 403 writing as viewer, got %d", status)
+		}
+		writes := []struct{ method, path string }{
+			{"PUT", allocationTargetsEndpoint},
+			{"POST", loansEndpoint},
+			{"POST", netWorthSnapshotsEndpoint},
+			{"POST", recurringEndpoint},
+			{"POST", insightsEndpoint + "/sweep"},
+		}
+		for _, write := range writes {
+			if status := inWorkspace(t, write.method, write.path, map[string]any{}, member.Token, workspace.ID, nil); status != http.StatusForbidden {
+				t.Errorf("Expected status 403 on %s %s as viewer, got %d", write.method, write.path, status)
+			}
+		}
+		if status := inWorkspace(t, "GET", transactionsEndpoint, nil, member.Token, workspace.ID, &shared); status != http.StatusOK || len(shared.Data) != 1 {
+			t.Errorf("Expected the viewer's writes to leave the shared transaction alone, got %d %+v", status, shared.Data)
+		}
+		if status := decodeInto(t, "POST", workspacePath+"/invitations", invitation, member.Token, nil); status != http.StatusForbidden {
+			t.Errorf("Expected status 403 inviting as viewer, got %d", status)
+		}
+	})
+
+	t.Run("Members", func(t *testing.T) {
+		var members []WorkspaceMember
+		if status := decodeInto(t, "GET", workspacePath+"/members", nil, member.Token, &members); status != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", status)
+		}
+		if len(members) != 2 || members[0].Email != workspaceOwnerEmail || members[1].Role != "viewer" {
+			t.Errorf("Expected the owner and the viewer, got %+v", members)
+		}
+	})
+
+	t.Run("Editor", func(t *testing.T) {
+		body := map[string]any{"role": "editor"}
+		if status := decodeInto(t, "PUT", workspacePath+"/members/"+member.User.ID, body, owner.Token, nil); status != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", status)
+		}
+		if status := inWorkspace(t, "POST", transactionsEndpoint, expense, member.Token, workspace.ID, nil); status != http.StatusCreated {
+			t.Errorf("Expected status 201 writing as editor, got %d", status)
+		}
+	})
+
+	t.Run("Owners", func(t *testing.T) {
+		if status := decodeInto(t, "DELETE", workspacePath+"/members/"+owner.User.ID, nil, owner.Token, nil); status != http.StatusConflict {
+			t.Errorf("Expected status 409 removing the last owner, got %d", status)
+		}
+		personalPath := workspacesEndpoint + "/" + owner.User.ID
+		if status := decodeInto(t, "DELETE", personalPath, nil, owner.Token, nil); status != http.StatusConflict {
+			t.Errorf("Expected status 409 deleting the personal workspace, got %d", status)
+		}
+		if status := decodeInto(t, "POST", deleteRequestEndpoint, map[string]any{"password": testPassword}, owner.Token, nil); status != http.StatusConflict {
+			t.Errorf("Expected status 409 deleting the only owner of a shared workspace, got %d", status)
+		}
+	})
+
+	t.Run("Leave", func(t *testing.T) {
+		if status := decodeInto(t, "DELETE", workspacePath+"/members/"+member.User.ID, nil, member.Token, nil); status != http.StatusNoContent {
+			t.Fatalf("Expected status 204, got %d", status)
+		}
+		if status := inWorkspace(t, "GET", transactionsEndpoint, nil, member.Token, workspace.ID, nil); status != http.StatusNotFound {
+			t.Errorf("Expected status 404 after leaving, got %d", status)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		if status := decodeInto(t, "DELETE", workspacePath, nil, owner.Token, nil); status != http.StatusNoContent {
+			t.Fatalf("Expected status 204, got %d", status)
+		}
+		if status := inWorkspace(t, "GET", transactionsEndpoint, nil, owner.Token, workspace.ID, nil); status != http.StatusNotFound {
+			t.Errorf("Expected status 404 after deleting, got %d", status)
+		}
+	})
+}
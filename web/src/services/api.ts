@@ -15,7 +15,7 @@ export interface Transaction {
   date: string;
   categoryId?: string;
   category?: Category;
-  userId?: string;
+  workspaceId?: string;
   createdAt?: string;
   updatedAt?: string;
 }
@@ -28,7 +28,7 @@ export interface Investment {
   monthlyReturn: number;
   date: string;
   type?: string;
-  userId?: string;
+  workspaceId?: string;
   createdAt?: string;
   updatedAt?: string;
 }